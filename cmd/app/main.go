@@ -2,17 +2,110 @@ package main
 
 import (
 	"flag"
+	"fmt"
 	"os"
+	"strings"
 
 	"github.com/artemlive/gh-crossplane/internal/app"
+	"github.com/artemlive/gh-crossplane/internal/cli"
+	"github.com/artemlive/gh-crossplane/internal/keymap"
+	"github.com/artemlive/gh-crossplane/internal/layout"
+	"github.com/artemlive/gh-crossplane/internal/manifest"
+	"github.com/artemlive/gh-crossplane/internal/policy"
+	"github.com/artemlive/gh-crossplane/internal/presets"
+	"github.com/artemlive/gh-crossplane/internal/schema"
+	"github.com/artemlive/gh-crossplane/internal/ui/style"
 	tea "github.com/charmbracelet/bubbletea/v2"
 )
 
 func main() {
-	groupsDir := flag.String("groups-dir", "flux/resources/github/management/repositories", "Path to the directory with RepositoriesGroup YAMLs")
+	if len(os.Args) > 1 {
+		if cmd := cli.Lookup(os.Args[1]); cmd != nil {
+			if err := cmd.Run(os.Args[2:]); err != nil {
+				if err != flag.ErrHelp {
+					fmt.Fprintln(os.Stderr, "Error:", err)
+				}
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: gh crossplane [command] [flags]\n\n")
+		cli.PrintCommands(flag.CommandLine.Output())
+		fmt.Fprintf(flag.CommandLine.Output(), "\nWithout a command the interactive editor is started.\n\nFlags:\n")
+		flag.PrintDefaults()
+	}
+	groupsDir := flag.String("groups-dir", cli.DefaultGroupsDir, "Path to the directory with RepositoriesGroup YAMLs")
+	backup := flag.Bool("backup", false, "Keep a .bak copy of the previous content when saving a group file")
+	xrdPath := flag.String("xrd", "", "Path to the XRD of the groups, its schema builds a form of the fields the built-in editor doesn't know")
+	policiesPath := flag.String("policies", "", "Path to the policy file (defaults to "+policy.FileName+" next to the groups dir)")
+	presetsDir := flag.String("presets", "", "Path to the directory with the group and repository presets (defaults to "+presets.DirName+" next to the groups dir)")
+	layoutPath := flag.String("layout", "", "Path to the layout of the editor tabs (defaults to "+layout.FileName+" next to the groups dir)")
+	theme := flag.String("theme", style.ThemeAuto, "Color theme: "+strings.Join(style.ThemeNames, ", ")+" ("+style.ThemeAuto+" follows the background of the terminal)")
+	keysPath := flag.String("keys", "", "Path to the key bindings (defaults to "+keymap.FileName+" next to the groups dir)")
 	flag.Parse()
-	p := tea.NewProgram(app.NewAppModel(*groupsDir), tea.WithAltScreen())
-	if _, err := p.Run(); err != nil {
+
+	opts := app.Options{GroupsDir: *groupsDir, Backup: *backup}
+	if *xrdPath != "" {
+		xrd, err := schema.LoadXRD(*xrdPath)
+		if err != nil {
+			fail(err)
+		}
+		opts.XRD = xrd
+	}
+	if *policiesPath == "" {
+		*policiesPath = manifest.ConfigPath(*groupsDir, policy.FileName)
+	}
+	policies, err := policy.Load(*policiesPath)
+	if err != nil {
+		fail(err)
+	}
+	if len(policies.Rules) > 0 {
+		opts.Policies = policies
+	}
+
+	if *presetsDir == "" {
+		*presetsDir = manifest.ConfigPath(*groupsDir, presets.DirName)
+	}
+	if opts.Presets, err = presets.Load(*presetsDir); err != nil {
+		fail(err)
+	}
+
+	if *layoutPath == "" {
+		*layoutPath = manifest.ConfigPath(*groupsDir, layout.FileName)
+	}
+	if opts.Layout, err = layout.Load(*layoutPath); err != nil {
+		fail(err)
+	}
+
+	if *keysPath == "" {
+		*keysPath = manifest.ConfigPath(*groupsDir, keymap.FileName)
+	}
+	keys, err := keymap.Load(*keysPath)
+	if err != nil {
+		fail(err)
+	}
+	opts.Keys = &keys
+
+	if _, err := style.Lookup(*theme, true); err != nil {
+		fail(err)
+	}
+	opts.Theme = *theme
+
+	m := app.NewAppModel(opts)
+	p := tea.NewProgram(m, tea.WithAltScreen())
+	_, err = p.Run()
+	if err := m.Close(); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+	}
+	if err != nil {
 		os.Exit(1)
 	}
 }
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "Error:", err)
+	os.Exit(1)
+}
@@ -4,64 +4,269 @@ import (
 	"fmt"
 
 	"github.com/artemlive/gh-crossplane/debug"
+	"github.com/artemlive/gh-crossplane/internal/checks"
+	"github.com/artemlive/gh-crossplane/internal/directory"
 	"github.com/artemlive/gh-crossplane/internal/domain"
+	"github.com/artemlive/gh-crossplane/internal/git"
+	"github.com/artemlive/gh-crossplane/internal/github"
+	"github.com/artemlive/gh-crossplane/internal/gitops"
+	"github.com/artemlive/gh-crossplane/internal/keymap"
+	"github.com/artemlive/gh-crossplane/internal/layout"
 	"github.com/artemlive/gh-crossplane/internal/manifest"
+	"github.com/artemlive/gh-crossplane/internal/policy"
+	"github.com/artemlive/gh-crossplane/internal/presets"
+	"github.com/artemlive/gh-crossplane/internal/schema"
+	"github.com/artemlive/gh-crossplane/internal/ui/screens/bulkedit"
 	"github.com/artemlive/gh-crossplane/internal/ui/screens/configuregroup"
 	"github.com/artemlive/gh-crossplane/internal/ui/screens/createrepo"
+	"github.com/artemlive/gh-crossplane/internal/ui/screens/findrepo"
+	"github.com/artemlive/gh-crossplane/internal/ui/screens/importrepos"
 	"github.com/artemlive/gh-crossplane/internal/ui/screens/menu"
+	"github.com/artemlive/gh-crossplane/internal/ui/screens/newgroup"
 	"github.com/artemlive/gh-crossplane/internal/ui/screens/selectgroup"
+	"github.com/artemlive/gh-crossplane/internal/ui/screens/xrdform"
 	ui "github.com/artemlive/gh-crossplane/internal/ui/shared"
+	"github.com/artemlive/gh-crossplane/internal/ui/style"
+	"github.com/charmbracelet/bubbles/v2/help"
+	"github.com/charmbracelet/bubbles/v2/key"
 	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
 )
 
 type appState struct {
 	manifestLoader *manifest.ManifestLoader
+	watcher        *manifest.Watcher
+	repo           git.Repository
+	github         github.API
+	directory      *directory.Cache
+	checks         *checks.Cache
+	// xrd builds the form of the fields the domain types don't know, nil if not given
+	xrd      *schema.XRD
+	policies *policy.File
+	presets  []presets.Preset
+	layout   *layout.File
+	// theme is the name of the theme, picked again once the background of the terminal is known
+	theme string
 }
 
 func (m *appState) GetManifestLoader() *manifest.ManifestLoader {
 	return m.manifestLoader
 }
 
+// defaultOwner guesses the GitHub organization from the remote of the GitOps repository
+func (m *appState) defaultOwner() string {
+	if m.repo == nil {
+		return ""
+	}
+	owner, _, err := gitops.RemoteRepository(m.repo)
+	if err != nil {
+		return ""
+	}
+	return owner
+}
+
+func (m *appState) Services() ui.Services {
+	return ui.Services{
+		Loader:    m.manifestLoader,
+		Repo:      m.repo,
+		GitHub:    m.github,
+		Directory: m.directory,
+		Checks:    m.checks,
+		Policies:  m.policies,
+		Presets:   m.presets,
+		Layout:    m.layout,
+		XRD:       m.xrd,
+	}
+}
+
 type model struct {
 	curScreen ui.ViewableModel
 	state     appState
+	// help are the keys of the screen shown over it, nil if the help is closed
+	help [][]key.Binding
 
 	message ui.Message
 	width   int
 	height  int
 }
 
-func NewAppModel(groupDir string) model {
+// Options configure the interactive editor
+type Options struct {
+	GroupsDir string
+	// Backup keeps a .bak copy of the previous content on every save
+	Backup bool
+	// XRD builds the form of the fields the domain types don't know, nil if not given
+	XRD *schema.XRD
+	// Policies are the org-wide rules the groups are checked against, nil if there are none
+	Policies *policy.File
+	// Presets are the templates new groups and repositories start from
+	Presets []presets.Preset
+	// Layout arranges the fields of the editor, nil for the built-in layout
+	Layout *layout.File
+	// Keys replace the default key bindings, nil for the defaults
+	Keys *keymap.KeyMap
+	// Theme is the name of the color theme, empty for the theme matching the terminal
+	Theme string
+}
+
+func NewAppModel(opts Options) model {
+	groupDir := opts.GroupsDir
 	state := appState{
 		manifestLoader: manifest.NewManifestLoader(groupDir),
+		xrd:            opts.XRD,
+		policies:       opts.Policies,
+		presets:        opts.Presets,
+		layout:         opts.Layout,
+		theme:          opts.Theme,
+	}
+	state.manifestLoader.SetBackup(opts.Backup)
+	if opts.Keys != nil {
+		keymap.Keys = *opts.Keys
+	}
+	// most terminals are dark, the theme is fixed once the terminal tells its background
+	if theme, err := style.Lookup(opts.Theme, true); err == nil {
+		style.Apply(theme)
+	}
+
+	watcher, err := manifest.NewWatcher(groupDir)
+	if err != nil {
+		// live reload is optional, the app works fine without it
+		debug.Log.Printf("Failed to watch %s: %v", groupDir, err)
+	}
+	state.watcher = watcher
+
+	if repo, err := git.Open(groupDir); err == nil {
+		state.repo = repo
+	} else {
+		debug.Log.Printf("Git integration is disabled: %v", err)
+	}
+
+	if client, err := github.New(); err == nil {
+		state.github = client
+	} else {
+		debug.Log.Printf("GitHub integration is disabled: %v", err)
 	}
 
+	state.directory, state.checks = newCaches(state.defaultOwner(), state.github)
+
 	return model{
 		state:     state,
 		curScreen: menu.NewMenuModel(),
 	}
 }
 
+// Close stops watching the groups dir, it's called once the program exits
+func (m model) Close() error {
+	if m.state.watcher == nil {
+		return nil
+	}
+	return m.state.watcher.Close()
+}
+
 func switchToMenu() tea.Cmd {
 	return func() tea.Msg {
 		return ui.SwitchToMenuMsg{}
 	}
 }
 
+// waitForGroupChanges blocks until the watcher reports changed files
+func waitForGroupChanges(w *manifest.Watcher) tea.Cmd {
+	if w == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		paths, ok := <-w.Changes()
+		if !ok {
+			return nil
+		}
+		return ui.GroupFilesChangedMsg{Paths: paths}
+	}
+}
+
+// newCaches returns the cached lookups of the org used for the completions,
+// nil if the org is unknown
+func newCaches(org string, gh github.API) (*directory.Cache, *checks.Cache) {
+	if org == "" {
+		return nil, nil
+	}
+	cacheDir, err := directory.DefaultCacheDir()
+	if err != nil {
+		debug.Log.Printf("Completions are disabled: %v", err)
+		return nil, nil
+	}
+	return directory.NewCache(cacheDir, org, gh), checks.NewCache(cacheDir, org, gh)
+}
+
+// loadCaches reads the cached lookups, then refreshes the directory from GitHub
+// in the background, so the completion works offline.
+// The status checks are refreshed per group when it's opened.
+func loadCaches(d *directory.Cache, c *checks.Cache) tea.Cmd {
+	if d == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		if err := c.Load(); err != nil {
+			debug.Log.Printf("Failed to read the cached status checks: %v", err)
+		}
+		if err := d.Load(); err != nil {
+			debug.Log.Printf("Failed to read the cached directory: %v", err)
+		}
+		if err := d.Refresh(); err != nil {
+			debug.Log.Printf("Failed to refresh the directory, using the cached one: %v", err)
+		}
+		return nil
+	}
+}
+
 func (m model) Init() tea.Cmd {
-	return switchToMenu()
+	return tea.Batch(switchToMenu(), tea.RequestBackgroundColor, waitForGroupChanges(m.state.watcher), loadCaches(m.state.directory, m.state.checks))
 }
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case tea.BackgroundColorMsg:
+		if theme, err := style.Lookup(m.state.theme, msg.IsDark()); err == nil {
+			debug.Log.Printf("Using the %s theme, dark background: %t", theme.Name, msg.IsDark())
+			style.Apply(theme)
+		}
+		return m, nil
 	case ui.SwitchToMenuMsg:
 		menu := menu.NewMenuModel()
 		m.curScreen = menu
 		return m, menu.Init()
 	case ui.SwitchToCreateRepoMsg:
-		createRepoModel := createrepo.NewCreateRepoModel()
+		createRepoModel := createrepo.NewCreateRepoModel(m.state.Services(), m.state.defaultOwner())
 		m.curScreen = createRepoModel
 		return m, createRepoModel.Init()
+	case ui.SwitchToImportMsg:
+		importModel := importrepos.NewImportModel(m.state.Services(), m.state.defaultOwner(), m.width, m.height)
+		m.curScreen = importModel
+		return m, importModel.Init()
+	case ui.SwitchToBulkEditMsg:
+		bulkEditModel := bulkedit.NewBulkEditModel(m.state.Services(), m.width, m.height)
+		m.curScreen = bulkEditModel
+		return m, bulkEditModel.Init()
+	case ui.SwitchToSearchMsg:
+		findRepoModel := findrepo.NewFindRepoModel(m.state.GetManifestLoader().Groups(), findrepo.ModeSearch, m.width, m.height)
+		m.curScreen = findRepoModel
+		return m, findRepoModel.Init()
+	case ui.SwitchToRepoPickerMsg:
+		findRepoModel := findrepo.NewFindRepoModel(m.state.GetManifestLoader().Groups(), findrepo.ModeConfigure, m.width, m.height)
+		m.curScreen = findRepoModel
+		return m, findRepoModel.Init()
+	case ui.SwitchToConfigureRepoMsg:
+		group := m.state.GetManifestLoader().GetGroup(msg.GroupName)
+		if group == nil {
+			m.message = ui.ErrorMessage(fmt.Sprintf("Group '%s' not found", msg.GroupName))
+			return m, nil
+		}
+		configureRepoModel, err := configuregroup.NewRepositoryEditor(group, msg.RepoName, m.state.Services(), m.width, m.height)
+		if err != nil {
+			m.message = ui.ErrorMessage(err.Error())
+			return m, nil
+		}
+		m.curScreen = configureRepoModel
+		return m, configureRepoModel.Init()
 	case ui.SwitchToSelectGroupMsg:
 		// pass the width and height to the selectGroup model
 		// because it needs to know the size of the terminal
@@ -70,6 +275,9 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			Name:        msg.RepoName,
 			Description: msg.Description,
 		}
+		if p := presets.Find(m.state.presets, msg.Preset); p != nil {
+			repo = p.NewRepository(msg.RepoName, msg.Description)
+		}
 		selectGroupModel := selectgroup.NewSelectGroupModel(m.state.GetManifestLoader().Groups(), repo, m.width, m.height)
 		m.curScreen = selectGroupModel
 		return m, selectGroupModel.Init()
@@ -81,13 +289,63 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.message = ui.ErrorMessage(fmt.Sprintf("Group '%s' not found", groupName))
 			return m, nil
 		}
-		configureGroupModel := configuregroup.NewConfigureGroupModel(group, m.state.manifestLoader, m.width, m.height)
+		configureGroupModel := configuregroup.NewConfigureGroupModel(group, m.state.Services(), m.width, m.height)
+		if msg.Repo != nil {
+			configureGroupModel.AddRepository(*msg.Repo)
+		}
+		if msg.RepoName != "" && !configureGroupModel.FocusRepository(msg.RepoName) {
+			m.message = ui.ErrorMessage(fmt.Sprintf("Repository '%s' not found in group '%s'", msg.RepoName, groupName))
+		}
 		m.curScreen = configureGroupModel
 		return m, configureGroupModel.Init()
+	case ui.SwitchToXRDFormMsg:
+		group := m.state.GetManifestLoader().GetGroup(msg.GroupName)
+		if group == nil {
+			m.message = ui.ErrorMessage(fmt.Sprintf("Group '%s' not found", msg.GroupName))
+			return m, nil
+		}
+		form, err := xrdform.New(group, m.state.xrd, m.state.GetManifestLoader(), m.width, m.height)
+		if err != nil {
+			m.message = ui.ErrorMessage(fmt.Sprintf("Can't build the form of group '%s': %v", msg.GroupName, err))
+			return m, nil
+		}
+		m.curScreen = form
+		return m, form.Init()
+	case ui.SwitchToNewGroupMsg:
+		newGroupModel := newgroup.NewNewGroupModel(m.state.Services(), msg.Repo)
+		m.curScreen = newGroupModel
+		return m, newGroupModel.Init()
+	case ui.GroupFilesChangedMsg:
+		next := waitForGroupChanges(m.state.watcher)
+		changed, err := m.state.GetManifestLoader().Reload(msg.Paths)
+		if err != nil {
+			debug.Log.Printf("Failed to reload groups: %v", err)
+		}
+		if len(changed) == 0 {
+			return m, next
+		}
+		reloaded := ui.GroupsReloadedMsg{Paths: changed, Groups: m.state.GetManifestLoader().Groups()}
+		model, cmd := m.curScreen.Update(reloaded)
+		if screen, ok := model.(ui.ViewableModel); ok {
+			m.curScreen = screen
+		}
+		return m, tea.Batch(cmd, next)
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "ctrl+c":
+		keys := keymap.Keys
+		switch {
+		case key.Matches(msg, keys.ForceQuit):
 			return m, tea.Quit
+		case m.help != nil:
+			// the screen doesn't get the keys while the help is shown over it
+			if key.Matches(msg, keys.Help, keys.Back, keys.Quit) {
+				m.help = nil
+			}
+			return m, nil
+		case key.Matches(msg, keys.Help):
+			if groups := m.keyHelp(msg); groups != nil {
+				m.help = groups
+				return m, nil
+			}
 		}
 	case tea.WindowSizeMsg:
 		// I save this to pass the size to the selectGroup model
@@ -113,5 +371,41 @@ func (m model) View() (string, *tea.Cursor) {
 	}
 
 	debug.Log.Printf("Rendering screen: %T", m.curScreen)
-	return m.curScreen.View()
+	view, cursor := m.curScreen.View()
+	if m.help != nil {
+		return m.renderHelp(view), nil
+	}
+	return view, cursor
+}
+
+// keyHelp returns the keys of the current screen for the help overlay,
+// nil if the screen has none or the key is typed into a text input
+func (m model) keyHelp(msg tea.KeyMsg) [][]key.Binding {
+	h, ok := m.curScreen.(ui.KeyHelper)
+	if !ok {
+		return nil
+	}
+	groups, typing := h.KeyHelp()
+	if len(groups) == 0 || (typing && msg.Key().Text != "") {
+		return nil
+	}
+	return append(groups, []key.Binding{keymap.Describe(keymap.Keys.Help, "close the help")})
+}
+
+// renderHelp draws the keys of the screen in a box over it
+func (m model) renderHelp(screen string) string {
+	h := help.New()
+	h.Styles = style.HelpStyles()
+	h.ShowAll = true
+	if m.width > 0 {
+		// the box has a border and a padding
+		h.Width = max(m.width-8, 20)
+	}
+	box := style.HelpBoxStyle.Render(ui.JoinVertical([]string{style.LabelStyle.Render("Keys"), "", h.FullHelpView(m.help)}))
+	x := max(0, (m.width-lipgloss.Width(box))/2)
+	y := max(0, (m.height-lipgloss.Height(box))/2)
+	return lipgloss.NewCanvas(
+		lipgloss.NewLayer(screen),
+		lipgloss.NewLayer(box).X(x).Y(y).Z(1),
+	).Render()
 }
@@ -0,0 +1,247 @@
+// Package bulk sets a field in many groups or repositories at once,
+// e.g. "deleteBranchOnMerge: true" in every group labeled team=platform.
+//
+// The groups are edited as yaml documents, so the comments and the fields
+// the domain types don't know about are kept, see the dynamic package.
+package bulk
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/artemlive/gh-crossplane/internal/dynamic"
+	"github.com/artemlive/gh-crossplane/internal/manifest"
+	"github.com/artemlive/gh-crossplane/internal/schema"
+	"github.com/artemlive/gh-crossplane/internal/versions"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrNoChanges is returned by Plan when the selected groups already have the value
+var ErrNoChanges = errors.New("the selected groups already have the value")
+
+// Selector chooses the groups and repositories an edit applies to
+type Selector struct {
+	Groups []string          // group name patterns, e.g. "platform-*", all groups if empty
+	Labels map[string]string // labels the groups must have
+	// Repos are repository name patterns. If set, the field is set on the matching
+	// repositories of the selected groups instead of the group spec.
+	Repos []string
+}
+
+// Edit sets the field to the value in everything the selector matches
+type Edit struct {
+	Selector Selector
+	// Field is the dotted yaml path of the field in the spec or in the repository,
+	// e.g. "deleteBranchOnMerge"
+	Field string
+	// Value is the text form of the value, lists are comma separated.
+	// An empty value removes the field.
+	Value string
+}
+
+// Change is the edit of a single group file
+type Change struct {
+	Group manifest.GroupFile
+	Doc   *yaml.Node // the edited document
+	Repos []string   // the repositories changed, empty if the group spec was changed
+	Diff  []manifest.DiffLine
+}
+
+// ParseList splits a comma separated list, skipping the empty items
+func ParseList(s string) []string {
+	var out []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// ParseLabels parses comma separated key=value pairs
+func ParseLabels(s string) (map[string]string, error) {
+	items := ParseList(s)
+	if len(items) == 0 {
+		return nil, nil
+	}
+	out := make(map[string]string, len(items))
+	for _, item := range items {
+		k, v, ok := strings.Cut(item, "=")
+		if !ok || strings.TrimSpace(k) == "" {
+			return nil, fmt.Errorf("label %q is not key=value", item)
+		}
+		out[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+	return out, nil
+}
+
+// MatchGroup reports whether the group is selected
+func (s Selector) MatchGroup(gf manifest.GroupFile) bool {
+	name := gf.Manifest.Metadata.Name
+	if len(s.Groups) > 0 && !matchAny(s.Groups, name) {
+		return false
+	}
+	for k, v := range s.Labels {
+		if gf.Manifest.Metadata.Labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+func matchAny(patterns []string, name string) bool {
+	return slices.ContainsFunc(patterns, func(p string) bool {
+		ok, _ := path.Match(p, name)
+		return ok
+	})
+}
+
+// Plan computes the changes of the edit, nothing is written.
+// Only the groups which would change are returned.
+func Plan(groups []manifest.GroupFile, e Edit) ([]Change, error) {
+	for _, p := range append(slices.Clone(e.Selector.Groups), e.Selector.Repos...) {
+		if _, err := path.Match(p, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", p, err)
+		}
+	}
+	fieldPath := strings.Split(strings.TrimPrefix(strings.TrimSpace(e.Field), "spec."), ".")
+	if fieldPath[0] == "" {
+		return nil, errors.New("no field given")
+	}
+
+	var out []Change
+	selected := 0
+	for _, gf := range groups {
+		if !e.Selector.MatchGroup(gf) {
+			continue
+		}
+		selected++
+		c, err := plan(gf, e, fieldPath)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", gf.Path, err)
+		}
+		if c != nil {
+			out = append(out, *c)
+		}
+	}
+	switch {
+	case selected == 0:
+		return nil, errors.New("no groups match the selection")
+	case len(out) == 0:
+		return nil, ErrNoChanges
+	}
+	return out, nil
+}
+
+func plan(gf manifest.GroupFile, e Edit, fieldPath []string) (*Change, error) {
+	spec, err := versions.SpecSchema(versions.Of(gf.Manifest.APIVersion))
+	if err != nil {
+		return nil, err
+	}
+	before, err := gf.Node()
+	if err != nil {
+		return nil, err
+	}
+	doc, err := gf.Node()
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Change{Group: gf, Doc: doc}
+	if len(e.Selector.Repos) == 0 {
+		f, err := field(spec, fieldPath)
+		if err != nil {
+			return nil, err
+		}
+		f.Path = append([]string{"spec"}, f.Path...)
+		if err := dynamic.Set(doc, f, e.Value); err != nil {
+			return nil, err
+		}
+	} else {
+		items := spec.Properties["repositories"]
+		if items == nil || items.Items == nil {
+			return nil, errors.New("the schema has no repositories")
+		}
+		f, err := field(items.Items, fieldPath)
+		if err != nil {
+			return nil, err
+		}
+		repos := dynamic.Lookup(doc, []string{"spec", "repositories"})
+		if repos == nil || repos.Kind != yaml.SequenceNode {
+			return nil, nil
+		}
+		for _, r := range repos.Content {
+			name := dynamic.Text(r, dynamic.Field{Path: []string{"name"}})
+			if r.Kind != yaml.MappingNode || !matchAny(e.Selector.Repos, name) {
+				continue
+			}
+			old := dynamic.Text(r, f)
+			if err := dynamic.Set(r, f, e.Value); err != nil {
+				return nil, err
+			}
+			if dynamic.Text(r, f) != old {
+				c.Repos = append(c.Repos, name)
+			}
+		}
+	}
+
+	if c.Diff, err = manifest.DiffNode(before, doc); err != nil {
+		return nil, err
+	}
+	if len(c.Diff) == 0 {
+		return nil, nil
+	}
+	return c, nil
+}
+
+// field returns the editable field at the path of the object schema
+func field(s *schema.Schema, fieldPath []string) (dynamic.Field, error) {
+	name := strings.Join(fieldPath, ".")
+	for _, f := range dynamic.Fields(s) {
+		if f.Name() != name {
+			continue
+		}
+		if !f.Editable() {
+			return f, fmt.Errorf("field %s can't be set in bulk, only single values and lists of values can", name)
+		}
+		return f, nil
+	}
+	return dynamic.Field{}, fmt.Errorf("unknown field %s", name)
+}
+
+// Apply writes the changed group files. It stops at the first file which can't
+// be written, the files written before it stay changed.
+func Apply(loader *manifest.ManifestLoader, changes []Change) error {
+	for i := range changes {
+		c := &changes[i]
+		if err := loader.SaveGroupNode(&c.Group, c.Doc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Groups returns the changed groups, after Apply they hold the written manifests
+func Groups(changes []Change) []manifest.GroupFile {
+	groups := make([]manifest.GroupFile, len(changes))
+	for i, c := range changes {
+		groups[i] = c.Group
+	}
+	return groups
+}
+
+// Summary describes the changes, e.g. "3 group(s), 12 repo(s)"
+func Summary(changes []Change) string {
+	repos := 0
+	for _, c := range changes {
+		repos += len(c.Repos)
+	}
+	s := fmt.Sprintf("%d group(s)", len(changes))
+	if repos > 0 {
+		s += fmt.Sprintf(", %d repo(s)", repos)
+	}
+	return s
+}
@@ -0,0 +1,164 @@
+package checks
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/artemlive/gh-crossplane/internal/github"
+)
+
+// MaxAge is how long the contexts of a repository are used before they are fetched again
+const MaxAge = time.Hour
+
+// RepoChecks are the status check contexts reported on the default branch of a repository
+type RepoChecks struct {
+	Branch    string    `json:"branch"`
+	Contexts  []string  `json:"contexts"`
+	FetchedAt time.Time `json:"fetchedAt"`
+}
+
+// Fetch collects the names of the check runs and the commit statuses of the latest
+// commit on the branch. An empty branch means the default branch of the repository.
+func Fetch(gh github.Checks, owner, repo, branch string, now time.Time) (RepoChecks, error) {
+	if branch == "" {
+		r, err := gh.GetRepository(owner, repo)
+		if err != nil {
+			return RepoChecks{}, fmt.Errorf("get repository %s/%s: %w", owner, repo, err)
+		}
+		branch = r.DefaultBranch
+	}
+
+	runs, err := gh.ListCheckRuns(owner, repo, branch)
+	if err != nil {
+		return RepoChecks{}, fmt.Errorf("list check runs of %s/%s@%s: %w", owner, repo, branch, err)
+	}
+	statuses, err := gh.ListCommitStatuses(owner, repo, branch)
+	if err != nil {
+		return RepoChecks{}, fmt.Errorf("list commit statuses of %s/%s@%s: %w", owner, repo, branch, err)
+	}
+
+	out := RepoChecks{Branch: branch, FetchedAt: now}
+	for _, r := range runs {
+		out.Contexts = append(out.Contexts, r.Name)
+	}
+	for _, s := range statuses {
+		out.Contexts = append(out.Contexts, s.Context)
+	}
+	slices.Sort(out.Contexts)
+	out.Contexts = slices.Compact(out.Contexts)
+	return out, nil
+}
+
+// Cache keeps the contexts of the repositories of an organization on disk,
+// so the suggestions work offline. The methods are safe for concurrent use
+// and on a nil Cache, which knows nothing.
+type Cache struct {
+	mu    sync.RWMutex
+	path  string
+	owner string
+	gh    github.Checks
+	repos map[string]RepoChecks
+}
+
+func NewCache(cacheDir, owner string, gh github.Checks) *Cache {
+	return &Cache{
+		path:  filepath.Join(cacheDir, "checks-"+owner+".json"),
+		owner: owner,
+		gh:    gh,
+		repos: make(map[string]RepoChecks),
+	}
+}
+
+// Load reads the cached contexts from disk, a missing cache is not an error
+func (c *Cache) Load() error {
+	data, err := os.ReadFile(c.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	repos := make(map[string]RepoChecks)
+	if err := json.Unmarshal(data, &repos); err != nil {
+		return fmt.Errorf("parse %s: %w", c.path, err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.repos = repos
+	return nil
+}
+
+// Refresh fetches the contexts of the repositories which weren't fetched
+// within MaxAge and saves them to disk. The branches map the repositories to
+// the branch to look at, the default branch is used for the missing ones.
+// Repositories which fail keep their cached contexts.
+func (c *Cache) Refresh(branches map[string]string, now time.Time) error {
+	if c.gh == nil {
+		return errors.New("GitHub client is not available")
+	}
+
+	var errs []error
+	fetched := false
+	for repo, branch := range branches {
+		c.mu.RLock()
+		cached, ok := c.repos[repo]
+		c.mu.RUnlock()
+		if ok && now.Sub(cached.FetchedAt) < MaxAge && (branch == "" || branch == cached.Branch) {
+			continue
+		}
+
+		rc, err := Fetch(c.gh, c.owner, repo, branch, now)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		c.mu.Lock()
+		c.repos[repo] = rc
+		c.mu.Unlock()
+		fetched = true
+	}
+	if fetched {
+		errs = append(errs, c.save())
+	}
+	return errors.Join(errs...)
+}
+
+func (c *Cache) save() error {
+	c.mu.RLock()
+	data, err := json.MarshalIndent(c.repos, "", "  ")
+	c.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return err
+	}
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, c.path)
+}
+
+// Contexts returns the known contexts of the repositories, sorted and without duplicates
+func (c *Cache) Contexts(repos []string) []string {
+	if c == nil {
+		return nil
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var out []string
+	for _, repo := range repos {
+		out = append(out, c.repos[repo].Contexts...)
+	}
+	slices.Sort(out)
+	return slices.Compact(out)
+}
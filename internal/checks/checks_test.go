@@ -0,0 +1,111 @@
+package checks
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/artemlive/gh-crossplane/internal/github"
+)
+
+// fakeChecks serves the responses recorded in testdata/github and counts the fetched refs
+type fakeChecks struct {
+	github.Checks
+	fetched []string
+}
+
+func newFakeChecks(t *testing.T) *fakeChecks {
+	t.Helper()
+	gh, err := github.NewFixtureClient("testdata/github")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &fakeChecks{Checks: gh}
+}
+
+func (f *fakeChecks) ListCheckRuns(owner, name, ref string) ([]github.CheckRun, error) {
+	f.fetched = append(f.fetched, owner+"/"+name+"@"+ref)
+	return f.Checks.ListCheckRuns(owner, name, ref)
+}
+
+func TestFetch(t *testing.T) {
+	gh := newFakeChecks(t)
+	now := time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	rc, err := Fetch(gh, "acme", "api", "", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := RepoChecks{Branch: "main", Contexts: []string{"build", "ci/jenkins", "lint"}, FetchedAt: now}
+	if rc.Branch != want.Branch || !slices.Equal(rc.Contexts, want.Contexts) || !rc.FetchedAt.Equal(now) {
+		t.Errorf("Fetch = %+v, want %+v", rc, want)
+	}
+
+	rc, err = Fetch(gh, "acme", "web", "develop", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(rc.Contexts, []string{"e2e"}) {
+		t.Errorf("contexts of develop = %v, want [e2e]", rc.Contexts)
+	}
+
+	if _, err := Fetch(gh, "acme", "missing", "", now); err == nil {
+		t.Error("expected an error for a missing repository")
+	}
+}
+
+func TestCache(t *testing.T) {
+	gh := newFakeChecks(t)
+	dir := t.TempDir()
+	now := time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	c := NewCache(dir, "acme", gh)
+	if err := c.Load(); err != nil {
+		t.Fatalf("a missing cache isn't an error: %v", err)
+	}
+	if err := c.Refresh(map[string]string{"api": "", "web": "develop"}, now); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.Contexts([]string{"api", "web"}); !slices.Equal(got, []string{"build", "ci/jenkins", "e2e", "lint"}) {
+		t.Errorf("contexts = %v", got)
+	}
+
+	// the fresh contexts are read from disk and not fetched again
+	gh.fetched = nil
+	cached := NewCache(dir, "acme", gh)
+	if err := cached.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if err := cached.Refresh(map[string]string{"api": "", "web": "develop"}, now.Add(MaxAge/2)); err != nil {
+		t.Fatal(err)
+	}
+	if len(gh.fetched) != 0 {
+		t.Errorf("fetched %v, want the cached contexts", gh.fetched)
+	}
+	if got := cached.Contexts([]string{"web"}); !slices.Equal(got, []string{"e2e"}) {
+		t.Errorf("cached contexts of web = %v, want [e2e]", got)
+	}
+
+	// the old ones and those of another branch are fetched
+	if err := cached.Refresh(map[string]string{"api": "", "web": "main"}, now.Add(MaxAge/2)); err == nil {
+		t.Error("expected the error of the missing web@main fixtures")
+	}
+	if err := cached.Refresh(map[string]string{"api": ""}, now.Add(MaxAge)); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"acme/web@main", "acme/api@main"}; !slices.Equal(gh.fetched, want) {
+		t.Errorf("fetched %v, want %v", gh.fetched, want)
+	}
+	// the failed repository keeps its contexts
+	if got := cached.Contexts([]string{"web"}); !slices.Equal(got, []string{"e2e"}) {
+		t.Errorf("contexts of web after the failed refresh = %v, want [e2e]", got)
+	}
+
+	if got := (*Cache)(nil).Contexts([]string{"api"}); got != nil {
+		t.Errorf("nil cache contexts = %v", got)
+	}
+	if tmp, _ := filepath.Glob(filepath.Join(dir, "*.tmp")); len(tmp) > 0 {
+		t.Errorf("temporary files left: %v", tmp)
+	}
+}
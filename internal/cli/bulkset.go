@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/artemlive/gh-crossplane/internal/bulk"
+	"github.com/artemlive/gh-crossplane/internal/git"
+	"github.com/artemlive/gh-crossplane/internal/gitops"
+)
+
+var bulkSetCommand = &Command{
+	Name:    "bulk-set",
+	Summary: "Set a field in many groups or repositories at once",
+	Run:     runBulkSet,
+}
+
+func runBulkSet(args []string) error {
+	fs, groupsDir := newFlagSet("bulk-set", "[-group <pattern>,...] [-label <key>=<value>,...] [-repo <pattern>,...] [-dry-run] [-commit] <field> [<value>]")
+	groups := fs.String("group", "", "Comma separated group name patterns, e.g. 'platform-*' (defaults to all groups)")
+	labels := fs.String("label", "", "Comma separated key=value labels the groups must have")
+	repos := fs.String("repo", "", "Comma separated repository name patterns, the field is set on the repositories instead of the groups")
+	dryRun := fs.Bool("dry-run", false, "Only print the changes")
+	commit := fs.Bool("commit", false, "Commit the changed files to a new branch")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < 1 || fs.NArg() > 2 {
+		fs.Usage()
+		return errors.New("expected the field and the value, an empty value removes the field")
+	}
+
+	labelSet, err := bulk.ParseLabels(*labels)
+	if err != nil {
+		return err
+	}
+	edit := bulk.Edit{
+		Selector: bulk.Selector{
+			Groups: bulk.ParseList(*groups),
+			Labels: labelSet,
+			Repos:  bulk.ParseList(*repos),
+		},
+		Field: fs.Arg(0),
+		Value: fs.Arg(1),
+	}
+
+	loader, err := loadGroups(*groupsDir)
+	if err != nil {
+		return err
+	}
+	changes, err := bulk.Plan(loader.Groups(), edit)
+	if errors.Is(err, bulk.ErrNoChanges) {
+		fmt.Println("Nothing to change,", err)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, c := range changes {
+		fmt.Printf("--- %s\n", c.Group.Path)
+		for _, l := range c.Diff {
+			fmt.Println(l)
+		}
+		fmt.Println()
+	}
+	if *dryRun {
+		fmt.Printf("%s would be changed\n", bulk.Summary(changes))
+		return nil
+	}
+
+	if err := bulk.Apply(loader, changes); err != nil {
+		return err
+	}
+	fmt.Printf("%s changed\n", bulk.Summary(changes))
+	if !*commit {
+		return nil
+	}
+
+	repo, err := git.Open(*groupsDir)
+	if err != nil {
+		return err
+	}
+	res, err := gitops.CommitGroupChanges(repo, bulk.Groups(changes), time.Now())
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Committed %d file(s) to branch %s\n", len(res.Files), res.Branch)
+	return nil
+}
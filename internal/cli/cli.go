@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/artemlive/gh-crossplane/internal/git"
+	"github.com/artemlive/gh-crossplane/internal/github"
+	"github.com/artemlive/gh-crossplane/internal/gitops"
+	"github.com/artemlive/gh-crossplane/internal/manifest"
+)
+
+// DefaultGroupsDir is where the RepositoriesGroup YAMLs live in the GitOps repository
+const DefaultGroupsDir = "flux/resources/github/management/repositories"
+
+// Command is a non-interactive subcommand, e.g. "gh crossplane import".
+type Command struct {
+	Name    string
+	Summary string
+	Run     func(args []string) error
+}
+
+// Commands lists the available subcommands, anything else starts the TUI.
+var Commands = []*Command{
+	importCommand,
+	driftCommand,
+	renderCommand,
+	schemaCommand,
+	migrateCommand,
+	validateCommand,
+	bulkSetCommand,
+}
+
+// Lookup returns the command with the name, nil if there is no such command.
+func Lookup(name string) *Command {
+	for _, c := range Commands {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// PrintCommands writes the list of commands, used in the usage message.
+func PrintCommands(w io.Writer) {
+	fmt.Fprintln(w, "Commands:")
+	for _, c := range Commands {
+		fmt.Fprintf(w, "  %-10s %s\n", c.Name, c.Summary)
+	}
+}
+
+// newFlagSet creates the flag set of a command with the common -groups-dir flag
+func newFlagSet(name, usage string) (*flag.FlagSet, *string) {
+	fs := newCommandFlagSet(name, usage)
+	groupsDir := fs.String("groups-dir", DefaultGroupsDir, "Path to the directory with RepositoriesGroup YAMLs")
+	return fs, groupsDir
+}
+
+// loadGroups loads the groups of the dir, the files of unsupported versions
+// are reported and skipped
+func loadGroups(groupsDir string) (*manifest.ManifestLoader, error) {
+	loader, err := manifest.Load(groupsDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, err := range loader.Skipped() {
+		fmt.Fprintf(os.Stderr, "Skipping %v\n", err)
+	}
+	return loader, nil
+}
+
+// newCommandFlagSet creates the flag set of a command which doesn't read the groups
+func newCommandFlagSet(name, usage string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: gh crossplane %s %s\n\nFlags:\n", name, usage)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// githubFlags adds the flags selecting where the GitHub data comes from
+func githubFlags(fs *flag.FlagSet) (fixtures, record *string) {
+	fixtures = fs.String("fixtures", "", "Read GitHub responses recorded in the directory instead of calling the API")
+	record = fs.String("record", "", "Record the GitHub responses to the directory, for later use with -fixtures")
+	return fixtures, record
+}
+
+// newGitHubClient returns the client selected by the -fixtures and -record flags
+func newGitHubClient(fixtures, record string) (*github.Client, error) {
+	switch {
+	case fixtures != "" && record != "":
+		return nil, errors.New("-fixtures and -record can't be used together")
+	case fixtures != "":
+		return github.NewFixtureClient(fixtures)
+	case record != "":
+		return github.NewRecordingClient(record)
+	}
+	return github.New()
+}
+
+// defaultOwner guesses the GitHub organization from the remote of the GitOps repository
+func defaultOwner(groupsDir string) string {
+	repo, err := git.Open(groupsDir)
+	if err != nil {
+		return ""
+	}
+	owner, _, err := gitops.RemoteRepository(repo)
+	if err != nil {
+		return ""
+	}
+	return owner
+}
@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"errors"
+	"os"
+
+	"github.com/artemlive/gh-crossplane/internal/drift"
+	"github.com/artemlive/gh-crossplane/internal/manifest"
+)
+
+// errDrift makes the command exit with a non-zero status when -exit-code is set
+var errDrift = errors.New("drift detected")
+
+var driftCommand = &Command{
+	Name:    "drift",
+	Summary: "Compare the manifests with the live repository settings",
+	Run:     runDrift,
+}
+
+func runDrift(args []string) error {
+	fs, groupsDir := newFlagSet("drift", "[-org <org>] [-group <name>]")
+	org := fs.String("org", "", "GitHub organization (defaults to the owner of the GitOps repository)")
+	group := fs.String("group", "", "Only check the repositories of this group")
+	exitCode := fs.Bool("exit-code", false, "Exit with a non-zero status if any repository drifted")
+	fixtures, record := githubFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *org == "" {
+		*org = defaultOwner(*groupsDir)
+	}
+	if *org == "" {
+		return errors.New("-org is required, it can't be taken from the git remote")
+	}
+
+	loader, err := loadGroups(*groupsDir)
+	if err != nil {
+		return err
+	}
+	groups := loader.Groups()
+	if *group != "" {
+		gf := loader.GetGroup(*group)
+		if gf == nil {
+			return errors.New("group " + *group + " not found")
+		}
+		groups = []manifest.GroupFile{*gf}
+	}
+
+	gh, err := newGitHubClient(*fixtures, *record)
+	if err != nil {
+		return err
+	}
+
+	report := drift.Check(gh, *org, groups)
+	if err := drift.Print(os.Stdout, report); err != nil {
+		return err
+	}
+	if *exitCode && len(report.Drifted()) > 0 {
+		return errDrift
+	}
+	return nil
+}
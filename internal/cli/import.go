@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/artemlive/gh-crossplane/internal/importer"
+	"gopkg.in/yaml.v3"
+)
+
+var importCommand = &Command{
+	Name:    "import",
+	Summary: "Import existing GitHub repositories into a group",
+	Run:     runImport,
+}
+
+func runImport(args []string) error {
+	fs, groupsDir := newFlagSet("import", "-group <name> [-org <org>] [-all | <repo>...]")
+	org := fs.String("org", "", "GitHub organization (defaults to the owner of the GitOps repository)")
+	group := fs.String("group", "", "Group to import into, created if it doesn't exist")
+	all := fs.Bool("all", false, "Import all repositories of the organization which aren't managed yet")
+	dryRun := fs.Bool("dry-run", false, "Print the resulting group instead of writing it")
+	fixtures, record := githubFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *group == "" {
+		fs.Usage()
+		return errors.New("-group is required")
+	}
+	if *org == "" {
+		*org = defaultOwner(*groupsDir)
+	}
+	if *org == "" {
+		return errors.New("-org is required, it can't be taken from the git remote")
+	}
+
+	loader, err := loadGroups(*groupsDir)
+	if err != nil {
+		return err
+	}
+	gh, err := newGitHubClient(*fixtures, *record)
+	if err != nil {
+		return err
+	}
+
+	names := fs.Args()
+	if *all {
+		repos, err := gh.ListOrgRepositories(*org)
+		if err != nil {
+			return fmt.Errorf("list repositories of %s: %w", *org, err)
+		}
+		managed := importer.ManagedRepositories(loader.Groups())
+		for _, r := range repos {
+			if _, ok := managed[r.Name]; !ok {
+				names = append(names, r.Name)
+			}
+		}
+	}
+	if len(names) == 0 {
+		return errors.New("no repositories to import")
+	}
+	if err := importer.Validate(loader.Groups(), *group, names); err != nil {
+		return err
+	}
+
+	live, err := importer.Fetch(gh, *org, names)
+	if err != nil {
+		return err
+	}
+	gf, warnings := importer.Plan(loader, *group, live)
+	for _, w := range warnings {
+		fmt.Fprintln(os.Stderr, "warning:", w)
+	}
+
+	if *dryRun {
+		enc := yaml.NewEncoder(os.Stdout)
+		enc.SetIndent(1)
+		defer enc.Close() //nolint:errcheck
+		return enc.Encode(gf.Manifest)
+	}
+	if err := loader.SaveGroupFile(gf); err != nil {
+		return err
+	}
+	fmt.Printf("Imported %s into group %s (%s)\n", strings.Join(names, ", "), *group, gf.Path)
+	return nil
+}
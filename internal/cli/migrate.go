@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/artemlive/gh-crossplane/internal/manifest"
+	"github.com/artemlive/gh-crossplane/internal/versions"
+)
+
+var migrateCommand = &Command{
+	Name:    "migrate",
+	Summary: "Convert the group files to another apiVersion, keeping the comments",
+	Run:     runMigrate,
+}
+
+func runMigrate(args []string) error {
+	fs, groupsDir := newFlagSet("migrate", "[-to <version>] [-group <name>] [-dry-run]")
+	to := fs.String("to", versions.Latest(), "Version to convert to, one of "+strings.Join(versions.Names(), ", "))
+	group := fs.String("group", "", "Only convert this group")
+	dryRun := fs.Bool("dry-run", false, "Only print the files which would be converted")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if !versions.Known(*to) {
+		return fmt.Errorf("unknown version %s, known versions are %s", *to, strings.Join(versions.Names(), ", "))
+	}
+
+	loader, err := loadGroups(*groupsDir)
+	if err != nil {
+		return err
+	}
+	groups := loader.Groups()
+	if *group != "" {
+		gf := loader.GetGroup(*group)
+		if gf == nil {
+			return fmt.Errorf("group %s not found", *group)
+		}
+		groups = []manifest.GroupFile{*gf}
+	}
+
+	converted := 0
+	for _, gf := range groups {
+		from := versions.Of(gf.Manifest.APIVersion)
+		if from == *to {
+			continue
+		}
+		doc, err := gf.Node()
+		if err != nil {
+			return err
+		}
+		if err := versions.Convert(doc, from, *to); err != nil {
+			return fmt.Errorf("%s: %w", gf.Path, err)
+		}
+		fmt.Printf("%s: %s -> %s\n", gf.Path, from, *to)
+		converted++
+		if *dryRun {
+			continue
+		}
+		if err := loader.SaveGroupNode(&gf, doc); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case converted == 0:
+		fmt.Printf("All groups are already %s\n", *to)
+	case *dryRun:
+		fmt.Printf("\n%d files would be converted to %s\n", converted, *to)
+	default:
+		fmt.Printf("\n%d files converted to %s\n", converted, *to)
+	}
+	return nil
+}
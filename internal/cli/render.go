@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/artemlive/gh-crossplane/internal/manifest"
+	"github.com/artemlive/gh-crossplane/internal/render"
+)
+
+var renderCommand = &Command{
+	Name:    "render",
+	Summary: "Print the Crossplane managed resources the groups produce",
+	Run:     runRender,
+}
+
+func runRender(args []string) error {
+	fs, groupsDir := newFlagSet("render", "[-group <name>] [-repo <name>]")
+	group := fs.String("group", "", "Only render this group")
+	repo := fs.String("repo", "", "Only render this repository")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	loader, err := loadGroups(*groupsDir)
+	if err != nil {
+		return err
+	}
+	groups := loader.Groups()
+	if *group != "" {
+		gf := loader.GetGroup(*group)
+		if gf == nil {
+			return fmt.Errorf("group %s not found", *group)
+		}
+		groups = []manifest.GroupFile{*gf}
+	}
+
+	var resources []render.Resource
+	found := false
+	for _, g := range groups {
+		for _, r := range g.Manifest.Spec.Repositories {
+			if *repo != "" && r.Name != *repo {
+				continue
+			}
+			found = true
+			resources = append(resources, render.Repository(g.Manifest, r)...)
+		}
+	}
+	if *repo != "" && !found {
+		return fmt.Errorf("repository %s not found", *repo)
+	}
+	return render.Encode(os.Stdout, resources)
+}
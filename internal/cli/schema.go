@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/artemlive/gh-crossplane/internal/domain"
+	"github.com/artemlive/gh-crossplane/internal/schema"
+	"github.com/artemlive/gh-crossplane/internal/versions"
+	"gopkg.in/yaml.v3"
+)
+
+// errSchemaMismatch makes the command exit with a non-zero status
+var errSchemaMismatch = errors.New("the XRD doesn't match the domain types")
+
+var schemaCommand = &Command{
+	Name:    "schema",
+	Summary: "Generate the XRD from the domain types, or check an existing one",
+	Run:     runSchema,
+}
+
+func runSchema(args []string) error {
+	fs := newCommandFlagSet("schema", "[-api-version <group/version>] [-check <xrd.yaml>]")
+	apiVersion := fs.String("api-version", domain.DefaultAPIVersion, "apiVersion of the RepositoriesGroup claims")
+	check := fs.String("check", "", "Compare the XRD in the file with the domain types instead of printing one")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	spec, err := versions.SpecSchema(versions.Of(*apiVersion))
+	if err != nil {
+		return err
+	}
+
+	if *check != "" {
+		xrd, err := schema.LoadXRD(*check)
+		if err != nil {
+			return err
+		}
+		mismatches, err := schema.Check(xrd, *apiVersion, spec)
+		if err != nil {
+			return err
+		}
+		for _, m := range mismatches {
+			fmt.Println(m)
+		}
+		if len(mismatches) > 0 {
+			fmt.Printf("\n%d mismatches between %s and the domain types\n", len(mismatches), *check)
+			return errSchemaMismatch
+		}
+		fmt.Printf("%s matches the domain types\n", *check)
+		return nil
+	}
+
+	xrd, err := schema.GenerateXRD(*apiVersion, spec)
+	if err != nil {
+		return err
+	}
+	enc := yaml.NewEncoder(os.Stdout)
+	enc.SetIndent(2)
+	if err := enc.Encode(xrd); err != nil {
+		return err
+	}
+	return enc.Close()
+}
@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/artemlive/gh-crossplane/internal/manifest"
+	"github.com/artemlive/gh-crossplane/internal/policy"
+)
+
+// errPolicyViolated makes the command exit with a non-zero status
+var errPolicyViolated = errors.New("policy violations found")
+
+var validateCommand = &Command{
+	Name:    "validate",
+	Summary: "Check the groups against the org-wide policy rules",
+	Run:     runValidate,
+}
+
+func runValidate(args []string) error {
+	fs, groupsDir := newFlagSet("validate", "[-policies <policies.yaml>] [-group <name>]")
+	policies := fs.String("policies", "", "Path to the policy file (defaults to "+policy.FileName+" next to the groups dir)")
+	group := fs.String("group", "", "Only check this group")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *policies == "" {
+		*policies = manifest.ConfigPath(*groupsDir, policy.FileName)
+	}
+
+	// the groups are loaded first, so broken group files fail the validation
+	// even without rules
+	loader, err := loadGroups(*groupsDir)
+	if err != nil {
+		return err
+	}
+	if skipped := loader.Skipped(); len(skipped) > 0 {
+		return fmt.Errorf("%d group files couldn't be loaded", len(skipped))
+	}
+	groups := loader.Groups()
+	if *group != "" {
+		gf := loader.GetGroup(*group)
+		if gf == nil {
+			return fmt.Errorf("group %s not found", *group)
+		}
+		groups = []manifest.GroupFile{*gf}
+	}
+
+	rules, err := policy.Load(*policies)
+	if err != nil {
+		return err
+	}
+	if len(rules.Rules) == 0 {
+		fmt.Printf("No policy rules in %s, %d groups loaded\n", *policies, len(groups))
+		return nil
+	}
+
+	failed, exempt, warnings := 0, 0, 0
+	for _, g := range groups {
+		for _, v := range rules.Evaluate(g.Manifest) {
+			switch {
+			case v.Exemption != nil:
+				exempt++
+				fmt.Println("exempt  ", v)
+			case v.IsError():
+				failed++
+				fmt.Println("error   ", v)
+			default:
+				warnings++
+				fmt.Println("warning ", v)
+			}
+		}
+	}
+
+	fmt.Printf("\n%d groups checked against %d rules: %d errors, %d warnings, %d exempt\n", len(groups), len(rules.Rules), failed, warnings, exempt)
+	if failed > 0 {
+		return errPolicyViolated
+	}
+	return nil
+}
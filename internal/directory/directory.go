@@ -0,0 +1,267 @@
+package directory
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/artemlive/gh-crossplane/internal/domain"
+	"github.com/artemlive/gh-crossplane/internal/github"
+)
+
+// Directory lists the teams and the members of an organization,
+// used to complete and check the permission subjects.
+type Directory struct {
+	Org     string   `json:"org"`
+	Teams   []string `json:"teams"`   // team slugs
+	Members []string `json:"members"` // user logins
+	// Users are the collaborators outside the organization which were looked up,
+	// false if there is no such user
+	Users     map[string]bool `json:"users,omitempty"`
+	FetchedAt time.Time       `json:"fetchedAt"`
+}
+
+// Fetch reads the teams and the members of the organization from GitHub.
+func Fetch(gh github.Organizations, org string, now time.Time) (*Directory, error) {
+	teams, err := gh.ListOrgTeams(org)
+	if err != nil {
+		return nil, fmt.Errorf("list teams of %s: %w", org, err)
+	}
+	members, err := gh.ListOrgMembers(org)
+	if err != nil {
+		return nil, fmt.Errorf("list members of %s: %w", org, err)
+	}
+
+	d := &Directory{Org: org, FetchedAt: now}
+	for _, t := range teams {
+		d.Teams = append(d.Teams, t.Slug)
+	}
+	for _, u := range members {
+		d.Members = append(d.Members, u.Login)
+	}
+	slices.Sort(d.Teams)
+	slices.Sort(d.Members)
+	return d, nil
+}
+
+// DefaultCacheDir returns the directory the lookups are cached in
+func DefaultCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "gh-crossplane"), nil
+}
+
+func cachePath(cacheDir, org string) string {
+	return filepath.Join(cacheDir, "directory-"+org+".json")
+}
+
+// Load reads the cached directory of the organization, os.ErrNotExist if there is none.
+func Load(cacheDir, org string) (*Directory, error) {
+	data, err := os.ReadFile(cachePath(cacheDir, org))
+	if err != nil {
+		return nil, err
+	}
+	var d Directory
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, fmt.Errorf("parse cached directory of %s: %w", org, err)
+	}
+	return &d, nil
+}
+
+// Save writes the directory to the cache
+func (d *Directory) Save(cacheDir string) error {
+	if err := os.MkdirAll(cacheDir, 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := cachePath(cacheDir, d.Org)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Check returns a warning for every permission of the group and its repositories
+// granted to a team which isn't in the organization or to a user which doesn't exist.
+// The collaborators don't have to be members, the users which weren't looked up
+// yet aren't reported.
+func (d *Directory) Check(group domain.RepositoriesGroup) []string {
+	warnings := d.checkPermissions(group.Spec.Permissions, "")
+	for _, r := range group.Spec.Repositories {
+		warnings = append(warnings, d.checkPermissions(r.Permissions, " (repository "+r.Name+")")...)
+	}
+	return warnings
+}
+
+func (d *Directory) checkPermissions(perms []domain.Permission, scope string) []string {
+	var warnings []string
+	for _, p := range perms {
+		switch {
+		case p.Team != "" && !slices.Contains(d.Teams, p.Team):
+			warnings = append(warnings, fmt.Sprintf("unknown team %s in %s%s", p.Team, d.Org, scope))
+		case p.Collaborator != "" && d.missingUser(p.Collaborator):
+			warnings = append(warnings, fmt.Sprintf("unknown user %s%s", p.Collaborator, scope))
+		}
+	}
+	return warnings
+}
+
+// missingUser reports whether the user was looked up and doesn't exist
+func (d *Directory) missingUser(login string) bool {
+	exists, known := d.Users[login]
+	return known && !exists && !slices.Contains(d.Members, login)
+}
+
+// Collaborators returns the users granted a permission on the group or its repositories
+func Collaborators(group domain.RepositoriesGroup) []string {
+	var out []string
+	add := func(perms []domain.Permission) {
+		for _, p := range perms {
+			if p.Collaborator != "" {
+				out = append(out, p.Collaborator)
+			}
+		}
+	}
+	add(group.Spec.Permissions)
+	for _, r := range group.Spec.Repositories {
+		add(r.Permissions)
+	}
+	slices.Sort(out)
+	return slices.Compact(out)
+}
+
+// Cache holds the directory shared by the screens. It's read from disk first, so it
+// works offline, and refreshed from GitHub in the background.
+// The methods are safe for concurrent use and on a nil Cache, which knows nothing.
+type Cache struct {
+	mu       sync.RWMutex
+	dir      string
+	org      string
+	gh       github.Organizations
+	snapshot *Directory
+}
+
+func NewCache(cacheDir, org string, gh github.Organizations) *Cache {
+	return &Cache{
+		dir: cacheDir,
+		org: org,
+		gh:  gh,
+	}
+}
+
+// Load reads the cached directory from disk, a missing cache is not an error
+func (c *Cache) Load() error {
+	d, err := Load(c.dir, c.org)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	c.set(d)
+	return nil
+}
+
+// Refresh fetches the directory from GitHub and updates the cache on disk.
+// On failure the cached copy is kept.
+func (c *Cache) Refresh() error {
+	if c.gh == nil {
+		return errors.New("GitHub client is not available")
+	}
+	d, err := Fetch(c.gh, c.org, time.Now())
+	if err != nil {
+		return err
+	}
+	if old := c.Directory(); old != nil {
+		d.Users = old.Users
+	}
+	c.set(d)
+	return d.Save(c.dir)
+}
+
+// LookupUsers checks whether the users outside the organization exist and
+// updates the cache on disk. The members and the users looked up before are skipped.
+func (c *Cache) LookupUsers(logins []string) error {
+	if c.gh == nil {
+		return errors.New("GitHub client is not available")
+	}
+	d := c.Directory()
+	if d == nil {
+		// the members aren't known yet
+		return nil
+	}
+
+	users := maps.Clone(d.Users)
+	if users == nil {
+		users = make(map[string]bool)
+	}
+	var errs []error
+	for _, login := range logins {
+		if _, known := users[login]; known || slices.Contains(d.Members, login) {
+			continue
+		}
+		_, err := c.gh.GetUser(login)
+		switch {
+		case github.IsNotFound(err):
+			users[login] = false
+		case err != nil:
+			errs = append(errs, fmt.Errorf("look up user %s: %w", login, err))
+		default:
+			users[login] = true
+		}
+	}
+	if len(users) == len(d.Users) {
+		return errors.Join(errs...)
+	}
+
+	// the snapshot is shared with the readers, it's replaced instead of changed
+	updated := *d
+	updated.Users = users
+	c.set(&updated)
+	errs = append(errs, updated.Save(c.dir))
+	return errors.Join(errs...)
+}
+
+func (c *Cache) set(d *Directory) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.snapshot = d
+}
+
+// Directory returns the current directory, nil if nothing is known yet
+func (c *Cache) Directory() *Directory {
+	if c == nil {
+		return nil
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.snapshot
+}
+
+// Teams returns the known team slugs
+func (c *Cache) Teams() []string {
+	if d := c.Directory(); d != nil {
+		return d.Teams
+	}
+	return nil
+}
+
+// Members returns the known user logins
+func (c *Cache) Members() []string {
+	if d := c.Directory(); d != nil {
+		return d.Members
+	}
+	return nil
+}
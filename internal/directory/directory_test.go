@@ -0,0 +1,92 @@
+package directory
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/artemlive/gh-crossplane/internal/domain"
+	"github.com/artemlive/gh-crossplane/internal/github"
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// fakeOrg is an organization with its members, users are the other existing users
+type fakeOrg struct {
+	teams, members, users []string
+	lookedUp              []string
+}
+
+func (f *fakeOrg) ListOrgTeams(org string) ([]github.Team, error) {
+	var out []github.Team
+	for _, t := range f.teams {
+		out = append(out, github.Team{Slug: t})
+	}
+	return out, nil
+}
+
+func (f *fakeOrg) ListOrgMembers(org string) ([]github.User, error) {
+	var out []github.User
+	for _, u := range f.members {
+		out = append(out, github.User{Login: u})
+	}
+	return out, nil
+}
+
+func (f *fakeOrg) GetUser(login string) (*github.User, error) {
+	f.lookedUp = append(f.lookedUp, login)
+	if !slices.Contains(f.users, login) && !slices.Contains(f.members, login) {
+		return nil, &api.HTTPError{StatusCode: http.StatusNotFound}
+	}
+	return &github.User{Login: login}, nil
+}
+
+func TestCheckCollaborators(t *testing.T) {
+	gh := &fakeOrg{teams: []string{"platform"}, members: []string{"alice"}, users: []string{"outside"}}
+	c := NewCache(t.TempDir(), "acme", gh)
+	if err := c.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+
+	var g domain.RepositoriesGroup
+	g.Spec.Permissions = []domain.Permission{
+		{Team: "platform", Permission: "admin"},
+		{Team: "ghosts", Permission: "pull"},
+		{Collaborator: "alice", Permission: "push"},
+		{Collaborator: "outside", Permission: "pull"},
+	}
+	g.Spec.Repositories = []domain.Repository{{
+		Name:        "api",
+		Permissions: []domain.Permission{{Collaborator: "nobody", Permission: "pull"}},
+	}}
+
+	// the users which weren't looked up aren't reported
+	if got, want := c.Directory().Check(g), []string{"unknown team ghosts in acme"}; !slices.Equal(got, want) {
+		t.Errorf("warnings before the lookup = %q, want %q", got, want)
+	}
+
+	if err := c.LookupUsers(Collaborators(g)); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"nobody", "outside"}; !slices.Equal(gh.lookedUp, want) {
+		t.Errorf("looked up %v, want the users outside the org %v", gh.lookedUp, want)
+	}
+	want := []string{"unknown team ghosts in acme", "unknown user nobody (repository api)"}
+	if got := c.Directory().Check(g); !slices.Equal(got, want) {
+		t.Errorf("warnings = %q, want %q", got, want)
+	}
+
+	// the looked up users are cached and kept by the refresh
+	gh.lookedUp = nil
+	if err := c.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.LookupUsers(Collaborators(g)); err != nil {
+		t.Fatal(err)
+	}
+	if len(gh.lookedUp) != 0 {
+		t.Errorf("looked up %v again", gh.lookedUp)
+	}
+	if got := c.Directory().Check(g); !slices.Equal(got, want) {
+		t.Errorf("warnings after the refresh = %q, want %q", got, want)
+	}
+}
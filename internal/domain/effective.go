@@ -0,0 +1,147 @@
+package domain
+
+import (
+	"reflect"
+)
+
+// additiveFields are the lists where the repository items are added to the group ones,
+// for the rest of the fields a repository value replaces the group value.
+var additiveFields = map[string]bool{
+	"Topics":      true,
+	"Permissions": true,
+	"Protections": true,
+}
+
+// IsAdditive reports whether the repository values of the spec field
+// are added to the group values instead of replacing them.
+func IsAdditive(field string) bool {
+	return additiveFields[field]
+}
+
+// Effective returns the settings the repository ends up with:
+// the group spec with the repository overrides applied.
+// Set repository values replace the group ones, topics are combined, permissions
+// and protections are combined with the repository winning for the same team,
+// collaborator or protection name.
+func Effective(spec RepositoriesGroupSpec, repo Repository) RepositoriesGroupSpec {
+	out := spec
+	out.Repositories = nil
+
+	outVal := reflect.ValueOf(&out).Elem()
+	repoVal := reflect.ValueOf(repo)
+	t := outVal.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name := t.Field(i).Name
+		rv := repoVal.FieldByName(name)
+		if !rv.IsValid() || rv.IsZero() || IsAdditive(name) {
+			continue
+		}
+		outVal.Field(i).Set(rv)
+	}
+
+	out.Topics = combine(spec.Topics, repo.Topics, func(t string) string { return t })
+	out.Permissions = combine(spec.Permissions, repo.Permissions, permissionKey)
+	out.Protections = combine(spec.Protections, repo.Protections, func(p Protection) string { return p.Name })
+	return out
+}
+
+// Overlay returns the spec with the set values of top applied, e.g. the settings of a preset.
+// Set values replace the base ones, the lists of the additive fields are combined
+// the same way Effective combines them. The repositories of the base are kept.
+func Overlay(base, top RepositoriesGroupSpec) RepositoriesGroupSpec {
+	out := base
+
+	outVal := reflect.ValueOf(&out).Elem()
+	topVal := reflect.ValueOf(top)
+	t := outVal.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name := t.Field(i).Name
+		if name == "Repositories" || topVal.Field(i).IsZero() || IsAdditive(name) {
+			continue
+		}
+		outVal.Field(i).Set(topVal.Field(i))
+	}
+
+	out.Topics = combine(base.Topics, top.Topics, func(t string) string { return t })
+	out.Permissions = combine(base.Permissions, top.Permissions, permissionKey)
+	out.Protections = combine(base.Protections, top.Protections, func(p Protection) string { return p.Name })
+	return out
+}
+
+func permissionKey(p Permission) string {
+	if p.Team != "" {
+		return "team:" + p.Team
+	}
+	return "collaborator:" + p.Collaborator
+}
+
+// combine appends the repository items to the group ones,
+// a repository item replaces the group item with the same key
+func combine[T any](group, repo []T, key func(T) string) []T {
+	if len(repo) == 0 {
+		return group
+	}
+	var out []T
+	for _, g := range group {
+		replaced := false
+		for _, r := range repo {
+			if key(r) == key(g) {
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			out = append(out, g)
+		}
+	}
+	return append(out, repo...)
+}
+
+// Clone returns a deep copy of the repository, which can be edited
+// without changing the group it was taken from
+func (r Repository) Clone() Repository {
+	return DeepCopy(r)
+}
+
+// DeepCopy copies the value with the pointers, slices and maps it holds,
+// so the copy can be edited without changing the original
+func DeepCopy[T any](v T) T {
+	return deepCopy(reflect.ValueOf(&v).Elem()).Interface().(T)
+}
+
+func deepCopy(v reflect.Value) reflect.Value {
+	out := reflect.New(v.Type()).Elem()
+	switch v.Kind() {
+	case reflect.Pointer:
+		if !v.IsNil() {
+			p := reflect.New(v.Type().Elem())
+			p.Elem().Set(deepCopy(v.Elem()))
+			out.Set(p)
+		}
+	case reflect.Slice:
+		if !v.IsNil() {
+			s := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+			for i := range v.Len() {
+				s.Index(i).Set(deepCopy(v.Index(i)))
+			}
+			out.Set(s)
+		}
+	case reflect.Map:
+		if !v.IsNil() {
+			m := reflect.MakeMapWithSize(v.Type(), v.Len())
+			for iter := v.MapRange(); iter.Next(); {
+				m.SetMapIndex(iter.Key(), deepCopy(iter.Value()))
+			}
+			out.Set(m)
+		}
+	case reflect.Struct:
+		for i := range v.NumField() {
+			if out.Field(i).CanSet() {
+				out.Field(i).Set(deepCopy(v.Field(i)))
+			}
+		}
+	default:
+		out.Set(v)
+	}
+	return out
+}
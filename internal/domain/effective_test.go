@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRepositoryClone(t *testing.T) {
+	archived := true
+	repo := Repository{
+		Name:        "api",
+		Topics:      []string{"go"},
+		Archived:    &archived,
+		Permissions: []Permission{{Team: "platform", Permission: "push"}},
+		Protections: []Protection{{
+			Name:                 "main",
+			Pattern:              "main",
+			RequiredStatusChecks: []StatusCheck{{Contexts: []string{"build"}}},
+		}},
+		Annotations: map[string]string{"crossplane.io/external-name": "api"},
+	}
+
+	clone := repo.Clone()
+	if !reflect.DeepEqual(clone, repo) {
+		t.Fatalf("clone = %+v, want %+v", clone, repo)
+	}
+
+	// the edits of the clone don't reach the original
+	clone.Topics[0] = "rust"
+	*clone.Archived = false
+	clone.Permissions[0].Permission = "admin"
+	clone.Protections[0].RequiredStatusChecks[0].Contexts[0] = "lint"
+	clone.Annotations["crossplane.io/external-name"] = "other"
+
+	if repo.Topics[0] != "go" || !*repo.Archived || repo.Permissions[0].Permission != "push" ||
+		repo.Protections[0].RequiredStatusChecks[0].Contexts[0] != "build" ||
+		repo.Annotations["crossplane.io/external-name"] != "api" {
+		t.Errorf("the original changed with the clone: %+v", repo)
+	}
+}
@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// the formats Kubernetes requires for the keys and values of the labels and annotations
+var (
+	qualifiedName = regexp.MustCompile(`^([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9]$`)
+	dnsSubdomain  = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+)
+
+// ValidateMetadataKey checks a label or annotation key, e.g. "crossplane.io/paused":
+// an optional DNS subdomain prefix and a name of at most 63 characters.
+func ValidateMetadataKey(key string) error {
+	prefix, name, hasPrefix := strings.Cut(key, "/")
+	if !hasPrefix {
+		name, prefix = prefix, ""
+	}
+	if hasPrefix {
+		if len(prefix) > 253 || !dnsSubdomain.MatchString(prefix) {
+			return fmt.Errorf("key %q: the prefix must be a lowercase DNS subdomain of at most 253 characters", key)
+		}
+	}
+	if len(name) > 63 || !qualifiedName.MatchString(name) {
+		return fmt.Errorf("key %q: the name must be at most 63 characters of letters, digits, '-', '_' and '.', starting and ending with a letter or digit", key)
+	}
+	return nil
+}
+
+// ValidateLabelValue checks a label value, it may be empty.
+// Annotation values aren't restricted.
+func ValidateLabelValue(value string) error {
+	if value == "" {
+		return nil
+	}
+	if len(value) > 63 || !qualifiedName.MatchString(value) {
+		return fmt.Errorf("value %q: it must be at most 63 characters of letters, digits, '-', '_' and '.', starting and ending with a letter or digit", value)
+	}
+	return nil
+}
@@ -1,5 +1,11 @@
 package domain
 
+// DefaultAPIVersion is used for new groups when there are no other groups to take it from
+const DefaultAPIVersion = "github.platform.crossplane.io/v1alpha1"
+
+// Kind is the kind of the group manifests
+const Kind = "RepositoriesGroup"
+
 // RepositoriesGroup is the root resource definition
 // matching the YAML structure of the CRD.
 type RepositoriesGroup struct {
@@ -10,20 +16,21 @@ type RepositoriesGroup struct {
 }
 
 type Metadata struct {
-	Name   string            `yaml:"name"`
-	Labels map[string]string `yaml:"labels,omitempty"`
+	Name        string            `yaml:"name"`
+	Labels      map[string]string `yaml:"labels,omitempty" ui:"type=labels,label=Labels"`
+	Annotations map[string]string `yaml:"annotations,omitempty" ui:"type=annotations,label=Annotations"`
 }
 
 type RepositoriesGroupSpec struct {
-	DeletionPolicy           string        `yaml:"deletionPolicy,omitempty" ui:"type=text,label=Deletion Policy"`
-	ManagementPolicies       []string      `yaml:"managementPolicies,omitempty"` // omit: complex
+	DeletionPolicy           string        `yaml:"deletionPolicy,omitempty" ui:"type=text,label=Deletion Policy,enum=Delete|Orphan"`
+	ManagementPolicies       []string      `yaml:"managementPolicies,omitempty" ui:"type=strings,label=Management Policies"`
 	Repositories             []Repository  `yaml:"repositories" ui:"type=repository,label=Repositories"`
-	Permissions              []Permission  `yaml:"permissions,omitempty"`         // omit: complex
-	Topics                   []string      `yaml:"topics,omitempty"`              // omit: complex
-	Protections              []Protection  `yaml:"protections,omitempty"`         // omit: complex
-	SecurityAndAnalysis      []SecAnalysis `yaml:"securityAndAnalysis,omitempty"` // omit: complex
+	Permissions              []Permission  `yaml:"permissions,omitempty" ui:"type=permissions,label=Permissions"`
+	Topics                   []string      `yaml:"topics,omitempty" ui:"type=strings,label=Topics"`
+	Protections              []Protection  `yaml:"protections,omitempty" ui:"type=protections,label=Protections"`
+	SecurityAndAnalysis      []SecAnalysis `yaml:"securityAndAnalysis,omitempty" ui:"type=security,label=Security and Analysis"`
 	DefaultBranch            string        `yaml:"defaultBranch,omitempty" ui:"type=text,label=Default Branch"`
-	Visibility               string        `yaml:"visibility,omitempty" ui:"type=text,label=Visibility"`
+	Visibility               string        `yaml:"visibility,omitempty" ui:"type=text,label=Visibility,enum=public|private|internal"`
 	HasIssues                *bool         `yaml:"hasIssues,omitempty" ui:"type=checkbox,label=Has Issues"`
 	HasDownloads             *bool         `yaml:"hasDownloads,omitempty" ui:"type=checkbox,label=Has Downloads"`
 	HasWiki                  *bool         `yaml:"hasWiki,omitempty" ui:"type=checkbox,label=Has Wiki"`
@@ -37,10 +44,10 @@ type RepositoriesGroupSpec struct {
 	AllowMergeCommit         *bool         `yaml:"allowMergeCommit,omitempty" ui:"type=checkbox,label=Allow Merge Commit"`
 	AllowRebaseMerge         *bool         `yaml:"allowRebaseMerge,omitempty" ui:"type=checkbox,label=Allow Rebase Merge"`
 	IsTemplate               *bool         `yaml:"isTemplate,omitempty" ui:"type=checkbox,label=Is Template"`
-	MergeCommitMessage       string        `yaml:"mergeCommitMessage,omitempty" ui:"type=text,label=Merge Commit Message"`
-	MergeCommitTitle         string        `yaml:"mergeCommitTitle,omitempty" ui:"type=text,label=Merge Commit Title"`
-	SquashMergeCommitMessage string        `yaml:"squashMergeCommitMessage,omitempty" ui:"type=text,label=Squash Commit Message"`
-	SquashMergeCommitTitle   string        `yaml:"squashMergeCommitTitle,omitempty" ui:"type=text,label=Squash Commit Title"`
+	MergeCommitMessage       string        `yaml:"mergeCommitMessage,omitempty" ui:"type=text,label=Merge Commit Message,enum=PR_BODY|PR_TITLE|BLANK"`
+	MergeCommitTitle         string        `yaml:"mergeCommitTitle,omitempty" ui:"type=text,label=Merge Commit Title,enum=PR_TITLE|MERGE_MESSAGE"`
+	SquashMergeCommitMessage string        `yaml:"squashMergeCommitMessage,omitempty" ui:"type=text,label=Squash Commit Message,enum=PR_BODY|COMMIT_MESSAGES|BLANK"`
+	SquashMergeCommitTitle   string        `yaml:"squashMergeCommitTitle,omitempty" ui:"type=text,label=Squash Commit Title,enum=PR_TITLE|COMMIT_OR_PR_TITLE"`
 	VulnerabilityAlerts      *bool         `yaml:"vulnerabilityAlerts,omitempty" ui:"type=checkbox,label=Vulnerability Alerts"`
 	AutolinkReferences       []AutolinkRef `yaml:"autolinkReferences,omitempty"` // omit: complex
 }
@@ -48,21 +55,23 @@ type RepositoriesGroupSpec struct {
 type Repository struct {
 	Name                string        `yaml:"name" ui:"type=text,label=Name"`
 	Description         string        `yaml:"description,omitempty" ui:"type=text,label=Description"`
-	Permissions         []Permission  `yaml:"permissions,omitempty"` // omit: complex
-	Topics              []string      `yaml:"topics,omitempty"`      // omit: complex
+	Permissions         []Permission  `yaml:"permissions,omitempty" ui:"type=permissions,label=Permissions"`
+	Topics              []string      `yaml:"topics,omitempty" ui:"type=strings,label=Topics"`
 	Archived            *bool         `yaml:"archived,omitempty" ui:"type=checkbox,label=Archived"`
-	Visibility          string        `yaml:"visibility,omitempty" ui:"type=text,label=Visibility"`
+	Visibility          string        `yaml:"visibility,omitempty" ui:"type=text,label=Visibility,enum=public|private|internal"`
 	DefaultBranch       string        `yaml:"defaultBranch,omitempty" ui:"type=text,label=Default Branch"`
 	AllowAutoMerge      *bool         `yaml:"allowAutoMerge,omitempty" ui:"type=checkbox,label=Allow Auto-Merge"`
 	DeleteBranchOnMerge *bool         `yaml:"deleteBranchOnMerge,omitempty" ui:"type=checkbox,label=Delete Branch on Merge"`
-	SecurityAndAnalysis []SecAnalysis `yaml:"securityAndAnalysis,omitempty"` // omit: complex
-	Protections         []Protection  `yaml:"protections,omitempty"`         // omit: complex
+	SecurityAndAnalysis []SecAnalysis `yaml:"securityAndAnalysis,omitempty" ui:"type=security,label=Security and Analysis"`
+	Protections         []Protection  `yaml:"protections,omitempty" ui:"type=protections,label=Protections"`
+	// Annotations are set on the managed Repository, e.g. crossplane.io/external-name
+	Annotations map[string]string `yaml:"annotations,omitempty" ui:"type=annotations,label=Annotations"`
 }
 
 type Permission struct {
 	Team         string `yaml:"team,omitempty"`
 	Collaborator string `yaml:"collaborator,omitempty"`
-	Permission   string `yaml:"permission"`
+	Permission   string `yaml:"permission" ui:"enum=pull|triage|push|maintain|admin"`
 }
 
 type SecAnalysis struct {
@@ -72,7 +81,7 @@ type SecAnalysis struct {
 }
 
 type Status struct {
-	Status string `yaml:"status"`
+	Status string `yaml:"status" ui:"enum=enabled|disabled"`
 }
 
 type Protection struct {
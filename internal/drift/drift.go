@@ -0,0 +1,315 @@
+package drift
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/artemlive/gh-crossplane/internal/domain"
+	"github.com/artemlive/gh-crossplane/internal/github"
+	"github.com/artemlive/gh-crossplane/internal/importer"
+	"github.com/artemlive/gh-crossplane/internal/manifest"
+	"github.com/artemlive/gh-crossplane/internal/util"
+)
+
+// unobservable are the spec fields which can't be read back from the repository settings
+var unobservable = map[string]bool{
+	"DeletionPolicy":      true,
+	"ManagementPolicies":  true,
+	"AutoInit":            true,
+	"ArchiveOnDestroy":    true,
+	"VulnerabilityAlerts": true,
+	"AutolinkReferences":  true,
+}
+
+// FieldDiff is a setting which differs between the manifest and GitHub
+type FieldDiff struct {
+	Field    string // yaml path relative to the repository, e.g. "protections[main].enforceAdmins"
+	Manifest string
+	Live     string
+}
+
+// RepoReport holds the differences of a single repository
+type RepoReport struct {
+	Group string
+	Repo  string
+	Diffs []FieldDiff
+	Err   error // the live settings couldn't be read
+}
+
+// Report is the result of a drift check
+type Report struct {
+	Owner string
+	Repos []RepoReport
+}
+
+// Drifted returns the reports of the repositories with differences or errors
+func (r Report) Drifted() []RepoReport {
+	var out []RepoReport
+	for _, rr := range r.Repos {
+		if len(rr.Diffs) > 0 || rr.Err != nil {
+			out = append(out, rr)
+		}
+	}
+	return out
+}
+
+// Check compares the effective settings of every repository in the groups
+// with its live settings on GitHub.
+func Check(gh github.Repositories, owner string, groups []manifest.GroupFile) Report {
+	report := Report{Owner: owner}
+	for _, g := range groups {
+		for _, repo := range g.Manifest.Spec.Repositories {
+			rr := RepoReport{
+				Group: g.Manifest.Metadata.Name,
+				Repo:  repo.Name,
+			}
+			live, err := importer.Fetch(gh, owner, []string{repo.Name})
+			if err != nil {
+				rr.Err = err
+			} else {
+				rr.Diffs = Compare(domain.Effective(g.Manifest.Spec, repo), repo, live[0])
+			}
+			report.Repos = append(report.Repos, rr)
+		}
+	}
+	return report
+}
+
+// Compare returns the differences between the effective settings of the repository
+// and its live settings. Settings which aren't set in the manifest are not managed
+// and thus never reported.
+func Compare(want domain.RepositoriesGroupSpec, repo domain.Repository, live importer.LiveRepository) []FieldDiff {
+	var diffs []FieldDiff
+
+	if repo.Description != "" && repo.Description != live.Description {
+		diffs = append(diffs, FieldDiff{"description", quote(repo.Description), quote(live.Description)})
+	}
+	if repo.Archived != nil && *repo.Archived != live.Archived {
+		diffs = append(diffs, FieldDiff{"archived", fmt.Sprint(*repo.Archived), fmt.Sprint(live.Archived)})
+	}
+
+	wantVal := reflect.ValueOf(want)
+	liveVal := reflect.ValueOf(live.Spec)
+	t := wantVal.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.Name == "Repositories" || unobservable[sf.Name] {
+			continue
+		}
+		wv, lv := wantVal.Field(i), liveVal.Field(i)
+		if wv.IsZero() {
+			continue
+		}
+		name := util.YAMLName(sf)
+
+		switch sf.Name {
+		case "Topics":
+			diffs = append(diffs, compareTopics(want.Topics, live.Spec.Topics)...)
+		case "Permissions":
+			diffs = append(diffs, comparePermissions(want.Permissions, live.Spec.Permissions)...)
+		case "Protections":
+			diffs = append(diffs, compareProtections(want.Protections, live.Spec.Protections)...)
+		case "SecurityAndAnalysis":
+			diffs = append(diffs, compareStructs(name, wv, lv)...)
+		default:
+			if !reflect.DeepEqual(wv.Interface(), lv.Interface()) {
+				diffs = append(diffs, FieldDiff{name, formatValue(wv), formatValue(lv)})
+			}
+		}
+	}
+	return diffs
+}
+
+func compareTopics(want, live []string) []FieldDiff {
+	a, b := slices.Clone(want), slices.Clone(live)
+	slices.Sort(a)
+	slices.Sort(b)
+	if slices.Equal(a, b) {
+		return nil
+	}
+	return []FieldDiff{{"topics", strings.Join(a, ", "), strings.Join(b, ", ")}}
+}
+
+// comparePermissions compares the team permissions, collaborators aren't read from GitHub
+func comparePermissions(want, live []domain.Permission) []FieldDiff {
+	var diffs []FieldDiff
+	liveByTeam := make(map[string]string)
+	for _, p := range live {
+		liveByTeam[p.Team] = p.Permission
+	}
+
+	wantTeams := make(map[string]bool)
+	for _, p := range want {
+		if p.Team == "" {
+			continue
+		}
+		wantTeams[p.Team] = true
+		if got, ok := liveByTeam[p.Team]; !ok || got != p.Permission {
+			diffs = append(diffs, FieldDiff{"permissions[team " + p.Team + "]", p.Permission, orNone(got)})
+		}
+	}
+	for _, p := range live {
+		if !wantTeams[p.Team] {
+			diffs = append(diffs, FieldDiff{"permissions[team " + p.Team + "]", "<none>", p.Permission})
+		}
+	}
+	return diffs
+}
+
+// compareProtections matches the protections by pattern, the REST API only reports
+// the protected branches, so patterns with wildcards can't be checked. The branches
+// they cover aren't reported as protected outside the manifest either.
+func compareProtections(want, live []domain.Protection) []FieldDiff {
+	var diffs []FieldDiff
+	liveByBranch := make(map[string]domain.Protection)
+	for _, p := range live {
+		liveByBranch[p.Pattern] = p
+	}
+
+	wantBranches := make(map[string]bool)
+	var wildcards []string
+	for _, p := range want {
+		if strings.ContainsAny(p.Pattern, "*?[") {
+			wildcards = append(wildcards, p.Pattern)
+			continue
+		}
+		wantBranches[p.Pattern] = true
+		path := fmt.Sprintf("protections[%s]", p.Name)
+		got, ok := liveByBranch[p.Pattern]
+		if !ok {
+			diffs = append(diffs, FieldDiff{path, "protected", "not protected"})
+			continue
+		}
+		// the name only exists in the manifest
+		got.Name = p.Name
+		diffs = append(diffs, compareStructs(path, reflect.ValueOf(p), reflect.ValueOf(got))...)
+	}
+	for _, p := range live {
+		if !wantBranches[p.Pattern] && !matchesAny(wildcards, p.Pattern) {
+			diffs = append(diffs, FieldDiff{fmt.Sprintf("protections[%s]", p.Pattern), "not protected", "protected"})
+		}
+	}
+	return diffs
+}
+
+// matchesAny reports whether the branch matches one of the patterns,
+// like in GitHub "*" doesn't match "/" and "**" does
+func matchesAny(patterns []string, branch string) bool {
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(globRegexp(pattern))
+		if err == nil && re.MatchString(branch) {
+			return true
+		}
+	}
+	return false
+}
+
+// globRegexp converts the branch name pattern to a regular expression
+func globRegexp(pattern string) string {
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			if strings.HasPrefix(pattern[i:], "**") {
+				b.WriteString(".*")
+				i++
+			} else {
+				b.WriteString("[^/]*")
+			}
+		case '?':
+			b.WriteString("[^/]")
+		case '[':
+			end := strings.IndexByte(pattern[i:], ']')
+			if end < 0 {
+				b.WriteString(`\[`)
+				continue
+			}
+			b.WriteString(pattern[i : i+end+1])
+			i += end
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return b.String()
+}
+
+// compareStructs compares the nested settings field by field,
+// single item lists are compared as their item. The settings
+// left unset in the manifest are skipped at every level.
+func compareStructs(path string, want, live reflect.Value) []FieldDiff {
+	if want.IsZero() {
+		return nil
+	}
+	if want.Kind() == reflect.Slice {
+		if want.Len() == 1 && live.Len() == 1 {
+			return compareStructs(path, want.Index(0), live.Index(0))
+		}
+		if !reflect.DeepEqual(want.Interface(), live.Interface()) {
+			return []FieldDiff{{path, formatValue(want), formatValue(live)}}
+		}
+		return nil
+	}
+	if want.Kind() != reflect.Struct {
+		if !reflect.DeepEqual(want.Interface(), live.Interface()) {
+			return []FieldDiff{{path, formatValue(want), formatValue(live)}}
+		}
+		return nil
+	}
+
+	var diffs []FieldDiff
+	for i := 0; i < want.NumField(); i++ {
+		sf := want.Type().Field(i)
+		diffs = append(diffs, compareStructs(path+"."+util.YAMLName(sf), want.Field(i), live.Field(i))...)
+	}
+	return diffs
+}
+
+// Print writes the report as a per repository table
+func Print(w io.Writer, r Report) error {
+	drifted := r.Drifted()
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, rr := range drifted {
+		fmt.Fprintf(tw, "%s/%s (group %s)\n", r.Owner, rr.Repo, rr.Group)
+		if rr.Err != nil {
+			fmt.Fprintf(tw, "  error:\t%s\n", rr.Err)
+			continue
+		}
+		fmt.Fprintf(tw, "  FIELD\tMANIFEST\tLIVE\n")
+		for _, d := range rr.Diffs {
+			fmt.Fprintf(tw, "  %s\t%s\t%s\n", d.Field, d.Manifest, d.Live)
+		}
+	}
+	fmt.Fprintf(tw, "\n%d of %d repositories drifted\n", len(drifted), len(r.Repos))
+	return tw.Flush()
+}
+
+func formatValue(v reflect.Value) string {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return "<unset>"
+		}
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.String {
+		return quote(v.String())
+	}
+	return fmt.Sprintf("%v", v.Interface())
+}
+
+func quote(s string) string {
+	return fmt.Sprintf("%q", s)
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "<none>"
+	}
+	return s
+}
@@ -0,0 +1,72 @@
+package drift
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/artemlive/gh-crossplane/internal/github"
+	"github.com/artemlive/gh-crossplane/internal/manifest"
+)
+
+// TestCheckFixtures checks the groups in testdata/groups against the responses
+// recorded in testdata/github
+func TestCheckFixtures(t *testing.T) {
+	loader, err := manifest.Load("testdata/groups")
+	if err != nil {
+		t.Fatal(err)
+	}
+	gh, err := github.NewFixtureClient("testdata/github")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report := Check(gh, "acme", loader.Groups())
+	if len(report.Repos) != 2 {
+		t.Fatalf("checked %d repositories, want 2", len(report.Repos))
+	}
+
+	drifted := report.Drifted()
+	if len(drifted) != 1 || drifted[0].Repo != "api" {
+		t.Fatalf("drifted = %+v, want only api", drifted)
+	}
+	if drifted[0].Err != nil {
+		t.Fatal(drifted[0].Err)
+	}
+
+	// the review settings left unset in the manifest and the release branches
+	// covered by the wildcard pattern aren't reported
+	want := []FieldDiff{
+		{"protections[hotfix]", "not protected", "protected"},
+		{"visibility", `"private"`, `"public"`},
+	}
+	if !reflect.DeepEqual(drifted[0].Diffs, want) {
+		t.Errorf("diffs = %+v, want %+v", drifted[0].Diffs, want)
+	}
+
+	var out strings.Builder
+	if err := Print(&out, report); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "1 of 2 repositories drifted") {
+		t.Errorf("unexpected report:\n%s", out.String())
+	}
+}
+
+func TestMatchesAny(t *testing.T) {
+	for _, tc := range []struct {
+		pattern, branch string
+		want            bool
+	}{
+		{"release/*", "release/1.0", true},
+		{"release/*", "release/1.0/hotfix", false},
+		{"release/**", "release/1.0/hotfix", true},
+		{"v?", "v1", true},
+		{"[ab]-*", "b-x", true},
+		{"feature.*", "feature-x", false},
+	} {
+		if got := matchesAny([]string{tc.pattern}, tc.branch); got != tc.want {
+			t.Errorf("matchesAny(%q, %q) = %v, want %v", tc.pattern, tc.branch, got, tc.want)
+		}
+	}
+}
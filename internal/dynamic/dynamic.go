@@ -0,0 +1,248 @@
+// Package dynamic edits the group manifests as generic yaml documents,
+// driven by the openAPIV3Schema of an XRD instead of the domain types.
+package dynamic
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/artemlive/gh-crossplane/internal/schema"
+	"github.com/artemlive/gh-crossplane/internal/util"
+	"gopkg.in/yaml.v3"
+)
+
+// Field is a property of the schema which can be edited as a single value
+type Field struct {
+	Path   []string // e.g. ["spec", "hasIssues"]
+	Schema *schema.Schema
+}
+
+// Name returns the dotted path of the field, e.g. "spec.hasIssues"
+func (f Field) Name() string {
+	return strings.Join(f.Path, ".")
+}
+
+// Label returns the name of the property in words
+func (f Field) Label() string {
+	return util.Humanize(f.Path[len(f.Path)-1])
+}
+
+// Section returns the dotted path of the object the field belongs to
+func (f Field) Section() string {
+	return strings.Join(f.Path[:len(f.Path)-1], ".")
+}
+
+// Editable reports whether the field can be edited as a single value:
+// scalars and lists of scalars. Lists of objects and maps can't.
+func (f Field) Editable() bool {
+	return scalar(f.Schema.Type) || (f.Schema.Type == "array" && f.Schema.Items != nil && scalar(f.Schema.Items.Type))
+}
+
+func scalar(t string) bool {
+	return t == "string" || t == "boolean" || t == "integer" || t == "number"
+}
+
+// Fields flattens the properties of the object schema into fields, depth first
+// and sorted by name. Nested objects with properties are expanded,
+// everything else is a field on its own.
+func Fields(s *schema.Schema, path ...string) []Field {
+	var out []Field
+	for _, name := range slices.Sorted(maps.Keys(s.Properties)) {
+		prop := s.Properties[name]
+		p := append(slices.Clone(path), name)
+		if prop.Type == "object" && len(prop.Properties) > 0 {
+			out = append(out, Fields(prop, p...)...)
+			continue
+		}
+		out = append(out, Field{Path: p, Schema: prop})
+	}
+	return out
+}
+
+// Lookup returns the value at the path of the document, nil if it's not set
+func Lookup(doc *yaml.Node, path []string) *yaml.Node {
+	n := root(doc)
+	for _, key := range path {
+		if n == nil || n.Kind != yaml.MappingNode {
+			return nil
+		}
+		n = value(n, key)
+	}
+	return n
+}
+
+// Text returns the value of the field in the document as it is edited:
+// a scalar as is, a list as comma separated values, "" if not set
+func Text(doc *yaml.Node, f Field) string {
+	n := Lookup(doc, f.Path)
+	if n == nil {
+		return ""
+	}
+	switch n.Kind {
+	case yaml.ScalarNode:
+		return n.Value
+	case yaml.SequenceNode:
+		var items []string
+		for _, item := range n.Content {
+			items = append(items, item.Value)
+		}
+		return strings.Join(items, ", ")
+	}
+	return ""
+}
+
+// Set sets the field in the document from its text form, see Text.
+// An empty text removes the field. The value is checked against the type
+// and the allowed values of the schema. The mappings along the path
+// are created as needed, the other keys and the comments are kept.
+func Set(doc *yaml.Node, f Field, text string) error {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		Delete(doc, f.Path)
+		return nil
+	}
+
+	var n *yaml.Node
+	if f.Schema.Type == "array" {
+		n = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for _, item := range strings.Split(text, ",") {
+			item = strings.TrimSpace(item)
+			if item == "" {
+				continue
+			}
+			in, err := scalarNode(f.Schema.Items, item)
+			if err != nil {
+				return fmt.Errorf("%s: %w", f.Name(), err)
+			}
+			n.Content = append(n.Content, in)
+		}
+	} else {
+		var err error
+		if n, err = scalarNode(f.Schema, text); err != nil {
+			return fmt.Errorf("%s: %w", f.Name(), err)
+		}
+	}
+
+	parent := root(doc)
+	for _, key := range f.Path[:len(f.Path)-1] {
+		child := value(parent, key)
+		if child == nil || child.Kind != yaml.MappingNode {
+			child = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			setValue(parent, key, child)
+		}
+		parent = child
+	}
+	key := f.Path[len(f.Path)-1]
+	if old := value(parent, key); old != nil {
+		// keep the comments of the replaced value
+		n.HeadComment, n.LineComment, n.FootComment = old.HeadComment, old.LineComment, old.FootComment
+	}
+	setValue(parent, key, n)
+	return nil
+}
+
+// Delete removes the value at the path of the document
+func Delete(doc *yaml.Node, path []string) {
+	parent := Lookup(doc, path[:len(path)-1])
+	if parent == nil || parent.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(parent.Content); i += 2 {
+		if parent.Content[i].Value == path[len(path)-1] {
+			parent.Content = slices.Delete(parent.Content, i, i+2)
+			return
+		}
+	}
+}
+
+func scalarNode(s *schema.Schema, text string) (*yaml.Node, error) {
+	if len(s.Enum) > 0 && !slices.Contains(s.Enum, text) {
+		return nil, fmt.Errorf("%q is not one of %s", text, strings.Join(s.Enum, ", "))
+	}
+	n := &yaml.Node{Kind: yaml.ScalarNode, Value: text}
+	switch s.Type {
+	case "boolean":
+		b, err := strconv.ParseBool(text)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a boolean", text)
+		}
+		n.Tag, n.Value = "!!bool", strconv.FormatBool(b)
+	case "integer":
+		if _, err := strconv.ParseInt(text, 10, 64); err != nil {
+			return nil, fmt.Errorf("%q is not an integer", text)
+		}
+		n.Tag = "!!int"
+	case "number":
+		if _, err := strconv.ParseFloat(text, 64); err != nil {
+			return nil, fmt.Errorf("%q is not a number", text)
+		}
+		n.Tag = "!!float"
+	default:
+		// quoted if it would be read back as something else than a string
+		n.Tag = "!!str"
+	}
+	return n, nil
+}
+
+// root returns the top level mapping of the document
+func root(doc *yaml.Node) *yaml.Node {
+	if doc.Kind == yaml.DocumentNode {
+		if len(doc.Content) == 0 {
+			doc.Content = []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}
+		}
+		return doc.Content[0]
+	}
+	return doc
+}
+
+func value(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func setValue(mapping *yaml.Node, key string, n *yaml.Node) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			mapping.Content[i+1] = n
+			return
+		}
+	}
+	mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, n)
+}
+
+// Summary describes a field which isn't editable, e.g. "3 items"
+func Summary(doc *yaml.Node, f Field) string {
+	n := Lookup(doc, f.Path)
+	switch {
+	case n == nil:
+		return "not set"
+	case n.Kind == yaml.SequenceNode:
+		return fmt.Sprintf("%d items", len(n.Content))
+	case n.Kind == yaml.MappingNode:
+		return fmt.Sprintf("%d keys", len(n.Content)/2)
+	}
+	return n.Value
+}
+
+// DefaultText returns the default of the field in its text form, "" if there's none
+func DefaultText(f Field) string {
+	switch d := f.Schema.Default.(type) {
+	case nil:
+		return ""
+	case []any:
+		var items []string
+		for _, item := range d {
+			items = append(items, fmt.Sprint(item))
+		}
+		return strings.Join(items, ", ")
+	default:
+		return fmt.Sprint(d)
+	}
+}
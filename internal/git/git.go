@@ -0,0 +1,196 @@
+package git
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Repository is the set of git operations the tool needs.
+// It's an interface, so the workflows can run against a fake or a temp repo.
+type Repository interface {
+	// Root returns the absolute path of the working tree
+	Root() string
+	CurrentBranch() (string, error)
+	// CreateBranch creates a new branch from HEAD and switches to it
+	CreateBranch(name string) error
+	// ChangedFiles returns the absolute paths of modified and untracked files
+	ChangedFiles() ([]string, error)
+	// FileAt returns the file content at the revision, os.ErrNotExist if it's not there
+	FileAt(rev, path string) ([]byte, error)
+	// ResolveRevision returns the commit hash of the revision, e.g. "origin/main"
+	ResolveRevision(rev string) (string, error)
+	Add(paths ...string) error
+	// Commit commits the paths only, the other staged changes stay staged.
+	// The new files have to be added first.
+	Commit(message string, paths ...string) error
+	RemoteURL(remote string) (string, error)
+	// Push pushes the branch and sets it as upstream
+	Push(remote, branch string) error
+}
+
+// CLIRepository implements Repository by running the git binary.
+type CLIRepository struct {
+	root string
+}
+
+// compile-time check to ensure CLIRepository implements the Repository interface
+var _ Repository = (*CLIRepository)(nil)
+
+// Open finds the git repository containing dir.
+func Open(dir string) (*CLIRepository, error) {
+	out, err := run(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, fmt.Errorf("%s is not inside a git repository: %w", dir, err)
+	}
+	return &CLIRepository{root: strings.TrimSpace(out)}, nil
+}
+
+func (r *CLIRepository) Root() string {
+	return r.root
+}
+
+func (r *CLIRepository) CurrentBranch() (string, error) {
+	out, err := run(r.root, "rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
+
+func (r *CLIRepository) CreateBranch(name string) error {
+	_, err := run(r.root, "checkout", "-b", name)
+	return err
+}
+
+func (r *CLIRepository) ChangedFiles() ([]string, error) {
+	out, err := run(r.root, "status", "--porcelain", "-z", "--untracked-files=all")
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	entries := strings.Split(out, "\x00")
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+		if len(entry) < 4 {
+			continue
+		}
+		status, path := entry[:2], entry[3:]
+		if status[0] == 'R' || status[0] == 'C' {
+			// renames and copies are followed by the source path
+			i++
+		}
+		files = append(files, filepath.Join(r.root, path))
+	}
+	return files, nil
+}
+
+func (r *CLIRepository) FileAt(rev, path string) ([]byte, error) {
+	rel, err := r.rel(path)
+	if err != nil {
+		return nil, err
+	}
+	out, err := run(r.root, "show", rev+":"+rel)
+	if err != nil {
+		// either the revision doesn't exist or the file isn't there
+		return nil, fmt.Errorf("%s at %s: %w", rel, rev, os.ErrNotExist)
+	}
+	return []byte(out), nil
+}
+
+func (r *CLIRepository) ResolveRevision(rev string) (string, error) {
+	out, err := run(r.root, "rev-parse", "--verify", "--quiet", rev+"^{commit}")
+	if err != nil {
+		return "", fmt.Errorf("unknown revision %s", rev)
+	}
+	return strings.TrimSpace(out), nil
+}
+
+func (r *CLIRepository) Add(paths ...string) error {
+	rels, err := r.rels(paths)
+	if err != nil {
+		return err
+	}
+	_, err = run(r.root, append([]string{"add", "--"}, rels...)...)
+	return err
+}
+
+func (r *CLIRepository) Commit(message string, paths ...string) error {
+	if len(paths) == 0 {
+		return errors.New("no paths to commit")
+	}
+	rels, err := r.rels(paths)
+	if err != nil {
+		return err
+	}
+	_, err = run(r.root, append([]string{"commit", "--only", "-m", message, "--"}, rels...)...)
+	return err
+}
+
+func (r *CLIRepository) RemoteURL(remote string) (string, error) {
+	out, err := run(r.root, "remote", "get-url", remote)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
+
+func (r *CLIRepository) Push(remote, branch string) error {
+	_, err := run(r.root, "push", "--set-upstream", remote, branch)
+	return err
+}
+
+// rels returns the paths relative to the repository root
+func (r *CLIRepository) rels(paths []string) ([]string, error) {
+	rels := make([]string, 0, len(paths))
+	for _, p := range paths {
+		rel, err := r.rel(p)
+		if err != nil {
+			return nil, err
+		}
+		rels = append(rels, rel)
+	}
+	return rels, nil
+}
+
+// rel returns the path relative to the repository root, using forward slashes
+func (r *CLIRepository) rel(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	// resolve symlinks the same way git does for the root, e.g. /tmp on macOS
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		abs = resolved
+	}
+	rel, err := filepath.Rel(r.root, abs)
+	if err != nil {
+		return "", err
+	}
+	if strings.HasPrefix(rel, "..") {
+		return "", fmt.Errorf("%s is outside of the repository %s", path, r.root)
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+func run(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && msg != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return stdout.String(), nil
+}
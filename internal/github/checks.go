@@ -0,0 +1,51 @@
+package github
+
+import (
+	"net/url"
+)
+
+// Checks is the part of the API used to find the status check names of a repository.
+type Checks interface {
+	GetRepository(owner, name string) (*Repository, error)
+	// ListCheckRuns returns the check runs of the latest commit of the ref
+	ListCheckRuns(owner, name, ref string) ([]CheckRun, error)
+	// ListCommitStatuses returns the latest commit statuses of the ref, one per context
+	ListCommitStatuses(owner, name, ref string) ([]CommitStatus, error)
+}
+
+type CheckRun struct {
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	Conclusion string `json:"conclusion"`
+}
+
+type CommitStatus struct {
+	Context string `json:"context"`
+	State   string `json:"state"`
+}
+
+func (c *Client) ListCheckRuns(owner, name, ref string) ([]CheckRun, error) {
+	query := url.Values{}
+	query.Set("per_page", "100")
+
+	var resp struct {
+		CheckRuns []CheckRun `json:"check_runs"`
+	}
+	if err := c.get("repos/"+owner+"/"+name+"/commits/"+url.PathEscape(ref)+"/check-runs", query, &resp); err != nil {
+		return nil, err
+	}
+	return resp.CheckRuns, nil
+}
+
+func (c *Client) ListCommitStatuses(owner, name, ref string) ([]CommitStatus, error) {
+	query := url.Values{}
+	query.Set("per_page", "100")
+
+	var resp struct {
+		Statuses []CommitStatus `json:"statuses"`
+	}
+	if err := c.get("repos/"+owner+"/"+name+"/commits/"+url.PathEscape(ref)+"/status", query, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Statuses, nil
+}
@@ -0,0 +1,121 @@
+package github
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// NewFixtureClient returns a Client answering GET requests with recorded responses
+// from the directory instead of calling GitHub, e.g. "repos/acme/foo" is read
+// from "<dir>/repos/acme/foo.json" and its second page from "<dir>/repos/acme/foo.page2.json".
+// Missing fixtures are answered with 404, missing next pages with an empty list.
+// It lets the commands run offline and against known data.
+func NewFixtureClient(dir string) (*Client, error) {
+	rest, err := api.NewRESTClient(api.ClientOptions{
+		Host:         "github.com",
+		AuthToken:    "fixtures",
+		Transport:    fixtureTransport{dir: dir},
+		LogIgnoreEnv: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(rest, ""), nil
+}
+
+// NewRecordingClient returns a Client using the default authentication,
+// which saves every successful GET response to the directory
+// in the layout expected by NewFixtureClient.
+func NewRecordingClient(dir string) (*Client, error) {
+	rest, err := api.NewRESTClient(api.ClientOptions{
+		Transport: recordingTransport{dir: dir, next: http.DefaultTransport},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(rest, ""), nil
+}
+
+// fixturePath maps the request to the fixture file, the query is ignored
+// except for the page after the first one
+func fixturePath(dir string, req *http.Request) string {
+	path := strings.TrimPrefix(req.URL.Path, "/")
+	path = strings.TrimPrefix(path, "api/v3/")
+	if isNextPage(req) {
+		path += ".page" + req.URL.Query().Get("page")
+	}
+	return filepath.Join(dir, filepath.FromSlash(path)+".json")
+}
+
+// isNextPage reports whether the request asks for a page after the first one
+func isNextPage(req *http.Request) bool {
+	page := req.URL.Query().Get("page")
+	return page != "" && page != "1"
+}
+
+type fixtureTransport struct {
+	dir string
+}
+
+func (t fixtureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		return jsonResponse(req, http.StatusMethodNotAllowed, []byte(`{"message":"fixtures are read-only"}`)), nil
+	}
+	body, err := os.ReadFile(fixturePath(t.dir, req))
+	if os.IsNotExist(err) && isNextPage(req) {
+		// the recorded list ended on the previous page
+		return jsonResponse(req, http.StatusOK, []byte(`[]`)), nil
+	}
+	if os.IsNotExist(err) {
+		return jsonResponse(req, http.StatusNotFound, []byte(`{"message":"Not Found"}`)), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return jsonResponse(req, http.StatusOK, body), nil
+}
+
+type recordingTransport struct {
+	dir  string
+	next http.RoundTripper
+}
+
+func (t recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || req.Method != http.MethodGet || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close() //nolint:errcheck
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	path := fixturePath(t.dir, req)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("record %s: %w", req.URL.Path, err)
+	}
+	if err := os.WriteFile(path, body, 0o644); err != nil {
+		return nil, fmt.Errorf("record %s: %w", req.URL.Path, err)
+	}
+	return resp, nil
+}
+
+func jsonResponse(req *http.Request, status int, body []byte) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Status:     fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		Header:     http.Header{"Content-Type": []string{"application/json; charset=utf-8"}},
+		Body:       io.NopCloser(bytes.NewReader(body)),
+		Request:    req,
+	}
+}
@@ -0,0 +1,67 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+func TestRecordAndReplayPages(t *testing.T) {
+	t.Setenv("GH_TOKEN", "test")
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /orgs/acme/repos", func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		var repos []Repository
+		switch page {
+		case 1:
+			repos = make([]Repository, 100)
+		case 2:
+			repos = make([]Repository, 2)
+		}
+		for i := range repos {
+			repos[i].Name = fmt.Sprintf("repo-%d-%d", page, i)
+		}
+		json.NewEncoder(w).Encode(repos) //nolint:errcheck
+	})
+	live := newTestClient(t, mux)
+
+	dir := t.TempDir()
+	recording, err := NewRecordingClient(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// send the recorded requests to the test server
+	recording.baseURL = live.baseURL
+	recorded, err := recording.ListOrgRepositories("acme")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recorded) != 102 {
+		t.Fatalf("recorded %d repositories, want 102", len(recorded))
+	}
+	for _, name := range []string{"repos.json", "repos.page2.json"} {
+		if _, err := os.Stat(filepath.Join(dir, "orgs", "acme", name)); err != nil {
+			t.Errorf("page not recorded: %v", err)
+		}
+	}
+
+	fixtures, err := NewFixtureClient(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replayed, err := fixtures.ListOrgRepositories("acme")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(replayed) != len(recorded) || replayed[101].Name != "repo-2-1" {
+		t.Errorf("replayed %d repositories, want the %d recorded", len(replayed), len(recorded))
+	}
+
+	if _, err := fixtures.GetRepository("acme", "missing"); !IsNotFound(err) {
+		t.Errorf("missing fixture error = %v, want not found", err)
+	}
+}
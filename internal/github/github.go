@@ -1,9 +1,33 @@
 package github
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
 	"github.com/cli/go-gh/v2/pkg/api"
 )
 
+// API is everything the tool needs from GitHub.
+// It's implemented by Client, the workflows depend on the narrower interfaces.
+type API interface {
+	PullRequests
+	Repositories
+	Organizations
+	Checks
+}
+
+// Client talks to the GitHub REST API.
+type Client struct {
+	rest    *api.RESTClient
+	baseURL string // empty means the default host of the REST client
+}
+
+// compile-time check to ensure Client implements the API interface
+var _ API = (*Client)(nil)
+
 // GetGitHubClient returns a GitHub API client using the default authentication method.
 func GetGitHubClient() (*api.RESTClient, error) {
 	client, err := api.DefaultRESTClient()
@@ -13,3 +37,89 @@ func GetGitHubClient() (*api.RESTClient, error) {
 
 	return client, nil
 }
+
+// New returns a Client using the default authentication method.
+func New() (*Client, error) {
+	rest, err := GetGitHubClient()
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(rest, ""), nil
+}
+
+// NewClient wraps the REST client. If baseURL is set, the requests are sent
+// there instead of the default host, e.g. to a httptest server.
+func NewClient(rest *api.RESTClient, baseURL string) *Client {
+	return &Client{
+		rest:    rest,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+func (c *Client) url(path string, query url.Values) string {
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	if c.baseURL == "" {
+		return path
+	}
+	return c.baseURL + "/" + path
+}
+
+func (c *Client) get(path string, query url.Values, resp any) error {
+	return c.rest.Get(c.url(path, query), resp)
+}
+
+func (c *Client) post(path string, body any, resp any) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	return c.rest.Post(c.url(path, nil), bytes.NewReader(payload), resp)
+}
+
+// getAll follows the pagination of list endpoints, collecting up to limit items (0 means no limit)
+func getAll[T any](c *Client, path string, query url.Values, limit int) ([]T, error) {
+	const perPage = 100
+
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set("per_page", fmt.Sprint(perPage))
+
+	var out []T
+	for page := 1; ; page++ {
+		query.Set("page", fmt.Sprint(page))
+		var items []T
+		if err := c.get(path, query, &items); err != nil {
+			return nil, err
+		}
+		out = append(out, items...)
+		if len(items) < perPage || limit > 0 && len(out) >= limit {
+			break
+		}
+	}
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+// ParseRepoURL extracts the owner and the repository name from a git remote URL,
+// both "git@github.com:org/repo.git" and "https://github.com/org/repo" forms are supported.
+func ParseRepoURL(remote string) (owner, name string, err error) {
+	path := remote
+	if u, err := url.Parse(remote); err == nil && u.Scheme != "" {
+		path = u.Path
+	} else if _, rest, ok := strings.Cut(remote, ":"); ok {
+		// scp-like syntax
+		path = rest
+	}
+
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	parts := strings.Split(path, "/")
+	if len(parts) < 2 || parts[len(parts)-2] == "" || parts[len(parts)-1] == "" {
+		return "", "", fmt.Errorf("can't find the repository in remote URL %q", remote)
+	}
+	return parts[len(parts)-2], parts[len(parts)-1], nil
+}
@@ -0,0 +1,38 @@
+package github
+
+import (
+	"net/url"
+)
+
+// Organizations is the part of the API used to look up the teams and members of an organization.
+type Organizations interface {
+	ListOrgTeams(org string) ([]Team, error)
+	ListOrgMembers(org string) ([]User, error)
+	// GetUser looks up a user outside the organization, e.g. an outside collaborator
+	GetUser(login string) (*User, error)
+}
+
+type Team struct {
+	Slug string `json:"slug"`
+	Name string `json:"name"`
+}
+
+type User struct {
+	Login string `json:"login"`
+}
+
+func (c *Client) ListOrgTeams(org string) ([]Team, error) {
+	return getAll[Team](c, "orgs/"+org+"/teams", nil, 0)
+}
+
+func (c *Client) ListOrgMembers(org string) ([]User, error) {
+	return getAll[User](c, "orgs/"+org+"/members", nil, 0)
+}
+
+func (c *Client) GetUser(login string) (*User, error) {
+	var u User
+	if err := c.get("users/"+url.PathEscape(login), nil, &u); err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
@@ -0,0 +1,51 @@
+package github
+
+import (
+	"net/url"
+)
+
+// PullRequests is the part of the API used to propose changes.
+type PullRequests interface {
+	GetRepository(owner, name string) (*Repository, error)
+	// FindPullRequest returns the open pull request for the branch, nil if there is none
+	FindPullRequest(owner, name, branch string) (*PullRequest, error)
+	CreatePullRequest(owner, name string, pr NewPullRequest) (*PullRequest, error)
+}
+
+type NewPullRequest struct {
+	Title string `json:"title"`
+	Head  string `json:"head"`
+	Base  string `json:"base"`
+	Body  string `json:"body"`
+	Draft bool   `json:"draft,omitempty"`
+}
+
+type PullRequest struct {
+	Number  int    `json:"number"`
+	Title   string `json:"title"`
+	HTMLURL string `json:"html_url"`
+	State   string `json:"state"`
+}
+
+func (c *Client) FindPullRequest(owner, name, branch string) (*PullRequest, error) {
+	query := url.Values{}
+	query.Set("head", owner+":"+branch)
+	query.Set("state", "open")
+
+	var prs []PullRequest
+	if err := c.get("repos/"+owner+"/"+name+"/pulls", query, &prs); err != nil {
+		return nil, err
+	}
+	if len(prs) == 0 {
+		return nil, nil
+	}
+	return &prs[0], nil
+}
+
+func (c *Client) CreatePullRequest(owner, name string, pr NewPullRequest) (*PullRequest, error) {
+	var created PullRequest
+	if err := c.post("repos/"+owner+"/"+name+"/pulls", pr, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
@@ -0,0 +1,131 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// newTestClient returns a client sending the requests to a httptest server with the handler
+func newTestClient(t *testing.T, handler http.Handler) *Client {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	rest, err := api.NewRESTClient(api.ClientOptions{
+		Host:         "github.com",
+		AuthToken:    "test",
+		LogIgnoreEnv: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewClient(rest, srv.URL)
+}
+
+func TestFindPullRequest(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/acme/gitops/pulls", func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("head") {
+		case "acme:gh-crossplane/team":
+			if state := r.URL.Query().Get("state"); state != "open" {
+				t.Errorf("state = %q, want open", state)
+			}
+			fmt.Fprint(w, `[{"number": 7, "title": "team: update", "html_url": "https://github.com/acme/gitops/pull/7", "state": "open"}]`)
+		default:
+			fmt.Fprint(w, `[]`)
+		}
+	})
+	c := newTestClient(t, mux)
+
+	pr, err := c.FindPullRequest("acme", "gitops", "gh-crossplane/team")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pr == nil || pr.Number != 7 || pr.HTMLURL != "https://github.com/acme/gitops/pull/7" {
+		t.Errorf("pull request = %+v, want #7", pr)
+	}
+
+	pr, err = c.FindPullRequest("acme", "gitops", "gh-crossplane/web")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pr != nil {
+		t.Errorf("pull request = %+v, want none", pr)
+	}
+}
+
+func TestCreatePullRequest(t *testing.T) {
+	want := NewPullRequest{
+		Title: "team: update",
+		Head:  "gh-crossplane/team",
+		Base:  "main",
+		Body:  "## Changes\n",
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /repos/acme/gitops/pulls", func(w http.ResponseWriter, r *http.Request) {
+		var got NewPullRequest
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode the request: %v", err)
+		}
+		if got != want {
+			t.Errorf("request = %+v, want %+v", got, want)
+		}
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"number": 8, "title": "team: update", "html_url": "https://github.com/acme/gitops/pull/8", "state": "open"}`)
+	})
+	c := newTestClient(t, mux)
+
+	pr, err := c.CreatePullRequest("acme", "gitops", want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pr.Number != 8 || pr.State != "open" {
+		t.Errorf("pull request = %+v, want the open #8", pr)
+	}
+}
+
+func TestCreatePullRequestError(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /repos/acme/gitops/pulls", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		fmt.Fprint(w, `{"message": "Validation Failed"}`)
+	})
+	c := newTestClient(t, mux)
+
+	if _, err := c.CreatePullRequest("acme", "gitops", NewPullRequest{}); err == nil {
+		t.Error("expected the error of the API")
+	}
+}
+
+func TestGetAllPages(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /orgs/acme/repos", func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		count := 100
+		if page == 2 {
+			count = 1
+		}
+		repos := make([]Repository, count)
+		for i := range repos {
+			repos[i].Name = fmt.Sprintf("repo-%d-%d", page, i)
+		}
+		json.NewEncoder(w).Encode(repos) //nolint:errcheck
+	})
+	c := newTestClient(t, mux)
+
+	repos, err := c.ListOrgRepositories("acme")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(repos) != 101 || repos[100].Name != "repo-2-0" {
+		t.Errorf("got %d repositories, want 101 from two pages", len(repos))
+	}
+}
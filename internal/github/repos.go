@@ -0,0 +1,150 @@
+package github
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// Repositories is the part of the API used to read the live repository settings.
+type Repositories interface {
+	ListOrgRepositories(org string) ([]Repository, error)
+	GetRepository(owner, name string) (*Repository, error)
+	ListRepositoryTeams(owner, name string) ([]TeamPermission, error)
+	ListBranchProtections(owner, name string) ([]BranchProtection, error)
+}
+
+// Repository holds the repository settings returned by the API.
+type Repository struct {
+	Name          string   `json:"name"`
+	FullName      string   `json:"full_name"`
+	Description   string   `json:"description"`
+	DefaultBranch string   `json:"default_branch"`
+	Visibility    string   `json:"visibility"`
+	Archived      bool     `json:"archived"`
+	Topics        []string `json:"topics"`
+
+	HasIssues           bool `json:"has_issues"`
+	HasDownloads        bool `json:"has_downloads"`
+	HasWiki             bool `json:"has_wiki"`
+	HasDiscussions      bool `json:"has_discussions"`
+	IsTemplate          bool `json:"is_template"`
+	AllowAutoMerge      bool `json:"allow_auto_merge"`
+	AllowSquashMerge    bool `json:"allow_squash_merge"`
+	AllowMergeCommit    bool `json:"allow_merge_commit"`
+	AllowRebaseMerge    bool `json:"allow_rebase_merge"`
+	AllowUpdateBranch   bool `json:"allow_update_branch"`
+	DeleteBranchOnMerge bool `json:"delete_branch_on_merge"`
+
+	MergeCommitTitle         string `json:"merge_commit_title"`
+	MergeCommitMessage       string `json:"merge_commit_message"`
+	SquashMergeCommitTitle   string `json:"squash_merge_commit_title"`
+	SquashMergeCommitMessage string `json:"squash_merge_commit_message"`
+
+	SecurityAndAnalysis *SecurityAndAnalysis `json:"security_and_analysis,omitempty"`
+}
+
+type SecurityAndAnalysis struct {
+	AdvancedSecurity             *SecurityStatus `json:"advanced_security,omitempty"`
+	SecretScanning               *SecurityStatus `json:"secret_scanning,omitempty"`
+	SecretScanningPushProtection *SecurityStatus `json:"secret_scanning_push_protection,omitempty"`
+}
+
+type SecurityStatus struct {
+	Status string `json:"status"`
+}
+
+// TeamPermission is a team with access to a repository
+type TeamPermission struct {
+	Slug       string `json:"slug"`
+	Name       string `json:"name"`
+	Permission string `json:"permission"`
+}
+
+// BranchProtection is the protection of a single branch
+type BranchProtection struct {
+	Branch                         string                      `json:"-"`
+	RequiredStatusChecks           *RequiredStatusChecks       `json:"required_status_checks,omitempty"`
+	EnforceAdmins                  *Enabled                    `json:"enforce_admins,omitempty"`
+	RequiredPullRequestReviews     *RequiredPullRequestReviews `json:"required_pull_request_reviews,omitempty"`
+	RequiredSignatures             *Enabled                    `json:"required_signatures,omitempty"`
+	RequiredConversationResolution *Enabled                    `json:"required_conversation_resolution,omitempty"`
+}
+
+type Enabled struct {
+	Enabled bool `json:"enabled"`
+}
+
+type RequiredStatusChecks struct {
+	Strict   bool     `json:"strict"`
+	Contexts []string `json:"contexts"`
+}
+
+type RequiredPullRequestReviews struct {
+	DismissStaleReviews          bool                   `json:"dismiss_stale_reviews"`
+	RequireCodeOwnerReviews      bool                   `json:"require_code_owner_reviews"`
+	RequiredApprovingReviewCount int                    `json:"required_approving_review_count"`
+	DismissalRestrictions        *DismissalRestrictions `json:"dismissal_restrictions,omitempty"`
+}
+
+type DismissalRestrictions struct {
+	Users []struct {
+		Login string `json:"login"`
+	} `json:"users"`
+	Teams []struct {
+		Slug string `json:"slug"`
+	} `json:"teams"`
+}
+
+func (c *Client) ListOrgRepositories(org string) ([]Repository, error) {
+	query := url.Values{}
+	query.Set("type", "all")
+	return getAll[Repository](c, "orgs/"+org+"/repos", query, 0)
+}
+
+func (c *Client) GetRepository(owner, name string) (*Repository, error) {
+	var repo Repository
+	if err := c.get("repos/"+owner+"/"+name, nil, &repo); err != nil {
+		return nil, err
+	}
+	return &repo, nil
+}
+
+func (c *Client) ListRepositoryTeams(owner, name string) ([]TeamPermission, error) {
+	return getAll[TeamPermission](c, "repos/"+owner+"/"+name+"/teams", nil, 0)
+}
+
+func (c *Client) ListBranchProtections(owner, name string) ([]BranchProtection, error) {
+	query := url.Values{}
+	query.Set("protected", "true")
+	branches, err := getAll[struct {
+		Name string `json:"name"`
+	}](c, "repos/"+owner+"/"+name+"/branches", query, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []BranchProtection
+	for _, b := range branches {
+		var p BranchProtection
+		err := c.get("repos/"+owner+"/"+name+"/branches/"+url.PathEscape(b.Name)+"/protection", nil, &p)
+		if IsNotFound(err) {
+			// protected by a ruleset, not by a classic protection rule
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		p.Branch = b.Name
+		out = append(out, p)
+	}
+	return out, nil
+}
+
+// IsNotFound reports whether the request failed because the resource doesn't exist
+func IsNotFound(err error) bool {
+	var httpErr *api.HTTPError
+	return errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusNotFound
+}
@@ -0,0 +1,136 @@
+package gitops
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/artemlive/gh-crossplane/internal/git"
+	"github.com/artemlive/gh-crossplane/internal/manifest"
+)
+
+// BranchPrefix is the prefix of the branches created by the tool.
+// Committing while on such a branch adds to it instead of creating another one.
+const BranchPrefix = "gh-crossplane/"
+
+// ErrNothingToCommit is returned when none of the group files has changed
+var ErrNothingToCommit = errors.New("no changed group files to commit")
+
+// CommitResult describes the commit made by CommitGroupChanges.
+type CommitResult struct {
+	Branch  string
+	Message string
+	Files   []string
+	Changes []manifest.Change
+}
+
+// CommitGroupChanges stages the changed group files and commits them
+// with a message generated from the semantic changes.
+// Unless HEAD is already on a branch created by the tool, a new branch is created first.
+// Other changes in the working tree and in the index are left alone.
+func CommitGroupChanges(repo git.Repository, groups []manifest.GroupFile, now time.Time) (*CommitResult, error) {
+	changed, err := repo.ChangedFiles()
+	if err != nil {
+		return nil, err
+	}
+	changedSet := make(map[string]bool, len(changed))
+	for _, path := range changed {
+		changedSet[canonical(path)] = true
+	}
+
+	result := &CommitResult{}
+	for _, gf := range groups {
+		if !changedSet[canonical(gf.Path)] {
+			continue
+		}
+		changes, err := groupChanges(repo, "HEAD", gf)
+		if err != nil {
+			return nil, err
+		}
+		result.Files = append(result.Files, gf.Path)
+		result.Changes = append(result.Changes, changes...)
+	}
+	if len(result.Files) == 0 {
+		return nil, ErrNothingToCommit
+	}
+
+	current, err := repo.CurrentBranch()
+	if err != nil {
+		return nil, err
+	}
+	result.Branch = current
+	if !strings.HasPrefix(current, BranchPrefix) {
+		result.Branch = BranchName(result.Changes, now)
+		if err := repo.CreateBranch(result.Branch); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := repo.Add(result.Files...); err != nil {
+		return nil, err
+	}
+	result.Message = manifest.CommitMessage(result.Changes)
+	if err := repo.Commit(result.Message, result.Files...); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+var nonSlugChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// BranchName generates a branch name from the names of the changed groups,
+// e.g. "gh-crossplane/team-x-20250102-150405".
+func BranchName(changes []manifest.Change, now time.Time) string {
+	var groups []string
+	seen := make(map[string]bool)
+	for _, c := range changes {
+		if !seen[c.Group] {
+			seen[c.Group] = true
+			groups = append(groups, c.Group)
+		}
+	}
+
+	slug := nonSlugChars.ReplaceAllString(strings.ToLower(strings.Join(groups, "-")), "-")
+	slug = strings.Trim(slug, "-")
+	if len(slug) > 40 {
+		slug = strings.Trim(slug[:40], "-")
+	}
+	if slug == "" {
+		slug = "update"
+	}
+	return fmt.Sprintf("%s%s-%s", BranchPrefix, slug, now.Format("20060102-150405"))
+}
+
+// groupChanges compares the group file with its version at the revision
+func groupChanges(repo git.Repository, rev string, gf manifest.GroupFile) ([]manifest.Change, error) {
+	content, err := repo.FileAt(rev, gf.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return manifest.NewGroupChanges(gf.Manifest), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	head, err := manifest.DecodeGroup(content)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal %s at %s: %w", gf.Path, rev, err)
+	}
+	return manifest.Changes(head, gf.Manifest), nil
+}
+
+// canonical returns an absolute path with resolved symlinks,
+// so paths reported by git can be compared with the loaded ones
+func canonical(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		return resolved
+	}
+	return abs
+}
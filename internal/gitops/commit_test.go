@@ -0,0 +1,138 @@
+package gitops
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/artemlive/gh-crossplane/internal/git"
+	"github.com/artemlive/gh-crossplane/internal/manifest"
+)
+
+const teamGroup = `apiVersion: github.platform.crossplane.io/v1alpha1
+kind: RepositoriesGroup
+metadata:
+  name: team
+spec:
+  visibility: private
+  repositories:
+    - name: api
+`
+
+// gitRun runs git in the dir and returns its trimmed output
+func gitRun(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// newTempRepo creates a repository with a committed group file and notes file
+func newTempRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	t.Setenv("GIT_AUTHOR_NAME", "test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	dir := t.TempDir()
+	gitRun(t, dir, "init", "-q", "-b", "main")
+	writeFile(t, filepath.Join(dir, "groups", "team.yaml"), teamGroup)
+	writeFile(t, filepath.Join(dir, "notes.txt"), "notes\n")
+	gitRun(t, dir, "add", ".")
+	gitRun(t, dir, "commit", "-q", "-m", "initial")
+	return dir
+}
+
+func TestCommitGroupChanges(t *testing.T) {
+	dir := newTempRepo(t)
+
+	// the user has staged an unrelated change before committing the groups
+	writeFile(t, filepath.Join(dir, "notes.txt"), "staged notes\n")
+	gitRun(t, dir, "add", "notes.txt")
+	writeFile(t, filepath.Join(dir, "groups", "team.yaml"), strings.Replace(teamGroup, "private", "public", 1))
+	writeFile(t, filepath.Join(dir, "groups", "web.yaml"), strings.Replace(teamGroup, "name: team", "name: web", 1))
+
+	loader := manifest.NewManifestLoader(filepath.Join(dir, "groups"))
+	if err := loader.LoadGroupsFromFS(); err != nil {
+		t.Fatal(err)
+	}
+	repo, err := git.Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC)
+	res, err := CommitGroupChanges(repo, loader.Groups(), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "gh-crossplane/team-web-20250102-150405"; res.Branch != want {
+		t.Errorf("branch = %q, want %q", res.Branch, want)
+	}
+	if branch := gitRun(t, dir, "rev-parse", "--abbrev-ref", "HEAD"); branch != res.Branch {
+		t.Errorf("HEAD is on %q, want %q", branch, res.Branch)
+	}
+
+	committed := strings.Split(gitRun(t, dir, "show", "--name-only", "--format=", "HEAD"), "\n")
+	slices.Sort(committed)
+	if want := []string{"groups/team.yaml", "groups/web.yaml"}; !slices.Equal(committed, want) {
+		t.Errorf("committed files = %v, want %v", committed, want)
+	}
+
+	if staged := gitRun(t, dir, "diff", "--cached", "--name-only"); staged != "notes.txt" {
+		t.Errorf("staged files after the commit = %q, want notes.txt", staged)
+	}
+	if msg := gitRun(t, dir, "log", "-1", "--format=%B"); msg != res.Message {
+		t.Errorf("commit message = %q, want %q", msg, res.Message)
+	}
+}
+
+func TestCommitGroupChangesOnToolBranch(t *testing.T) {
+	dir := newTempRepo(t)
+	gitRun(t, dir, "checkout", "-q", "-b", BranchPrefix+"team")
+	writeFile(t, filepath.Join(dir, "groups", "team.yaml"), strings.Replace(teamGroup, "private", "public", 1))
+
+	loader := manifest.NewManifestLoader(filepath.Join(dir, "groups"))
+	if err := loader.LoadGroupsFromFS(); err != nil {
+		t.Fatal(err)
+	}
+	repo, err := git.Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := CommitGroupChanges(repo, loader.Groups(), time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Branch != BranchPrefix+"team" {
+		t.Errorf("branch = %q, want the current branch", res.Branch)
+	}
+
+	if _, err := CommitGroupChanges(repo, loader.Groups(), time.Now()); err != ErrNothingToCommit {
+		t.Errorf("second commit error = %v, want ErrNothingToCommit", err)
+	}
+}
@@ -0,0 +1,132 @@
+package gitops
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/artemlive/gh-crossplane/internal/git"
+	"github.com/artemlive/gh-crossplane/internal/github"
+	"github.com/artemlive/gh-crossplane/internal/manifest"
+)
+
+// Remote is the git remote pointing to the GitOps repository
+const Remote = "origin"
+
+// ErrNotProposable is returned when HEAD isn't on a branch that can be proposed
+var ErrNotProposable = errors.New("commit the changes first, the current branch can't be proposed")
+
+// ProposeResult describes the pull request opened by Propose.
+type ProposeResult struct {
+	PullRequest *github.PullRequest
+	Existing    bool // the branch already had an open pull request
+	Changes     []manifest.Change
+	Repos       []string
+}
+
+// Propose pushes the current branch and opens a pull request against
+// the default branch of the GitOps repository. The body lists the semantic
+// changes of the group files and the repositories they affect.
+// If the branch already has an open pull request, it's only pushed.
+func Propose(repo git.Repository, gh github.PullRequests, groups []manifest.GroupFile) (*ProposeResult, error) {
+	branch, err := repo.CurrentBranch()
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(branch, BranchPrefix) {
+		return nil, ErrNotProposable
+	}
+
+	owner, name, err := RemoteRepository(repo)
+	if err != nil {
+		return nil, err
+	}
+	info, err := gh.GetRepository(owner, name)
+	if err != nil {
+		return nil, fmt.Errorf("get repository %s/%s: %w", owner, name, err)
+	}
+
+	changes, err := branchChanges(repo, Remote+"/"+info.DefaultBranch, groups)
+	if err != nil {
+		return nil, err
+	}
+	result := &ProposeResult{
+		Changes: changes,
+		Repos:   manifest.AffectedRepositories(changes, groups),
+	}
+
+	if err := repo.Push(Remote, branch); err != nil {
+		return nil, err
+	}
+
+	existing, err := gh.FindPullRequest(owner, name, branch)
+	if err != nil {
+		return nil, fmt.Errorf("find pull request for %s: %w", branch, err)
+	}
+	if existing != nil {
+		result.PullRequest = existing
+		result.Existing = true
+		return result, nil
+	}
+
+	message := manifest.CommitMessage(changes)
+	title, _, _ := strings.Cut(message, "\n")
+	pr, err := gh.CreatePullRequest(owner, name, github.NewPullRequest{
+		Title: title,
+		Head:  branch,
+		Base:  info.DefaultBranch,
+		Body:  PullRequestBody(result.Changes, result.Repos),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create pull request: %w", err)
+	}
+	result.PullRequest = pr
+	return result, nil
+}
+
+// RemoteRepository returns the owner and the name of the GitOps repository on GitHub
+func RemoteRepository(repo git.Repository) (owner, name string, err error) {
+	remoteURL, err := repo.RemoteURL(Remote)
+	if err != nil {
+		return "", "", err
+	}
+	return github.ParseRepoURL(remoteURL)
+}
+
+// PullRequestBody renders the markdown body of the pull request
+func PullRequestBody(changes []manifest.Change, repos []string) string {
+	var b strings.Builder
+
+	b.WriteString("## Changes\n\n")
+	if len(changes) == 0 {
+		b.WriteString("No semantic changes, only formatting.\n")
+	}
+	for _, c := range changes {
+		fmt.Fprintf(&b, "- **%s**: %s\n", c.Group, c.Summary)
+	}
+
+	if len(repos) > 0 {
+		b.WriteString("\n## Affected repositories\n\n")
+		for _, r := range repos {
+			fmt.Fprintf(&b, "- `%s`\n", r)
+		}
+	}
+	return b.String()
+}
+
+// branchChanges compares the group files with their version at the base revision
+func branchChanges(repo git.Repository, base string, groups []manifest.GroupFile) ([]manifest.Change, error) {
+	if _, err := repo.ResolveRevision(base); err != nil {
+		return nil, fmt.Errorf("%w, fetch it first", err)
+	}
+
+	var changes []manifest.Change
+	for _, gf := range groups {
+		c, err := groupChanges(repo, base, gf)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, c...)
+	}
+	return changes, nil
+}
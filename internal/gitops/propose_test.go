@@ -0,0 +1,135 @@
+package gitops
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/artemlive/gh-crossplane/internal/git"
+	"github.com/artemlive/gh-crossplane/internal/github"
+	"github.com/artemlive/gh-crossplane/internal/manifest"
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// fakeGitHub serves the part of the API used by Propose for acme/gitops
+type fakeGitHub struct {
+	t       *testing.T
+	created []github.NewPullRequest
+}
+
+func (f *fakeGitHub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/repos/acme/gitops":
+		fmt.Fprint(w, `{"name": "gitops", "default_branch": "main"}`)
+	case r.Method == http.MethodGet && r.URL.Path == "/repos/acme/gitops/pulls":
+		if len(f.created) == 0 {
+			fmt.Fprint(w, `[]`)
+			return
+		}
+		fmt.Fprint(w, `[{"number": 1, "html_url": "https://github.com/acme/gitops/pull/1", "state": "open"}]`)
+	case r.Method == http.MethodPost && r.URL.Path == "/repos/acme/gitops/pulls":
+		var pr github.NewPullRequest
+		if err := json.NewDecoder(r.Body).Decode(&pr); err != nil {
+			f.t.Errorf("decode the pull request: %v", err)
+		}
+		f.created = append(f.created, pr)
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"number": 1, "html_url": "https://github.com/acme/gitops/pull/1", "state": "open"}`)
+	default:
+		f.t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		http.NotFound(w, r)
+	}
+}
+
+func newFakeGitHub(t *testing.T) (*fakeGitHub, *github.Client) {
+	t.Helper()
+	fake := &fakeGitHub{t: t}
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+
+	rest, err := api.NewRESTClient(api.ClientOptions{
+		Host:         "github.com",
+		AuthToken:    "test",
+		LogIgnoreEnv: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fake, github.NewClient(rest, srv.URL)
+}
+
+func TestPropose(t *testing.T) {
+	dir := newTempRepo(t)
+
+	// the remote path ends with acme/gitops.git, so it's parsed as acme/gitops
+	remote := filepath.Join(t.TempDir(), "acme", "gitops.git")
+	gitRun(t, dir, "init", "-q", "--bare", remote)
+	gitRun(t, dir, "remote", "add", Remote, remote)
+	gitRun(t, dir, "push", "-q", Remote, "main")
+	gitRun(t, dir, "fetch", "-q", Remote)
+
+	writeFile(t, filepath.Join(dir, "groups", "team.yaml"), strings.Replace(teamGroup, "private", "public", 1))
+	loader := manifest.NewManifestLoader(filepath.Join(dir, "groups"))
+	if err := loader.LoadGroupsFromFS(); err != nil {
+		t.Fatal(err)
+	}
+	repo, err := git.Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	commit, err := CommitGroupChanges(repo, loader.Groups(), time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fake, gh := newFakeGitHub(t)
+	res, err := Propose(repo, gh, loader.Groups())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Existing || res.PullRequest == nil || res.PullRequest.Number != 1 {
+		t.Errorf("result = %+v, want the new pull request #1", res)
+	}
+	if len(fake.created) != 1 {
+		t.Fatalf("created %d pull requests, want 1", len(fake.created))
+	}
+	pr := fake.created[0]
+	if pr.Head != commit.Branch || pr.Base != "main" {
+		t.Errorf("pull request from %q to %q, want from %q to main", pr.Head, pr.Base, commit.Branch)
+	}
+	if title, _, _ := strings.Cut(commit.Message, "\n"); pr.Title != title {
+		t.Errorf("title = %q, want %q", pr.Title, title)
+	}
+	if !strings.Contains(pr.Body, "**team**") || !strings.Contains(pr.Body, "`api`") {
+		t.Errorf("body doesn't list the team group and the api repository:\n%s", pr.Body)
+	}
+	if pushed := gitRun(t, remote, "rev-parse", commit.Branch); pushed != gitRun(t, dir, "rev-parse", "HEAD") {
+		t.Errorf("the remote branch is at %s, want HEAD", pushed)
+	}
+
+	// the branch has the pull request now, proposing again only pushes it
+	res, err = Propose(repo, gh, loader.Groups())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Existing || len(fake.created) != 1 {
+		t.Errorf("existing = %v after %d pull requests, want the existing one", res.Existing, len(fake.created))
+	}
+}
+
+func TestProposeNeedsToolBranch(t *testing.T) {
+	dir := newTempRepo(t)
+	repo, err := git.Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, gh := newFakeGitHub(t)
+	if _, err := Propose(repo, gh, nil); err != ErrNotProposable {
+		t.Errorf("error = %v, want ErrNotProposable", err)
+	}
+}
@@ -0,0 +1,381 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+
+	"github.com/artemlive/gh-crossplane/internal/domain"
+	"github.com/artemlive/gh-crossplane/internal/github"
+	"github.com/artemlive/gh-crossplane/internal/manifest"
+)
+
+// LiveRepository holds the settings of a repository as they are on GitHub,
+// expressed as a group spec, so they can be compared field by field.
+type LiveRepository struct {
+	Name        string
+	Description string
+	Archived    bool
+	Spec        domain.RepositoriesGroupSpec
+}
+
+// Fetch reads the current settings of the repositories.
+func Fetch(gh github.Repositories, owner string, names []string) ([]LiveRepository, error) {
+	var out []LiveRepository
+	for _, name := range names {
+		repo, err := gh.GetRepository(owner, name)
+		if err != nil {
+			return nil, fmt.Errorf("get repository %s/%s: %w", owner, name, err)
+		}
+		teams, err := gh.ListRepositoryTeams(owner, name)
+		if err != nil {
+			return nil, fmt.Errorf("list teams of %s/%s: %w", owner, name, err)
+		}
+		protections, err := gh.ListBranchProtections(owner, name)
+		if err != nil {
+			return nil, fmt.Errorf("list branch protections of %s/%s: %w", owner, name, err)
+		}
+		out = append(out, ToLive(*repo, teams, protections, owner))
+	}
+	return out, nil
+}
+
+// ToLive converts the API responses to a LiveRepository.
+func ToLive(repo github.Repository, teams []github.TeamPermission, protections []github.BranchProtection, owner string) LiveRepository {
+	spec := domain.RepositoriesGroupSpec{
+		Visibility:               repo.Visibility,
+		DefaultBranch:            repo.DefaultBranch,
+		Topics:                   repo.Topics,
+		HasIssues:                &repo.HasIssues,
+		HasDownloads:             &repo.HasDownloads,
+		HasWiki:                  &repo.HasWiki,
+		HasDiscussions:           &repo.HasDiscussions,
+		IsTemplate:               &repo.IsTemplate,
+		AllowAutoMerge:           &repo.AllowAutoMerge,
+		AllowSquashMerge:         &repo.AllowSquashMerge,
+		AllowMergeCommit:         &repo.AllowMergeCommit,
+		AllowRebaseMerge:         &repo.AllowRebaseMerge,
+		AllowUpdateBranch:        &repo.AllowUpdateBranch,
+		DeleteBranchOnMerge:      &repo.DeleteBranchOnMerge,
+		MergeCommitTitle:         repo.MergeCommitTitle,
+		MergeCommitMessage:       repo.MergeCommitMessage,
+		SquashMergeCommitTitle:   repo.SquashMergeCommitTitle,
+		SquashMergeCommitMessage: repo.SquashMergeCommitMessage,
+	}
+
+	for _, t := range teams {
+		spec.Permissions = append(spec.Permissions, domain.Permission{
+			Team:       t.Slug,
+			Permission: t.Permission,
+		})
+	}
+	for _, p := range protections {
+		spec.Protections = append(spec.Protections, toProtection(p, owner))
+	}
+	if sa := toSecAnalysis(repo.SecurityAndAnalysis); sa != nil {
+		spec.SecurityAndAnalysis = []domain.SecAnalysis{*sa}
+	}
+
+	return LiveRepository{
+		Name:        repo.Name,
+		Description: repo.Description,
+		Archived:    repo.Archived,
+		Spec:        spec,
+	}
+}
+
+// NewGroup creates a group for the repositories. The settings shared by the repositories
+// go to the group spec, a value shared by most of them for the single value fields and
+// the items present in all of them for the lists. The rest is kept as repository overrides.
+// The returned warnings list the differences which can't be expressed per repository.
+func NewGroup(name, apiVersion string, live []LiveRepository) (domain.RepositoriesGroup, []string) {
+	group := domain.RepositoriesGroup{
+		APIVersion: apiVersion,
+		Kind:       domain.Kind,
+		Metadata:   domain.Metadata{Name: name},
+		Spec:       commonSpec(live),
+	}
+	warnings := AddToGroup(&group, live)
+	return group, warnings
+}
+
+// AddToGroup adds the repositories to the group, with the settings differing
+// from the group spec as repository overrides.
+// Repositories which are already in the group are skipped.
+func AddToGroup(group *domain.RepositoriesGroup, live []LiveRepository) []string {
+	var warnings []string
+	for _, l := range live {
+		if hasRepository(group, l.Name) {
+			warnings = append(warnings, fmt.Sprintf("%s: already in group %s, skipped", l.Name, group.Metadata.Name))
+			continue
+		}
+		repo, w := overrides(group.Spec, l)
+		group.Spec.Repositories = append(group.Spec.Repositories, repo)
+		warnings = append(warnings, w...)
+	}
+	return warnings
+}
+
+func hasRepository(group *domain.RepositoriesGroup, name string) bool {
+	for _, r := range group.Spec.Repositories {
+		if r.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// commonSpec factors the shared settings of the repositories up to the group level
+func commonSpec(live []LiveRepository) domain.RepositoriesGroupSpec {
+	var spec domain.RepositoriesGroupSpec
+	if len(live) == 0 {
+		return spec
+	}
+
+	out := reflect.ValueOf(&spec).Elem()
+	t := out.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name := t.Field(i).Name
+		if name == "Repositories" {
+			continue
+		}
+		values := make([]reflect.Value, len(live))
+		for j := range live {
+			values[j] = reflect.ValueOf(live[j].Spec).Field(i)
+		}
+		if domain.IsAdditive(name) {
+			out.Field(i).Set(intersection(values))
+		} else {
+			out.Field(i).Set(majority(values))
+		}
+	}
+	return spec
+}
+
+// overrides builds the repository entry with the settings which differ from the group spec
+func overrides(spec domain.RepositoriesGroupSpec, l LiveRepository) (domain.Repository, []string) {
+	repo := domain.Repository{
+		Name:        l.Name,
+		Description: l.Description,
+	}
+	if l.Archived {
+		repo.Archived = &l.Archived
+	}
+
+	var warnings []string
+	repoVal := reflect.ValueOf(&repo).Elem()
+	groupVal := reflect.ValueOf(spec)
+	liveVal := reflect.ValueOf(l.Spec)
+	t := groupVal.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		name := t.Field(i).Name
+		if name == "Repositories" {
+			continue
+		}
+		gv, lv := groupVal.Field(i), liveVal.Field(i)
+		repoField := repoVal.FieldByName(name)
+
+		if domain.IsAdditive(name) {
+			if extra := difference(lv, gv); extra.Len() > 0 {
+				repoField.Set(extra)
+			}
+			if missing := difference(gv, lv); missing.Len() > 0 {
+				warnings = append(warnings, fmt.Sprintf("%s: the group adds %s %s which are not set on GitHub", l.Name, name, formatValue(missing)))
+			}
+			continue
+		}
+
+		if reflect.DeepEqual(gv.Interface(), lv.Interface()) {
+			continue
+		}
+		if repoField.IsValid() {
+			repoField.Set(lv)
+			continue
+		}
+		if gv.IsZero() {
+			// not managed by the group
+			continue
+		}
+		warnings = append(warnings, fmt.Sprintf("%s: %s is %s on GitHub, but the group sets %s", l.Name, name, formatValue(lv), formatValue(gv)))
+	}
+	return repo, warnings
+}
+
+// majority returns the value shared by most of the repositories, the first one wins a tie
+func majority(values []reflect.Value) reflect.Value {
+	best, bestCount := values[0], 0
+	for _, v := range values {
+		count := 0
+		for _, other := range values {
+			if reflect.DeepEqual(v.Interface(), other.Interface()) {
+				count++
+			}
+		}
+		if count > bestCount {
+			best, bestCount = v, count
+		}
+	}
+	return best
+}
+
+// intersection returns the list items present in all the lists
+func intersection(lists []reflect.Value) reflect.Value {
+	out := reflect.MakeSlice(lists[0].Type(), 0, 0)
+	for i := 0; i < lists[0].Len(); i++ {
+		item := lists[0].Index(i)
+		inAll := true
+		for _, list := range lists[1:] {
+			if !contains(list, item) {
+				inAll = false
+				break
+			}
+		}
+		if inAll {
+			out = reflect.Append(out, item)
+		}
+	}
+	if out.Len() == 0 {
+		return reflect.Zero(lists[0].Type())
+	}
+	return out
+}
+
+// difference returns the items of a which are not in b
+func difference(a, b reflect.Value) reflect.Value {
+	out := reflect.MakeSlice(a.Type(), 0, 0)
+	for i := 0; i < a.Len(); i++ {
+		if !contains(b, a.Index(i)) {
+			out = reflect.Append(out, a.Index(i))
+		}
+	}
+	return out
+}
+
+func contains(list, item reflect.Value) bool {
+	for i := 0; i < list.Len(); i++ {
+		if reflect.DeepEqual(list.Index(i).Interface(), item.Interface()) {
+			return true
+		}
+	}
+	return false
+}
+
+func formatValue(v reflect.Value) string {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return "<unset>"
+		}
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.String {
+		return fmt.Sprintf("%q", v.String())
+	}
+	return fmt.Sprintf("%v", v.Interface())
+}
+
+func toProtection(p github.BranchProtection, owner string) domain.Protection {
+	out := domain.Protection{
+		Name:    p.Branch,
+		Pattern: p.Branch,
+	}
+	if p.EnforceAdmins != nil {
+		out.EnforceAdmins = p.EnforceAdmins.Enabled
+	}
+	if p.RequiredSignatures != nil {
+		out.RequireSignedCommits = p.RequiredSignatures.Enabled
+	}
+	if p.RequiredConversationResolution != nil {
+		out.RequireConversationResolution = p.RequiredConversationResolution.Enabled
+	}
+	if sc := p.RequiredStatusChecks; sc != nil {
+		out.RequiredStatusChecks = []domain.StatusCheck{{
+			Strict:   sc.Strict,
+			Contexts: sc.Contexts,
+		}}
+	}
+	if r := p.RequiredPullRequestReviews; r != nil {
+		review := domain.PRReview{
+			RequireCodeOwnerReviews:      r.RequireCodeOwnerReviews,
+			DismissStaleReviews:          r.DismissStaleReviews,
+			RequiredApprovingReviewCount: r.RequiredApprovingReviewCount,
+		}
+		if dr := r.DismissalRestrictions; dr != nil {
+			for _, u := range dr.Users {
+				review.DismissalRestrictions = append(review.DismissalRestrictions, u.Login)
+			}
+			for _, t := range dr.Teams {
+				review.DismissalRestrictions = append(review.DismissalRestrictions, owner+"/"+t.Slug)
+			}
+			review.RestrictDismissals = len(review.DismissalRestrictions) > 0
+		}
+		out.RequiredPullRequestReviews = []domain.PRReview{review}
+	}
+	return out
+}
+
+func toSecAnalysis(sa *github.SecurityAndAnalysis) *domain.SecAnalysis {
+	if sa == nil {
+		return nil
+	}
+	status := func(s *github.SecurityStatus) []domain.Status {
+		if s == nil {
+			return nil
+		}
+		return []domain.Status{{Status: s.Status}}
+	}
+	return &domain.SecAnalysis{
+		AdvancedSecurity:             status(sa.AdvancedSecurity),
+		SecretScanning:               status(sa.SecretScanning),
+		SecretScanningPushProtection: status(sa.SecretScanningPushProtection),
+	}
+}
+
+// Plan imports the repositories into the group with the given name,
+// creating a new group file if there is no such group yet.
+// Nothing is written, the caller saves the returned group file.
+func Plan(loader *manifest.ManifestLoader, groupName string, live []LiveRepository) (*manifest.GroupFile, []string) {
+	if gf := loader.GetGroup(groupName); gf != nil {
+		// don't append to the slice shared with the loaded copy
+		gf.Manifest.Spec.Repositories = slices.Clone(gf.Manifest.Spec.Repositories)
+		warnings := AddToGroup(&gf.Manifest, live)
+		return gf, warnings
+	}
+
+	group, warnings := NewGroup(groupName, loader.APIVersion(), live)
+	return &manifest.GroupFile{
+		Path:     loader.NewGroupPath(groupName),
+		Manifest: group,
+	}, warnings
+}
+
+// Validate checks the import into the group: a new group needs a valid name,
+// and the repositories can't be in another group already, since a repository
+// managed by two groups would be fought over.
+func Validate(groups []manifest.GroupFile, groupName string, names []string) error {
+	exists := slices.ContainsFunc(groups, func(g manifest.GroupFile) bool { return g.Manifest.Metadata.Name == groupName })
+	if !exists && !manifest.NamePattern.MatchString(groupName) {
+		return fmt.Errorf("invalid group name %q: it must consist of lowercase letters, digits and '-', and start and end with a letter or digit", groupName)
+	}
+
+	managed := ManagedRepositories(groups)
+	var errs []error
+	for _, name := range names {
+		if group, ok := managed[name]; ok && group != groupName {
+			errs = append(errs, fmt.Errorf("repository %s is already in group '%s'", name, group))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ManagedRepositories maps the names of the repositories in the groups to the group names.
+func ManagedRepositories(groups []manifest.GroupFile) map[string]string {
+	out := make(map[string]string)
+	for _, g := range groups {
+		for _, r := range g.Manifest.Spec.Repositories {
+			out[r.Name] = g.Manifest.Metadata.Name
+		}
+	}
+	return out
+}
@@ -0,0 +1,317 @@
+// Package keymap holds the key bindings of the editor. The default keys can be
+// changed with a yaml file next to the groups dir, mapping the names of the
+// bindings to their keys:
+//
+//	save: [ctrl+s, ctrl+w]
+//	quit: [ctrl+q]
+//	delete: [d, x]
+//	rename: []
+//
+// The bindings missing from the file keep their default keys, an empty list
+// turns the binding off. The bindings active at the same time must not share
+// a key, see scopes.
+package keymap
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/v2/key"
+	"github.com/charmbracelet/bubbles/v2/list"
+	"github.com/charmbracelet/bubbles/v2/viewport"
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the name of the key bindings file
+const FileName = "keys.yaml"
+
+// KeyMap is the set of the key bindings. The keys moving between the items use
+// j/k and h/l as well as the arrows, the keys leaving a text input don't,
+// since the letters are typed there.
+type KeyMap struct {
+	Help      key.Binding
+	Quit      key.Binding
+	ForceQuit key.Binding
+	Back      key.Binding
+
+	// moving in the lists and between the fields and the tabs
+	Up        key.Binding
+	Down      key.Binding
+	Left      key.Binding
+	Right     key.Binding
+	NextField key.Binding
+	PrevField key.Binding
+	// leaving a text input for the field above or below
+	FieldUp   key.Binding
+	FieldDown key.Binding
+
+	Select  key.Binding // picks the item of a list
+	Edit    key.Binding // starts editing the field
+	Done    key.Binding // finishes editing the field
+	Toggle  key.Binding // checks a checkbox or changes a value
+	Confirm key.Binding
+	Cancel  key.Binding
+	Add     key.Binding
+	Delete  key.Binding
+
+	// the group and the repository editors
+	Save      key.Binding
+	Commit    key.Binding
+	Propose   key.Binding
+	Render    key.Binding
+	Preset    key.Binding
+	Rename    key.Binding
+	OpenGroup key.Binding
+	XRDForm   key.Binding
+
+	// the prompt shown when the group changed on disk, only the prompt
+	// takes its keys, so they may be used by other bindings, e.g. k by Up
+	Merge  key.Binding
+	Reload key.Binding
+	Keep   key.Binding
+
+	// the permissions field
+	AddTeam         key.Binding
+	AddCollaborator key.Binding
+	Permission      key.Binding
+
+	// the branch protections field
+	EnforceAdmins          key.Binding
+	SignedCommits          key.Binding
+	ConversationResolution key.Binding
+	StrictChecks           key.Binding
+	AddCheck               key.Binding
+	RemoveCheck            key.Binding
+}
+
+// Keys are the active key bindings
+var Keys = Default()
+
+// Default returns the built-in key bindings
+func Default() KeyMap {
+	return KeyMap{
+		Help:      binding("?/f1", "show the keys", "?", "f1"),
+		Quit:      binding("q", "quit", "q"),
+		ForceQuit: binding("ctrl+c", "quit from anywhere", "ctrl+c"),
+		Back:      binding("esc", "back", "esc"),
+
+		Up:        binding("↑/k", "up", "up", "k"),
+		Down:      binding("↓/j", "down", "down", "j"),
+		Left:      binding("←/h", "previous tab", "left", "h"),
+		Right:     binding("→/l", "next tab", "right", "l"),
+		NextField: binding("tab", "next field", "tab"),
+		PrevField: binding("shift+tab", "previous field", "shift+tab"),
+		FieldUp:   binding("↑", "finish, field above", "up"),
+		FieldDown: binding("↓", "finish, field below", "down"),
+
+		Select:  binding("enter", "select", "enter"),
+		Edit:    binding("enter/i", "edit the field", "enter", "i"),
+		Done:    binding("enter", "finish editing", "enter"),
+		Toggle:  binding("space", "toggle", "space"),
+		Confirm: binding("y/enter", "confirm", "y", "enter"),
+		Cancel:  binding("n/esc/q", "cancel", "n", "esc", "q"),
+		Add:     binding("a", "add", "a"),
+		Delete:  binding("d", "delete", "d"),
+
+		Save:      binding("ctrl+s", "save", "ctrl+s"),
+		Commit:    binding("ctrl+g", "commit", "ctrl+g"),
+		Propose:   binding("ctrl+p", "propose a pull request", "ctrl+p"),
+		Render:    binding("ctrl+r", "render the resources", "ctrl+r"),
+		Preset:    binding("ctrl+t", "apply a preset", "ctrl+t"),
+		Rename:    binding("f2", "rename the group", "f2"),
+		OpenGroup: binding("ctrl+o", "open the group", "ctrl+o"),
+		XRDForm:   binding("ctrl+x", "edit with the XRD form", "ctrl+x"),
+
+		Merge:  binding("m", "merge their changes into my edits", "m"),
+		Reload: binding("r", "reload from disk, discard my edits", "r"),
+		Keep:   binding("k/esc", "keep my edits", "k", "esc"),
+
+		AddTeam:         binding("t", "add a team", "t"),
+		AddCollaborator: binding("c", "add a collaborator", "c"),
+		Permission:      binding("p/space", "change the permission", "p", "space"),
+
+		EnforceAdmins:          binding("e", "enforce for admins", "e"),
+		SignedCommits:          binding("s", "require signed commits", "s"),
+		ConversationResolution: binding("r", "require conversation resolution", "r"),
+		StrictChecks:           binding("t", "strict status checks", "t"),
+		AddCheck:               binding("c", "add a status check", "c"),
+		RemoveCheck:            binding("x", "remove the last status check", "x"),
+	}
+}
+
+func binding(help, desc string, keys ...string) key.Binding {
+	return key.NewBinding(key.WithKeys(keys...), key.WithHelp(help, desc))
+}
+
+// namedBinding is a binding with its name in the file
+type namedBinding struct {
+	name    string
+	binding *key.Binding
+}
+
+func (k *KeyMap) named() []namedBinding {
+	return []namedBinding{
+		{"help", &k.Help}, {"quit", &k.Quit}, {"forceQuit", &k.ForceQuit}, {"back", &k.Back},
+		{"up", &k.Up}, {"down", &k.Down}, {"left", &k.Left}, {"right", &k.Right},
+		{"nextField", &k.NextField}, {"prevField", &k.PrevField}, {"fieldUp", &k.FieldUp}, {"fieldDown", &k.FieldDown},
+		{"select", &k.Select}, {"edit", &k.Edit}, {"done", &k.Done}, {"toggle", &k.Toggle},
+		{"confirm", &k.Confirm}, {"cancel", &k.Cancel}, {"add", &k.Add}, {"delete", &k.Delete},
+		{"save", &k.Save}, {"commit", &k.Commit}, {"propose", &k.Propose}, {"render", &k.Render},
+		{"preset", &k.Preset}, {"rename", &k.Rename}, {"openGroup", &k.OpenGroup}, {"xrdForm", &k.XRDForm},
+		{"merge", &k.Merge}, {"reload", &k.Reload}, {"keep", &k.Keep},
+		{"addTeam", &k.AddTeam}, {"addCollaborator", &k.AddCollaborator}, {"permission", &k.Permission},
+		{"enforceAdmins", &k.EnforceAdmins}, {"signedCommits", &k.SignedCommits},
+		{"conversationResolution", &k.ConversationResolution}, {"strictChecks", &k.StrictChecks},
+		{"addCheck", &k.AddCheck}, {"removeCheck", &k.RemoveCheck},
+	}
+}
+
+// scopes are the groups of bindings which are active at the same time, e.g. the
+// keys of a list or of a field being edited. The bindings of a scope must not
+// share a key, the bindings of different scopes may: r reloads the group in the
+// conflict prompt and requires conversation resolution in the protections field.
+var scopes = []struct {
+	name     string
+	bindings []string
+}{
+	{"navigation", []string{
+		"help", "quit", "forceQuit", "back", "up", "down", "left", "right", "nextField", "prevField",
+		"edit", "toggle", "add", "delete", "save", "commit", "propose", "render", "preset", "rename",
+		"openGroup", "xrdForm",
+	}},
+	{"list", []string{"help", "quit", "forceQuit", "back", "up", "down", "select", "add"}},
+	{"editing", []string{"forceQuit", "back", "done", "fieldUp", "fieldDown", "nextField", "prevField", "save", "render"}},
+	{"permissions", []string{
+		"forceQuit", "back", "save", "render", "up", "down", "select", "addTeam", "addCollaborator",
+		"permission", "delete",
+	}},
+	{"protections", []string{
+		"forceQuit", "back", "save", "render", "up", "down", "add", "delete", "enforceAdmins",
+		"signedCommits", "conversationResolution", "strictChecks", "addCheck", "removeCheck",
+	}},
+	{"prompt", []string{"forceQuit", "merge", "reload", "keep"}},
+	{"confirm", []string{"forceQuit", "confirm", "cancel"}},
+}
+
+// Load reads the key bindings file. A missing file has no settings, the default keys are used.
+func Load(path string) (KeyMap, error) {
+	keys := Default()
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return keys, nil
+	}
+	if err != nil {
+		return KeyMap{}, err
+	}
+	var overrides map[string][]string
+	if err := yaml.Unmarshal(data, &overrides); err != nil {
+		return KeyMap{}, fmt.Errorf("parse %s: %w", path, err)
+	}
+	if err := keys.override(overrides); err != nil {
+		return KeyMap{}, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := keys.conflicts(); err != nil {
+		return KeyMap{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return keys, nil
+}
+
+// override replaces the keys of the bindings, the help shows the new keys
+func (k *KeyMap) override(overrides map[string][]string) error {
+	named := k.named()
+	var errs []error
+	for name, keys := range overrides {
+		i := slices.IndexFunc(named, func(n namedBinding) bool { return n.name == name })
+		if i < 0 {
+			errs = append(errs, fmt.Errorf("unknown key binding %s", name))
+			continue
+		}
+		b := named[i].binding
+		if len(keys) == 0 {
+			b.SetEnabled(false)
+			continue
+		}
+		b.SetKeys(keys...)
+		b.SetHelp(strings.Join(keys, "/"), b.Help().Desc)
+	}
+	// the map has no order, the errors are sorted to be stable
+	slices.SortFunc(errs, func(a, b error) int { return strings.Compare(a.Error(), b.Error()) })
+	return errors.Join(errs...)
+}
+
+// conflicts returns an error for every key shared by two enabled bindings of a scope
+func (k *KeyMap) conflicts() error {
+	bindings := make(map[string]*key.Binding)
+	for _, n := range k.named() {
+		bindings[n.name] = n.binding
+	}
+	var errs []error
+	for _, scope := range scopes {
+		used := make(map[string]string) // the key and the binding using it
+		for _, name := range scope.bindings {
+			b := bindings[name]
+			if !b.Enabled() {
+				continue
+			}
+			for _, pressed := range b.Keys() {
+				other, ok := used[pressed]
+				switch {
+				case !ok:
+					used[pressed] = name
+				case other != name:
+					errs = append(errs, fmt.Errorf("%s and %s both use %s in the %s keys", other, name, pressed, scope.name))
+				}
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Describe returns the binding with the description used by a screen,
+// e.g. "select group" for the Select binding
+func Describe(b key.Binding, desc string) key.Binding {
+	b.SetHelp(b.Help().Key, desc)
+	return b
+}
+
+// Hints lists the keys of the enabled bindings for a status line,
+// e.g. "enter select, esc back"
+func Hints(bindings ...key.Binding) string {
+	var out []string
+	for _, b := range bindings {
+		if b.Enabled() {
+			out = append(out, b.Help().Key+" "+b.Help().Desc)
+		}
+	}
+	return strings.Join(out, ", ")
+}
+
+// Name returns the keys of the binding for the messages, e.g. "ctrl+s" in
+// "press ctrl+s to save"
+func Name(b key.Binding) string {
+	return b.Help().Key
+}
+
+// Viewport returns the keys scrolling the previews, moving up and down with the
+// keys of the key map
+func Viewport() viewport.KeyMap {
+	km := viewport.DefaultKeyMap()
+	km.Up = Describe(Keys.Up, "scroll up")
+	km.Down = Describe(Keys.Down, "scroll down")
+	return km
+}
+
+// List returns the keys of the lists of the groups and the repositories, moving
+// with the keys of the key map. The help overlay replaces the help of the lists.
+func List() list.KeyMap {
+	km := list.DefaultKeyMap()
+	km.CursorUp = Keys.Up
+	km.CursorDown = Keys.Down
+	km.Quit = Keys.Quit
+	km.ShowFullHelp.Unbind()
+	km.CloseFullHelp.Unbind()
+	return km
+}
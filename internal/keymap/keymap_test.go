@@ -0,0 +1,77 @@
+package keymap
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestDefaultHasNoConflicts(t *testing.T) {
+	keys := Default()
+	if err := keys.conflicts(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestScopesNameKnownBindings(t *testing.T) {
+	keys := Default()
+	named := keys.named()
+	for _, scope := range scopes {
+		for _, name := range scope.bindings {
+			if !slices.ContainsFunc(named, func(n namedBinding) bool { return n.name == name }) {
+				t.Errorf("scope %s has unknown binding %s", scope.name, name)
+			}
+		}
+	}
+}
+
+func TestLoadOverrides(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	if err := os.WriteFile(path, []byte("save: [ctrl+w]\nrename: []\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	keys, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(keys.Save.Keys(), []string{"ctrl+w"}) || keys.Save.Help().Key != "ctrl+w" {
+		t.Errorf("save = %v (%s), want ctrl+w", keys.Save.Keys(), keys.Save.Help().Key)
+	}
+	if keys.Rename.Enabled() {
+		t.Error("rename is enabled, want it turned off")
+	}
+}
+
+func TestLoadConflicts(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"moved keys", "save: [ctrl+w]\nquit: [ctrl+q]\n", ""},
+		// j is Down in the editor, but only the prompt takes the keys of merge
+		{"other scope", "merge: [j]\n", ""},
+		{"shadows quit", "save: [q]\n", "quit and save both use q in the navigation keys"},
+		{"shadows up", "add: [k]\n", "up and add both use k in the navigation keys"},
+		{"prompt", "merge: [r]\n", "merge and reload both use r in the prompt keys"},
+		// the conflict is gone with the other binding turned off
+		{"turned off", "save: [q]\nquit: []\n", ""},
+		{"unknown", "safe: [ctrl+s]\n", "unknown key binding safe"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), FileName)
+			if err := os.WriteFile(path, []byte(tc.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := Load(path)
+			switch {
+			case tc.wantErr == "" && err != nil:
+				t.Errorf("Load = %v", err)
+			case tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)):
+				t.Errorf("Load = %v, want an error with %q", err, tc.wantErr)
+			}
+		})
+	}
+}
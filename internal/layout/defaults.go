@@ -0,0 +1,141 @@
+package layout
+
+// DefaultTabs is the built-in layout of the group editor
+var DefaultTabs = []Tab{
+	{
+		Name: "Group: General",
+		Fields: []string{
+			"Spec.Visibility",
+			"Spec.DefaultBranch",
+			"Spec.Topics",
+			"Spec.ArchiveOnDestroy",
+			"Spec.AutoInit",
+			"Spec.IsTemplate",
+			"Spec.ManagementPolicies",
+			"Spec.DeletionPolicy",
+		},
+		Kind: KindFields,
+	},
+	{
+		Name: "Group: Metadata",
+		Fields: []string{
+			"Metadata.Labels",
+			"Metadata.Annotations",
+		},
+		Kind: KindFields,
+	},
+	{
+		Name: "Group: Features",
+		Fields: []string{
+			"Spec.HasIssues",
+			"Spec.HasDownloads",
+			"Spec.HasWiki",
+			"Spec.HasDiscussions",
+			"Spec.AllowAutoMerge",
+			"Spec.AllowSquashMerge",
+			"Spec.AllowMergeCommit",
+			"Spec.AllowRebaseMerge",
+			"Spec.AllowUpdateBranch",
+			"Spec.DeleteBranchOnMerge",
+			"Spec.VulnerabilityAlerts",
+		},
+		Kind: KindFields,
+	},
+	{
+		Name: "Group: Merge Messages",
+		Fields: []string{
+			"Spec.MergeCommitMessage",
+			"Spec.MergeCommitTitle",
+			"Spec.SquashMergeCommitMessage",
+			"Spec.SquashMergeCommitTitle",
+		},
+		Kind: KindFields,
+	},
+	{
+		Name: "Group: Protections",
+		Fields: []string{
+			"Spec.Protections",
+		},
+		Kind: KindFields,
+	},
+	{
+		Name: "Group: Security",
+		Fields: []string{
+			"Spec.SecurityAndAnalysis",
+		},
+		Kind: KindFields,
+	},
+	{
+		Name: "Group: Autolinks",
+		Fields: []string{
+			"Spec.AutolinkReferences",
+		},
+		Kind: KindFields,
+	},
+	{
+		Name: "Group: Permissions",
+		Fields: []string{
+			"Spec.Permissions",
+		},
+		Kind: KindFields,
+	},
+	{
+		Name: "Repositories",
+		Fields: []string{
+			"Spec.Repositories",
+		},
+		Kind: KindRepositories,
+	},
+}
+
+// DefaultRepositoryTabs is the built-in layout of the repository editor
+var DefaultRepositoryTabs = []Tab{
+	{
+		Name: "General",
+		Fields: []string{
+			"Name",
+			"Description",
+			"Visibility",
+			"DefaultBranch",
+			"Topics",
+			"Archived",
+		},
+		Kind: KindFields,
+	},
+	{
+		Name: "Features",
+		Fields: []string{
+			"AllowAutoMerge",
+			"DeleteBranchOnMerge",
+		},
+		Kind: KindFields,
+	},
+	{
+		Name: "Permissions",
+		Fields: []string{
+			"Permissions",
+		},
+		Kind: KindFields,
+	},
+	{
+		Name: "Protections",
+		Fields: []string{
+			"Protections",
+		},
+		Kind: KindFields,
+	},
+	{
+		Name: "Security",
+		Fields: []string{
+			"SecurityAndAnalysis",
+		},
+		Kind: KindFields,
+	},
+	{
+		Name: "Annotations",
+		Fields: []string{
+			"Annotations",
+		},
+		Kind: KindFields,
+	},
+}
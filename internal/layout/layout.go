@@ -0,0 +1,305 @@
+// Package layout arranges the fields of the editor in tabs. The built-in layout
+// can be replaced by a yaml file next to the groups dir, as a whole or for the
+// groups of a team:
+//
+//	tabs:
+//	  - name: General
+//	    fields: [spec.visibility, spec.defaultBranch, metadata.labels]
+//	    labels:
+//	      spec.defaultBranch: Main Branch
+//	  - name: Repositories
+//	    kind: repositories
+//	hidden: [spec.isTemplate, archived]
+//	repositoryTabs:
+//	  - name: Repository
+//	    fields: [name, description, visibility, topics]
+//	views:
+//	  - name: platform
+//	    selector:
+//	      labels: {team: platform}
+//	    hidden: [spec.hasWiki]
+//
+// The fields are the yaml paths in the group, the fields of the repository tabs and
+// the hidden fields without a "spec." or "metadata." prefix are the fields of the
+// repositories. The first view selecting a group replaces the tabs and the
+// repository tabs it sets, its hidden fields are added to the others.
+package layout
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/artemlive/gh-crossplane/internal/domain"
+	"github.com/artemlive/gh-crossplane/internal/util"
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the name of the layout file
+const FileName = "layout.yaml"
+
+// TabKind selects how a tab is shown and edited
+type TabKind string
+
+const (
+	KindFields       TabKind = "fields"       // fields of the group
+	KindRepositories TabKind = "repositories" // the list of the repositories
+)
+
+// Tab is a tab of the group editor or of the repository editor
+type Tab struct {
+	Name string  `yaml:"name"`
+	Kind TabKind `yaml:"kind,omitempty"` // fields if empty
+	// Fields are dot-separated paths of the fields, e.g. "Spec.Visibility"
+	Fields []string `yaml:"fields,omitempty"`
+	// Labels replace the labels of the fields, by path
+	Labels      map[string]string `yaml:"labels,omitempty"`
+	Description string            `yaml:"description,omitempty"`
+}
+
+// Layout is the resolved layout of a group
+type Layout struct {
+	Tabs []Tab
+	// RepositoryTabs are the tabs of the repository editor, the fields are
+	// the paths in a repository, e.g. "Description"
+	RepositoryTabs []Tab
+}
+
+type File struct {
+	Tabs           []Tab    `yaml:"tabs,omitempty"`
+	Hidden         []string `yaml:"hidden,omitempty"`
+	RepositoryTabs []Tab    `yaml:"repositoryTabs,omitempty"`
+	Views          []View   `yaml:"views,omitempty"`
+}
+
+// View is the layout of the groups of a team
+type View struct {
+	Name           string   `yaml:"name"`
+	Selector       Selector `yaml:"selector"`
+	Tabs           []Tab    `yaml:"tabs,omitempty"`
+	Hidden         []string `yaml:"hidden,omitempty"`
+	RepositoryTabs []Tab    `yaml:"repositoryTabs,omitempty"`
+}
+
+// Selector chooses the groups a view applies to
+type Selector struct {
+	Groups []string          `yaml:"groups,omitempty"` // group name patterns, e.g. "platform-*"
+	Labels map[string]string `yaml:"labels,omitempty"` // labels of the group
+}
+
+// Matches reports whether the selector chooses the group, an empty one chooses none
+func (s Selector) Matches(g domain.RepositoriesGroup) bool {
+	if len(s.Groups) == 0 && len(s.Labels) == 0 {
+		return false
+	}
+	if len(s.Groups) > 0 && !slices.ContainsFunc(s.Groups, func(p string) bool {
+		ok, _ := path.Match(p, g.Metadata.Name)
+		return ok
+	}) {
+		return false
+	}
+	for k, v := range s.Labels {
+		if g.Metadata.Labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// Load reads the layout file. A missing file has no settings, the built-in layout is used.
+func Load(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &File{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var f File
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	if err := f.resolve(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &f, nil
+}
+
+// For returns the layout of the group. The file may be nil.
+func (f *File) For(g domain.RepositoriesGroup) Layout {
+	out := Layout{Tabs: DefaultTabs, RepositoryTabs: DefaultRepositoryTabs}
+	if f == nil {
+		return out
+	}
+	hidden := f.Hidden
+	if len(f.Tabs) > 0 {
+		out.Tabs = f.Tabs
+	}
+	if len(f.RepositoryTabs) > 0 {
+		out.RepositoryTabs = f.RepositoryTabs
+	}
+	for _, v := range f.Views {
+		if !v.Selector.Matches(g) {
+			continue
+		}
+		if len(v.Tabs) > 0 {
+			out.Tabs = v.Tabs
+		}
+		if len(v.RepositoryTabs) > 0 {
+			out.RepositoryTabs = v.RepositoryTabs
+		}
+		hidden = append(slices.Clone(hidden), v.Hidden...)
+		break
+	}
+
+	if len(hidden) == 0 {
+		return out
+	}
+	out.Tabs = hide(out.Tabs, hidden)
+	if len(out.Tabs) == 0 {
+		// the editor needs a tab, the repositories are left
+		out.Tabs = []Tab{{Name: "Repositories", Kind: KindRepositories}}
+	}
+	out.RepositoryTabs = hide(out.RepositoryTabs, hidden)
+	if len(out.RepositoryTabs) == 0 {
+		out.RepositoryTabs = []Tab{{Name: "General", Kind: KindFields, Fields: []string{"Name"}}}
+	}
+	return out
+}
+
+// hide drops the hidden fields from the tabs, and the tabs of fields left without any
+func hide(tabs []Tab, hidden []string) []Tab {
+	var out []Tab
+	for _, t := range tabs {
+		t.Fields = slices.DeleteFunc(slices.Clone(t.Fields), func(p string) bool { return slices.Contains(hidden, p) })
+		if t.Kind == KindFields && len(t.Fields) == 0 {
+			continue
+		}
+		out = append(out, t)
+	}
+	return out
+}
+
+// resolve checks the file and turns the yaml paths into the paths of the domain types
+func (f *File) resolve() error {
+	var errs []error
+	errs = append(errs, resolveTabs(f.Tabs, groupType, "")...)
+	errs = append(errs, resolveHidden(f.Hidden, "")...)
+	errs = append(errs, resolveTabs(f.RepositoryTabs, repoType, "repository ")...)
+	for i := range f.Views {
+		v := &f.Views[i]
+		where := fmt.Sprintf("view %s: ", v.Name)
+		if v.Name == "" {
+			errs = append(errs, fmt.Errorf("view %d has no name", i+1))
+		}
+		if len(v.Selector.Groups) == 0 && len(v.Selector.Labels) == 0 {
+			errs = append(errs, fmt.Errorf("%sthe selector is empty", where))
+		}
+		errs = append(errs, resolveTabs(v.Tabs, groupType, where)...)
+		errs = append(errs, resolveHidden(v.Hidden, where)...)
+		errs = append(errs, resolveTabs(v.RepositoryTabs, repoType, where+"repository ")...)
+	}
+	return errors.Join(errs...)
+}
+
+var (
+	groupType = reflect.TypeOf(domain.RepositoriesGroup{})
+	repoType  = reflect.TypeOf(domain.Repository{})
+)
+
+// resolveTabs resolves the fields of the tabs in the type, the group or a repository.
+// The repository editor has no list of repositories.
+func resolveTabs(tabs []Tab, t reflect.Type, where string) []error {
+	var errs []error
+	for i := range tabs {
+		tab := &tabs[i]
+		if tab.Name == "" {
+			errs = append(errs, fmt.Errorf("%stab %d has no name", where, i+1))
+		}
+		switch tab.Kind {
+		case "":
+			tab.Kind = KindFields
+		case KindFields:
+		case KindRepositories:
+			if t == repoType {
+				errs = append(errs, fmt.Errorf("%stab %s: a repository has no %s", where, tab.Name, KindRepositories))
+			}
+		default:
+			errs = append(errs, fmt.Errorf("%stab %s: unknown kind %q, it must be %s or %s", where, tab.Name, tab.Kind, KindFields, KindRepositories))
+		}
+		labels := make(map[string]string, len(tab.Labels))
+		for j, p := range tab.Fields {
+			resolved, err := fieldPath(t, p)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%stab %s: %w", where, tab.Name, err))
+				continue
+			}
+			tab.Fields[j] = resolved
+		}
+		for p, label := range tab.Labels {
+			resolved, err := fieldPath(t, p)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%stab %s: %w", where, tab.Name, err))
+				continue
+			}
+			labels[resolved] = label
+		}
+		tab.Labels = labels
+	}
+	return errs
+}
+
+// resolveHidden resolves the paths of the group fields, the others are repository fields
+func resolveHidden(hidden []string, where string) []error {
+	var errs []error
+	for i, p := range hidden {
+		t := repoType
+		if isGroupPath(p) {
+			t = groupType
+		}
+		resolved, err := fieldPath(t, p)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%shidden: %w", where, err))
+			continue
+		}
+		hidden[i] = resolved
+	}
+	return errs
+}
+
+func isGroupPath(p string) bool {
+	first, _, _ := strings.Cut(p, ".")
+	return strings.EqualFold(first, "spec") || strings.EqualFold(first, "metadata")
+}
+
+// fieldPath turns a yaml path such as "spec.defaultBranch" into the path of the
+// struct fields, "Spec.DefaultBranch". The names of the struct fields are accepted too.
+func fieldPath(t reflect.Type, p string) (string, error) {
+	var out []string
+	for _, part := range strings.Split(p, ".") {
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct {
+			return "", fmt.Errorf("unknown field %s", p)
+		}
+		found := false
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			if sf.Name == part || util.YAMLName(sf) == part {
+				out = append(out, sf.Name)
+				t = sf.Type
+				found = true
+				break
+			}
+		}
+		if !found {
+			return "", fmt.Errorf("unknown field %s", p)
+		}
+	}
+	return strings.Join(out, "."), nil
+}
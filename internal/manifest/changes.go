@@ -0,0 +1,352 @@
+package manifest
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/artemlive/gh-crossplane/internal/domain"
+	"github.com/artemlive/gh-crossplane/internal/util"
+)
+
+// Change is a single semantic change between two versions of a group,
+// e.g. "add foo to team-x group" or "enable signed commits in protection main".
+type Change struct {
+	Group   string
+	Path    string // yaml path of the changed field, e.g. "spec.repositories[foo].description"
+	Repo    string // affected repository, empty if the change applies to the whole group
+	Summary string
+}
+
+// Changes describes what changed between two versions of a group.
+func Changes(base, mine domain.RepositoriesGroup) []Change {
+	group := mine.Metadata.Name
+	d := differ{group: group}
+
+	if base.Metadata.Name != "" && base.Metadata.Name != group {
+		d.add("metadata.name", "", fmt.Sprintf("rename group %s to %s", base.Metadata.Name, group))
+	}
+	if !isEmptyEqual(reflect.ValueOf(base.Metadata.Labels), reflect.ValueOf(mine.Metadata.Labels)) {
+		d.add("metadata.labels", "", fmt.Sprintf("update %s group labels", group))
+	}
+	if !isEmptyEqual(reflect.ValueOf(base.Metadata.Annotations), reflect.ValueOf(mine.Metadata.Annotations)) {
+		d.add("metadata.annotations", "", fmt.Sprintf("update %s group annotations", group))
+	}
+
+	d.repositories(base.Spec.Repositories, mine.Spec.Repositories)
+	d.structFields("spec", reflect.ValueOf(base.Spec), reflect.ValueOf(mine.Spec), "", "")
+
+	return d.changes
+}
+
+// NewGroupChanges describes a group which didn't exist before.
+func NewGroupChanges(g domain.RepositoriesGroup) []Change {
+	changes := []Change{{
+		Group:   g.Metadata.Name,
+		Path:    "metadata.name",
+		Summary: fmt.Sprintf("add %s group", g.Metadata.Name),
+	}}
+	for _, repo := range g.Spec.Repositories {
+		changes = append(changes, Change{
+			Group:   g.Metadata.Name,
+			Path:    fmt.Sprintf("spec.repositories[%s]", repo.Name),
+			Repo:    repo.Name,
+			Summary: fmt.Sprintf("add %s to %s group", repo.Name, g.Metadata.Name),
+		})
+	}
+	return changes
+}
+
+// CommitMessage generates a commit message for the changes.
+// Short summaries fit into the subject, otherwise they are listed in the body.
+func CommitMessage(changes []Change) string {
+	const maxSubject = 72
+
+	var summaries []string
+	var groups []string
+	for _, c := range changes {
+		summaries = append(summaries, c.Summary)
+		if !slices.Contains(groups, c.Group) {
+			groups = append(groups, c.Group)
+		}
+	}
+	if len(summaries) == 0 {
+		return "repos: update repositories groups"
+	}
+
+	subject := "repos: " + strings.Join(summaries, "; ")
+	if len(subject) <= maxSubject {
+		return subject
+	}
+
+	subject = fmt.Sprintf("repos: update %s group", strings.Join(groups, ", "))
+	if len(groups) > 1 {
+		subject += "s"
+	}
+	var body []string
+	for _, s := range summaries {
+		body = append(body, "- "+s)
+	}
+	return subject + "\n\n" + strings.Join(body, "\n")
+}
+
+// AffectedRepositories returns the repositories affected by the changes.
+// Group level changes affect every repository of the group.
+func AffectedRepositories(changes []Change, groups []GroupFile) []string {
+	var repos []string
+	addRepo := func(name string) {
+		if !slices.Contains(repos, name) {
+			repos = append(repos, name)
+		}
+	}
+
+	for _, c := range changes {
+		if c.Repo != "" {
+			addRepo(c.Repo)
+			continue
+		}
+		for _, g := range groups {
+			if g.Manifest.Metadata.Name != c.Group {
+				continue
+			}
+			for _, r := range g.Manifest.Spec.Repositories {
+				addRepo(r.Name)
+			}
+		}
+	}
+	slices.Sort(repos)
+	return repos
+}
+
+type differ struct {
+	group   string
+	changes []Change
+}
+
+func (d *differ) add(path, repo, summary string) {
+	d.changes = append(d.changes, Change{
+		Group:   d.group,
+		Path:    path,
+		Repo:    repo,
+		Summary: summary,
+	})
+}
+
+func (d *differ) repositories(base, mine []domain.Repository) {
+	baseByName := make(map[string]domain.Repository, len(base))
+	for _, r := range base {
+		baseByName[r.Name] = r
+	}
+	mineByName := make(map[string]bool, len(mine))
+
+	for _, r := range mine {
+		mineByName[r.Name] = true
+		path := fmt.Sprintf("spec.repositories[%s]", r.Name)
+		old, ok := baseByName[r.Name]
+		if !ok {
+			d.add(path, r.Name, fmt.Sprintf("add %s to %s group", r.Name, d.group))
+			continue
+		}
+		d.structFields(path, reflect.ValueOf(old), reflect.ValueOf(r), " for "+r.Name, r.Name)
+	}
+	for _, r := range base {
+		if !mineByName[r.Name] {
+			d.add(fmt.Sprintf("spec.repositories[%s]", r.Name), r.Name, fmt.Sprintf("remove %s from %s group", r.Name, d.group))
+		}
+	}
+}
+
+// structFields compares the fields of two structs of the same type.
+// scope is appended to every summary, e.g. " for foo".
+func (d *differ) structFields(path string, base, mine reflect.Value, scope, repo string) {
+	t := mine.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name := util.YAMLName(sf)
+		if name == "repositories" || name == "name" {
+			// repositories are compared separately, names identify the items
+			continue
+		}
+		bv, mv := base.Field(i), mine.Field(i)
+		if isEmptyEqual(bv, mv) {
+			continue
+		}
+		d.value(path+"."+name, fieldLabel(sf), bv, mv, scope, repo)
+	}
+}
+
+func (d *differ) value(path, label string, base, mine reflect.Value, scope, repo string) {
+	switch mine.Kind() {
+	case reflect.Ptr:
+		if mine.IsNil() {
+			d.add(path, repo, fmt.Sprintf("unset %s%s", label, scope))
+			return
+		}
+		var elem reflect.Value
+		if base.IsNil() {
+			elem = reflect.Zero(mine.Type().Elem())
+		} else {
+			elem = base.Elem()
+		}
+		d.value(path, label, elem, mine.Elem(), scope, repo)
+	case reflect.Bool:
+		verb := "disable"
+		if mine.Bool() {
+			verb = "enable"
+		}
+		d.add(path, repo, fmt.Sprintf("%s %s%s", verb, toggleLabel(label), scope))
+	case reflect.String:
+		if mine.String() == "" {
+			d.add(path, repo, fmt.Sprintf("unset %s%s", label, scope))
+			return
+		}
+		d.add(path, repo, fmt.Sprintf("set %s to %q%s", label, mine.String(), scope))
+	case reflect.Int:
+		d.add(path, repo, fmt.Sprintf("set %s to %d%s", label, mine.Int(), scope))
+	case reflect.Slice:
+		d.slice(path, label, base, mine, scope, repo)
+	default:
+		d.add(path, repo, fmt.Sprintf("update %s%s", label, scope))
+	}
+}
+
+func (d *differ) slice(path, label string, base, mine reflect.Value, scope, repo string) {
+	elemType := mine.Type().Elem()
+
+	switch {
+	case elemType.Kind() == reflect.String:
+		for _, v := range missingStrings(mine, base) {
+			d.add(path, repo, fmt.Sprintf("add %q to %s%s", v, label, scope))
+		}
+		for _, v := range missingStrings(base, mine) {
+			d.add(path, repo, fmt.Sprintf("remove %q from %s%s", v, label, scope))
+		}
+	case elemType == reflect.TypeOf(domain.Permission{}):
+		d.permissions(path, base.Interface().([]domain.Permission), mine.Interface().([]domain.Permission), scope, repo)
+	case elemType.Kind() == reflect.Struct && hasNameField(elemType):
+		d.namedItems(path, label, base, mine, scope, repo)
+	case elemType.Kind() == reflect.Struct && base.Len() <= 1 && mine.Len() == 1:
+		// single item lists like requiredStatusChecks are compared field by field
+		old := reflect.Zero(elemType)
+		if base.Len() == 1 {
+			old = base.Index(0)
+		}
+		d.structFields(path, old, mine.Index(0), scope, repo)
+	default:
+		d.add(path, repo, fmt.Sprintf("update %s%s", label, scope))
+	}
+}
+
+func (d *differ) namedItems(path, label string, base, mine reflect.Value, scope, repo string) {
+	kind := strings.TrimSuffix(label, "s")
+
+	baseByName := make(map[string]reflect.Value)
+	for i := 0; i < base.Len(); i++ {
+		baseByName[base.Index(i).FieldByName("Name").String()] = base.Index(i)
+	}
+	seen := make(map[string]bool)
+
+	for i := 0; i < mine.Len(); i++ {
+		item := mine.Index(i)
+		name := item.FieldByName("Name").String()
+		seen[name] = true
+		itemPath := fmt.Sprintf("%s[%s]", path, name)
+		old, ok := baseByName[name]
+		if !ok {
+			d.add(itemPath, repo, fmt.Sprintf("add %s %s%s", kind, name, scope))
+			continue
+		}
+		d.structFields(itemPath, old, item, fmt.Sprintf(" in %s %s%s", kind, name, scope), repo)
+	}
+	for i := 0; i < base.Len(); i++ {
+		name := base.Index(i).FieldByName("Name").String()
+		if !seen[name] {
+			d.add(fmt.Sprintf("%s[%s]", path, name), repo, fmt.Sprintf("remove %s %s%s", kind, name, scope))
+		}
+	}
+}
+
+func (d *differ) permissions(path string, base, mine []domain.Permission, scope, repo string) {
+	subject := func(p domain.Permission) string {
+		if p.Team != "" {
+			return "team " + p.Team
+		}
+		return "collaborator " + p.Collaborator
+	}
+
+	baseBySubject := make(map[string]domain.Permission, len(base))
+	for _, p := range base {
+		baseBySubject[subject(p)] = p
+	}
+	seen := make(map[string]bool)
+
+	for _, p := range mine {
+		s := subject(p)
+		seen[s] = true
+		old, ok := baseBySubject[s]
+		switch {
+		case !ok:
+			d.add(path, repo, fmt.Sprintf("grant %s to %s%s", p.Permission, s, scope))
+		case old.Permission != p.Permission:
+			d.add(path, repo, fmt.Sprintf("change %s permission to %s%s", s, p.Permission, scope))
+		}
+	}
+	for _, p := range base {
+		if s := subject(p); !seen[s] {
+			d.add(path, repo, fmt.Sprintf("revoke %s access of %s%s", p.Permission, s, scope))
+		}
+	}
+}
+
+// isEmptyEqual is like reflect.DeepEqual, but treats nil and empty slices and maps as equal
+func isEmptyEqual(a, b reflect.Value) bool {
+	if (a.Kind() == reflect.Slice || a.Kind() == reflect.Map) && a.Len() == 0 && b.Len() == 0 {
+		return true
+	}
+	return reflect.DeepEqual(a.Interface(), b.Interface())
+}
+
+// missingStrings returns the strings of a which are not in b
+func missingStrings(a, b reflect.Value) []string {
+	var out []string
+	for i := 0; i < a.Len(); i++ {
+		v := a.Index(i).String()
+		found := false
+		for j := 0; j < b.Len(); j++ {
+			if b.Index(j).String() == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func hasNameField(t reflect.Type) bool {
+	f, ok := t.FieldByName("Name")
+	return ok && f.Type.Kind() == reflect.String
+}
+
+// fieldLabel returns a lower case label of the field,
+// taken from the ui tag if present
+func fieldLabel(sf reflect.StructField) string {
+	if label := util.ParseTag(sf.Tag.Get("ui"))["label"]; label != "" {
+		return strings.ToLower(label)
+	}
+	return util.Humanize(util.YAMLName(sf))
+}
+
+// toggleLabel strips the verb from boolean labels,
+// so "require signed commits" reads as "enable signed commits"
+func toggleLabel(label string) string {
+	for _, prefix := range []string{"has ", "allow ", "require ", "is "} {
+		if rest, ok := strings.CutPrefix(label, prefix); ok {
+			return rest
+		}
+	}
+	return label
+}
@@ -0,0 +1,120 @@
+package manifest
+
+import (
+	"strings"
+
+	"github.com/artemlive/gh-crossplane/internal/domain"
+	"gopkg.in/yaml.v3"
+)
+
+// DiffLine is a line of a line-based diff
+type DiffLine struct {
+	Op   byte // ' ' unchanged, '+' added, '-' removed
+	Text string
+}
+
+func (l DiffLine) String() string {
+	return string(l.Op) + " " + l.Text
+}
+
+// diffContext is the number of unchanged lines kept around the changes
+const diffContext = 2
+
+// Diff compares the files the two manifests would be written as.
+// Only the changed lines and a few lines around them are returned,
+// the skipped lines are replaced with a single "..." line.
+func Diff(before, after domain.RepositoriesGroup) ([]DiffLine, error) {
+	a, err := encodeManifest(before)
+	if err != nil {
+		return nil, err
+	}
+	b, err := encodeManifest(after)
+	if err != nil {
+		return nil, err
+	}
+	return DiffText(string(a), string(b)), nil
+}
+
+// DiffText is Diff for any two texts
+func DiffText(before, after string) []DiffLine {
+	a := strings.Split(strings.TrimSuffix(before, "\n"), "\n")
+	b := strings.Split(strings.TrimSuffix(after, "\n"), "\n")
+	if before == "" {
+		a = nil
+	}
+
+	// longest common subsequence, lcs[i][j] is the one of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var all []DiffLine
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			all = append(all, DiffLine{' ', a[i]})
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			all = append(all, DiffLine{'-', a[i]})
+			i++
+		default:
+			all = append(all, DiffLine{'+', b[j]})
+			j++
+		}
+	}
+	return trimContext(all)
+}
+
+// trimContext drops the unchanged lines far from any change
+func trimContext(lines []DiffLine) []DiffLine {
+	keep := make([]bool, len(lines))
+	for i, l := range lines {
+		if l.Op == ' ' {
+			continue
+		}
+		for k := max(0, i-diffContext); k <= min(len(lines)-1, i+diffContext); k++ {
+			keep[k] = true
+		}
+	}
+
+	var out []DiffLine
+	skipped := false
+	for i, l := range lines {
+		if !keep[i] {
+			skipped = true
+			continue
+		}
+		if skipped && len(out) > 0 {
+			out = append(out, DiffLine{' ', "..."})
+		}
+		skipped = false
+		out = append(out, l)
+	}
+	return out
+}
+
+// DiffNode compares two yaml documents of a group file, e.g. before and after
+// an edit of the document returned by GroupFile.Node
+func DiffNode(before, after *yaml.Node) ([]DiffLine, error) {
+	a, err := encodeYAML(before)
+	if err != nil {
+		return nil, err
+	}
+	b, err := encodeYAML(after)
+	if err != nil {
+		return nil, err
+	}
+	return DiffText(string(a), string(b)), nil
+}
@@ -1,23 +1,48 @@
 package manifest
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/artemlive/gh-crossplane/internal/domain"
+	"github.com/artemlive/gh-crossplane/internal/versions"
 	"gopkg.in/yaml.v3"
 )
 
+// ErrFileChanged is returned by SaveGroupFile when the file on disk
+// was modified after it had been loaded
+var ErrFileChanged = errors.New("file changed on disk since it was loaded")
+
+// ErrUnsupportedVersion is returned for the group files of an apiVersion
+// there is no conversion registered for
+var ErrUnsupportedVersion = errors.New("unsupported apiVersion")
+
 type ManifestLoader struct {
 	dir    string // Directory to load YAML files from
 	groups []GroupFile
+	backup bool // keep a .bak copy of the previous content on save
+	// skipped are the errors of the group files of unsupported versions
+	skipped []error
 }
 
 // GroupFile represents a loaded RepositoriesGroup + source path.
 type GroupFile struct {
 	Path     string
 	Manifest domain.RepositoriesGroup
+
+	// content is the raw file content as it was read from disk,
+	// it's used as the merge base and to detect unsaved edits
+	content []byte
+	hash    string
+	modTime time.Time
 }
 
 func (g GroupFile) Title() string {
@@ -32,6 +57,60 @@ func (g GroupFile) FilterValue() string {
 	return g.Manifest.Metadata.Name
 }
 
+// Base returns the manifest as it was last read from or written to disk.
+func (g GroupFile) Base() (domain.RepositoriesGroup, error) {
+	base, err := DecodeGroup(g.content)
+	if err != nil {
+		return base, fmt.Errorf("unmarshal %s: %w", g.Path, err)
+	}
+	return base, nil
+}
+
+// Modified reports whether the in-memory manifest differs from the one on disk.
+func (g GroupFile) Modified() bool {
+	base, err := g.Base()
+	if err != nil {
+		return true
+	}
+	// compare the encoded form, so nil and empty values are treated the same way
+	// as they are when the file gets written
+	want, err := encodeManifest(base)
+	if err != nil {
+		return true
+	}
+	got, err := encodeManifest(g.Manifest)
+	if err != nil {
+		return true
+	}
+	return !bytes.Equal(want, got)
+}
+
+// Node returns the file as it was last read from or written to disk as a yaml document,
+// or the in-memory manifest if the file hasn't been written yet.
+func (g GroupFile) Node() (*yaml.Node, error) {
+	content := g.content
+	if len(content) == 0 {
+		var err error
+		if content, err = encodeManifest(g.Manifest); err != nil {
+			return nil, err
+		}
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, fmt.Errorf("unmarshal %s: %w", g.Path, err)
+	}
+	return &doc, nil
+}
+
+// Rebase makes the other version of the file the base of this one,
+// the in-memory manifest is kept as is. It's used to accept the version
+// on disk as the one the local edits are meant to replace.
+func (g *GroupFile) Rebase(other GroupFile) {
+	g.content = other.content
+	g.hash = other.hash
+	g.modTime = other.modTime
+}
+
 // NewManifestLoader creates a new ManifestLoader instance.
 func NewManifestLoader(path string) *ManifestLoader {
 	manifestLoader := &ManifestLoader{
@@ -40,42 +119,126 @@ func NewManifestLoader(path string) *ManifestLoader {
 	if err := manifestLoader.LoadGroupsFromFS(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error loading groups from %s: %v\n", path, err)
 	}
+	for _, err := range manifestLoader.Skipped() {
+		fmt.Fprintf(os.Stderr, "Skipping %v\n", err)
+	}
 	return manifestLoader
 }
 
-// LoadGroupsFromDir scans all YAMLs with kind=RepositoriesGroup
+// Load is like NewManifestLoader, but fails if any of the files can't be loaded.
+// It's used by the commands, where a partially loaded directory would give wrong results.
+// The files of unsupported versions are skipped, see Skipped.
+func Load(path string) (*ManifestLoader, error) {
+	manifestLoader := &ManifestLoader{
+		dir: path,
+	}
+	if err := manifestLoader.LoadGroupsFromFS(); err != nil {
+		return nil, fmt.Errorf("load groups from %s: %w", path, err)
+	}
+	return manifestLoader, nil
+}
+
+// SetBackup enables keeping a "<file>.bak" copy of the previous content on every save.
+func (m *ManifestLoader) SetBackup(enabled bool) {
+	m.backup = enabled
+}
+
+// Dir returns the directory the groups are loaded from.
+func (m *ManifestLoader) Dir() string {
+	return m.dir
+}
+
+// LoadGroupsFromDir scans all YAMLs with kind=RepositoriesGroup.
+// A file which can't be read doesn't stop the scan, the errors of all
+// such files are returned together. The files of unsupported versions
+// aren't errors, they are reported by Skipped.
 func (m *ManifestLoader) LoadGroupsFromFS() error {
+	var errs []error
+	m.skipped = nil
 	err := filepath.Walk(m.dir, func(path string, info os.FileInfo, err error) error {
 		if err != nil || info.IsDir() {
 			return err
 		}
-		if filepath.Ext(path) != ".yaml" && filepath.Ext(path) != ".yml" {
+		if !isYAML(path) {
 			return nil
 		}
 
-		content, err := os.ReadFile(path)
-		if err != nil {
-			return err
-		}
-
-		var g domain.RepositoriesGroup
-		if err := yaml.Unmarshal(content, &g); err != nil {
-			return fmt.Errorf("unmarshal %s: %w", path, err)
-		}
-
-		if g.Kind != "RepositoriesGroup" {
+		gf, err := readGroupFile(path)
+		switch {
+		case errors.Is(err, ErrUnsupportedVersion):
+			m.skipped = append(m.skipped, err)
+			return nil
+		case err != nil:
+			errs = append(errs, err)
+			return nil
+		case gf == nil:
 			return nil // ignore unrelated YAMLs
 		}
 
-		m.groups = append(m.groups, GroupFile{
-			Path:     path,
-			Manifest: g,
-		})
+		m.groups = append(m.groups, *gf)
 
 		return nil
 	})
 
-	return err
+	return errors.Join(append(errs, err)...)
+}
+
+// Skipped returns the errors of the group files which weren't loaded
+// because their apiVersion isn't supported
+func (m *ManifestLoader) Skipped() []error {
+	return m.skipped
+}
+
+// NewGroupPath returns the path of the file for a new group.
+func (m *ManifestLoader) NewGroupPath(name string) string {
+	return filepath.Join(m.dir, name+".yaml")
+}
+
+// APIVersion returns the apiVersion to use for new groups,
+// the one of the already loaded groups if there are any.
+func (m *ManifestLoader) APIVersion() string {
+	for _, g := range m.groups {
+		if g.Manifest.APIVersion != "" {
+			return g.Manifest.APIVersion
+		}
+	}
+	return domain.DefaultAPIVersion
+}
+
+// Reload re-reads the given files and updates the loaded groups accordingly.
+// Files that were removed or are no longer RepositoriesGroup manifests are dropped.
+// It returns the paths of the groups that actually changed.
+func (m *ManifestLoader) Reload(paths []string) ([]string, error) {
+	var changed []string
+	var errs []error
+
+	for _, path := range paths {
+		idx := m.indexOf(path)
+
+		gf, err := readGroupFile(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			errs = append(errs, err)
+			continue
+		}
+
+		switch {
+		case gf == nil && idx >= 0:
+			// removed or not a group anymore, the slice returned by Groups may still be in use
+			m.groups = slices.Delete(slices.Clone(m.groups), idx, idx+1)
+			changed = append(changed, path)
+		case gf == nil:
+			continue
+		case idx < 0:
+			m.groups = append(m.groups, *gf)
+			changed = append(changed, path)
+		case m.groups[idx].hash != gf.hash:
+			m.groups = slices.Clone(m.groups)
+			m.groups[idx] = *gf
+			changed = append(changed, path)
+		}
+	}
+
+	return changed, errors.Join(errs...)
 }
 
 // Groups returns loaded RepositoriesGroup manifests.
@@ -99,18 +262,258 @@ func (m *ManifestLoader) GetGroup(name string) *GroupFile {
 	return nil
 }
 
+// GetGroupByPath returns a RepositoriesGroup manifest by its file path.
+func (m *ManifestLoader) GetGroupByPath(path string) *GroupFile {
+	if idx := m.indexOf(path); idx >= 0 {
+		group := m.groups[idx]
+		return &group
+	}
+	return nil
+}
+
+// SaveGroupFile writes the manifest to its file.
+// The content goes to a temporary file first which is then renamed over the target,
+// so the manifest is never left half-written. It refuses to overwrite the file
+// if it was changed by someone else since it had been loaded.
 func (m *ManifestLoader) SaveGroupFile(gf *GroupFile) error {
 	if gf == nil {
 		return fmt.Errorf("group file is nil")
 	}
-	f, err := os.Create(gf.Path)
+	content, err := encodeManifest(gf.Manifest)
+	if err != nil {
+		return fmt.Errorf("encode %s: %w", gf.Path, err)
+	}
+	return m.writeGroupFile(gf, content)
+}
+
+// SaveGroupNode writes the yaml document to the group file, keeping the fields
+// and the comments the domain types don't know about. The manifest of the group
+// file is updated from the document. See SaveGroupFile for the conflict handling.
+func (m *ManifestLoader) SaveGroupNode(gf *GroupFile, doc *yaml.Node) error {
+	if gf == nil {
+		return fmt.Errorf("group file is nil")
+	}
+	content, err := encodeYAML(doc)
 	if err != nil {
-		return fmt.Errorf("create file %s: %w", gf.Path, err)
+		return fmt.Errorf("encode %s: %w", gf.Path, err)
 	}
-	defer f.Close() //nolint:errcheck
+	manifest, err := DecodeGroup(content)
+	if err != nil {
+		return fmt.Errorf("unmarshal %s: %w", gf.Path, err)
+	}
+	gf.Manifest = manifest
+	return m.writeGroupFile(gf, content)
+}
 
-	enc := yaml.NewEncoder(f)
+func (m *ManifestLoader) writeGroupFile(gf *GroupFile, content []byte) error {
+	mode := os.FileMode(0o644)
+	info, err := os.Stat(gf.Path)
+	switch {
+	case err == nil:
+		mode = info.Mode().Perm()
+		if err := checkUnchanged(gf, info); err != nil {
+			return err
+		}
+		if m.backup {
+			if err := copyFile(gf.Path, gf.Path+".bak", mode); err != nil {
+				return fmt.Errorf("backup %s: %w", gf.Path, err)
+			}
+		}
+	case !errors.Is(err, os.ErrNotExist):
+		return fmt.Errorf("stat %s: %w", gf.Path, err)
+	}
+
+	if err := writeFileAtomic(gf.Path, content, mode); err != nil {
+		return fmt.Errorf("write file %s: %w", gf.Path, err)
+	}
+
+	gf.content = content
+	gf.hash = hashContent(content)
+	if info, err := os.Stat(gf.Path); err == nil {
+		gf.modTime = info.ModTime()
+	}
+
+	// keep the loaded copy in sync, so the watcher doesn't
+	// report our own write as an external change
+	if idx := m.indexOf(gf.Path); idx >= 0 {
+		m.groups[idx] = *gf
+	} else {
+		m.groups = append(m.groups, *gf)
+	}
+	return nil
+}
+
+// checkUnchanged makes sure the file on disk is still the one the group was loaded from.
+// The mtime is only a shortcut, a touched file with the same content is fine.
+func checkUnchanged(gf *GroupFile, info os.FileInfo) error {
+	if gf.hash == "" {
+		// a new group, which wasn't read from disk
+		return fmt.Errorf("%s: %w", gf.Path, ErrFileChanged)
+	}
+	if info.ModTime().Equal(gf.modTime) {
+		return nil
+	}
+	current, err := os.ReadFile(gf.Path)
+	if err != nil {
+		return fmt.Errorf("read %s: %w", gf.Path, err)
+	}
+	if hashContent(current) != gf.hash {
+		return fmt.Errorf("%s: %w", gf.Path, ErrFileChanged)
+	}
+	return nil
+}
+
+// writeFileAtomic writes the content to a temp file in the same directory
+// and renames it over the target
+func writeFileAtomic(path string, content []byte, mode os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	// it's a no-op after the successful rename
+	defer os.Remove(tmp.Name()) //nolint:errcheck
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close() //nolint:errcheck
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close() //nolint:errcheck
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), mode); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func copyFile(src, dst string, mode os.FileMode) error {
+	content, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(dst, content, mode)
+}
+
+func (m *ManifestLoader) indexOf(path string) int {
+	for i, group := range m.groups {
+		if filepath.Clean(group.Path) == filepath.Clean(path) {
+			return i
+		}
+	}
+	return -1
+}
+
+// readGroupFile reads a RepositoriesGroup from the path,
+// it returns nil if the file contains some other kind of resource.
+func readGroupFile(path string) (*GroupFile, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var header struct {
+		Kind string `yaml:"kind"`
+	}
+	if err := yaml.Unmarshal(content, &header); err != nil {
+		return nil, fmt.Errorf("unmarshal %s: %w", path, err)
+	}
+	if header.Kind != domain.Kind {
+		return nil, nil
+	}
+
+	g, err := DecodeGroup(content)
+	if errors.Is(err, ErrUnsupportedVersion) {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal %s: %w", path, err)
+	}
+
+	return &GroupFile{
+		Path:     path,
+		Manifest: g,
+		content:  content,
+		hash:     hashContent(content),
+		modTime:  info.ModTime(),
+	}, nil
+}
+
+// DecodeGroup reads a RepositoriesGroup of any known version into the domain types.
+// The apiVersion of the manifest is kept, so it's written back in the same version.
+func DecodeGroup(content []byte) (domain.RepositoriesGroup, error) {
+	var g domain.RepositoriesGroup
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return g, err
+	}
+	var header struct {
+		APIVersion string `yaml:"apiVersion"`
+	}
+	if err := doc.Decode(&header); err != nil {
+		return g, err
+	}
+	// the manifests written before the versions were introduced have none
+	version := versions.Internal
+	if header.APIVersion != "" {
+		version = versions.Of(header.APIVersion)
+	}
+	if !versions.Known(version) {
+		return g, fmt.Errorf("%w %s, known versions are %s", ErrUnsupportedVersion, header.APIVersion, strings.Join(versions.Names(), ", "))
+	}
+	if version != versions.Internal {
+		if err := versions.Convert(&doc, version, versions.Internal); err != nil {
+			return g, err
+		}
+	}
+	if err := doc.Decode(&g); err != nil {
+		return g, err
+	}
+	g.APIVersion = header.APIVersion
+	return g, nil
+}
+
+// encodeManifest writes the manifest in the version of its apiVersion
+func encodeManifest(g domain.RepositoriesGroup) ([]byte, error) {
+	version := versions.Of(g.APIVersion)
+	if version == versions.Internal || !versions.Known(version) {
+		return encodeYAML(g)
+	}
+	var doc yaml.Node
+	if err := doc.Encode(g); err != nil {
+		return nil, err
+	}
+	if err := versions.Convert(&doc, versions.Internal, version); err != nil {
+		return nil, err
+	}
+	return encodeYAML(&doc)
+}
+
+func encodeYAML(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
 	enc.SetIndent(1)
-	defer enc.Close() //nolint:errcheck
-	return enc.Encode(gf.Manifest)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func hashContent(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+func isYAML(path string) bool {
+	return filepath.Ext(path) == ".yaml" || filepath.Ext(path) == ".yml"
 }
@@ -0,0 +1,245 @@
+package manifest
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/artemlive/gh-crossplane/internal/domain"
+	"gopkg.in/yaml.v3"
+)
+
+const v1alpha1Group = `apiVersion: github.platform.crossplane.io/v1alpha1
+kind: RepositoriesGroup
+metadata:
+  name: team
+spec:
+  securityAndAnalysis:
+    - secretScanning:
+        - status: enabled
+  repositories:
+    - name: api
+      protections:
+        - name: main
+          pattern: main
+          requiredStatusChecks:
+            - strict: true
+              contexts: [build]
+`
+
+const v1beta1Group = `apiVersion: github.platform.crossplane.io/v1beta1
+kind: RepositoriesGroup
+metadata:
+  name: team
+spec:
+  securityAndAnalysis:
+    secretScanning:
+      status: enabled
+  repositories:
+    - name: api
+      protections:
+        - name: main
+          pattern: main
+          requiredStatusChecks:
+            strict: true
+            contexts: [build]
+`
+
+func TestDecodeGroupVersions(t *testing.T) {
+	alpha, err := DecodeGroup([]byte(v1alpha1Group))
+	if err != nil {
+		t.Fatal(err)
+	}
+	beta, err := DecodeGroup([]byte(v1beta1Group))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// both versions decode to the same domain types, only the apiVersion is kept
+	if beta.APIVersion != "github.platform.crossplane.io/v1beta1" {
+		t.Errorf("apiVersion = %s, want the one of the file", beta.APIVersion)
+	}
+	beta.APIVersion = alpha.APIVersion
+	if !reflect.DeepEqual(alpha, beta) {
+		t.Errorf("v1beta1 decoded to %+v, want %+v", beta, alpha)
+	}
+	checks := alpha.Spec.Repositories[0].Protections[0].RequiredStatusChecks
+	if len(checks) != 1 || !checks[0].Strict || checks[0].Contexts[0] != "build" {
+		t.Errorf("requiredStatusChecks = %+v", checks)
+	}
+}
+
+func TestEncodeManifestRoundTrip(t *testing.T) {
+	for _, tc := range []struct {
+		version string
+		content string
+		// path to a block, it's a list in v1alpha1 and an object in v1beta1
+		block []string
+		kind  yaml.Kind
+	}{
+		{"v1alpha1", v1alpha1Group, []string{"spec", "securityAndAnalysis"}, yaml.SequenceNode},
+		{"v1beta1", v1beta1Group, []string{"spec", "securityAndAnalysis"}, yaml.MappingNode},
+	} {
+		t.Run(tc.version, func(t *testing.T) {
+			g, err := DecodeGroup([]byte(tc.content))
+			if err != nil {
+				t.Fatal(err)
+			}
+			encoded, err := encodeManifest(g)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// written back in the version it was read in
+			var doc yaml.Node
+			if err := yaml.Unmarshal(encoded, &doc); err != nil {
+				t.Fatal(err)
+			}
+			n := doc.Content[0]
+			for _, key := range tc.block {
+				n = lookup(n, key)
+			}
+			if n == nil || n.Kind != tc.kind {
+				t.Errorf("%s isn't written in the %s form:\n%s", strings.Join(tc.block, "."), tc.version, encoded)
+			}
+
+			again, err := DecodeGroup(encoded)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(again, g) {
+				t.Errorf("round trip = %+v, want %+v", again, g)
+			}
+		})
+	}
+}
+
+func TestDecodeGroupWithoutAPIVersion(t *testing.T) {
+	content := strings.Replace(v1alpha1Group, "apiVersion: github.platform.crossplane.io/v1alpha1\n", "", 1)
+	g, err := DecodeGroup([]byte(content))
+	if err != nil {
+		t.Fatalf("a group without apiVersion is read as the internal version: %v", err)
+	}
+	if g.Spec.Repositories[0].Name != "api" {
+		t.Errorf("decoded %+v", g)
+	}
+}
+
+func TestLoadSkipsUnsupportedVersions(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "team.yaml"), v1alpha1Group)
+	writeFile(t, filepath.Join(dir, "future.yaml"), strings.ReplaceAll(v1alpha1Group, "v1alpha1", "v2"))
+
+	loader, err := Load(dir)
+	if err != nil {
+		t.Fatalf("an unsupported version fails the whole dir: %v", err)
+	}
+	if groups := loader.Groups(); len(groups) != 1 || groups[0].Manifest.Metadata.Name != "team" {
+		t.Errorf("groups = %v, want only team", groups)
+	}
+	skipped := loader.Skipped()
+	if len(skipped) != 1 || !errors.Is(skipped[0], ErrUnsupportedVersion) || !strings.Contains(skipped[0].Error(), "future.yaml") {
+		t.Errorf("skipped = %v, want future.yaml", skipped)
+	}
+
+	// a broken file still fails the commands, after the others were read
+	writeFile(t, filepath.Join(dir, "broken.yaml"), "kind: RepositoriesGroup\nspec: [\n")
+	if _, err := Load(dir); err == nil || !strings.Contains(err.Error(), "broken.yaml") {
+		t.Errorf("Load with a broken file = %v, want its error", err)
+	}
+}
+
+func TestSaveGroupFileRefusesExternalChanges(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "team.yaml")
+	writeFile(t, path, v1alpha1Group)
+	loader, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gf := loader.GetGroup("team")
+	gf.Manifest.Spec.Topics = []string{"go"}
+
+	// someone else writes the file between the load and the save
+	external := strings.Replace(v1alpha1Group, "name: api", "name: web", 1)
+	writeFile(t, path, external)
+	future := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, future, future); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := loader.SaveGroupFile(gf); !errors.Is(err, ErrFileChanged) {
+		t.Fatalf("save = %v, want %v", err, ErrFileChanged)
+	}
+	if got := readFile(t, path); got != external {
+		t.Errorf("the external change was overwritten:\n%s", got)
+	}
+
+	// touching the file without changing it isn't a conflict
+	gf = loader.GetGroup("team")
+	writeFile(t, path, v1alpha1Group)
+	if err := os.Chtimes(path, future.Add(time.Minute), future.Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	gf.Manifest.Spec.Topics = []string{"go"}
+	if err := loader.SaveGroupFile(gf); err != nil {
+		t.Fatalf("save after a touch = %v", err)
+	}
+	saved, err := DecodeGroup([]byte(readFile(t, path)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(saved.Spec.Topics, []string{"go"}) {
+		t.Errorf("saved topics = %v", saved.Spec.Topics)
+	}
+	if matches, _ := filepath.Glob(filepath.Join(dir, ".team.yaml.tmp-*")); len(matches) > 0 {
+		t.Errorf("temporary files left behind: %v", matches)
+	}
+}
+
+func TestSaveGroupFileRefusesNewGroupOverExistingFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "team.yaml")
+	writeFile(t, path, v1alpha1Group)
+	loader := &ManifestLoader{dir: dir}
+
+	gf := &GroupFile{Path: path, Manifest: domain.RepositoriesGroup{Kind: domain.Kind}}
+	if err := loader.SaveGroupFile(gf); !errors.Is(err, ErrFileChanged) {
+		t.Fatalf("save = %v, want %v", err, ErrFileChanged)
+	}
+	if got := readFile(t, path); got != v1alpha1Group {
+		t.Errorf("the existing file was overwritten:\n%s", got)
+	}
+}
+
+func lookup(mapping *yaml.Node, key string) *yaml.Node {
+	if mapping == nil {
+		return nil
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(content)
+}
@@ -1 +1,10 @@
 package manifest
+
+import "path/filepath"
+
+// ConfigPath returns the path of a config file or directory of the editor, e.g. the
+// policies or the key bindings. They are looked up next to the groups dir, so the
+// GitOps repository holding the groups holds them too.
+func ConfigPath(groupsDir, name string) string {
+	return filepath.Join(filepath.Dir(filepath.Clean(groupsDir)), name)
+}
@@ -0,0 +1,172 @@
+package manifest
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/artemlive/gh-crossplane/internal/domain"
+	"gopkg.in/yaml.v3"
+)
+
+// Merge performs a three-way merge of the manifests.
+// base is the common ancestor, mine holds the local edits and theirs is the version from disk.
+// Changes that touch different fields are combined, when both sides changed the same field
+// differently, the local value wins and the field path is reported as a conflict.
+// Lists of objects with a "name" key (repositories, protections, autolinks) are merged per item.
+func Merge(base, mine, theirs domain.RepositoriesGroup) (domain.RepositoriesGroup, []string, error) {
+	var out domain.RepositoriesGroup
+
+	b, err := toGeneric(base)
+	if err != nil {
+		return out, nil, err
+	}
+	o, err := toGeneric(mine)
+	if err != nil {
+		return out, nil, err
+	}
+	t, err := toGeneric(theirs)
+	if err != nil {
+		return out, nil, err
+	}
+
+	var conflicts []string
+	merged := mergeValues("", b, o, t, &conflicts)
+
+	content, err := yaml.Marshal(merged)
+	if err != nil {
+		return out, nil, fmt.Errorf("marshal merged manifest: %w", err)
+	}
+	if err := yaml.Unmarshal(content, &out); err != nil {
+		return out, nil, fmt.Errorf("unmarshal merged manifest: %w", err)
+	}
+	return out, conflicts, nil
+}
+
+func toGeneric(g domain.RepositoriesGroup) (any, error) {
+	content, err := yaml.Marshal(g)
+	if err != nil {
+		return nil, err
+	}
+	var v any
+	if err := yaml.Unmarshal(content, &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func mergeValues(path string, base, mine, theirs any, conflicts *[]string) any {
+	switch {
+	case reflect.DeepEqual(mine, theirs):
+		return mine
+	case reflect.DeepEqual(base, mine):
+		return theirs
+	case reflect.DeepEqual(base, theirs):
+		return mine
+	}
+
+	// both sides changed the value, try to go deeper
+	bm, _ := base.(map[string]any)
+	om, okMine := mine.(map[string]any)
+	tm, okTheirs := theirs.(map[string]any)
+	if okMine && okTheirs {
+		return mergeMaps(path, bm, om, tm, conflicts)
+	}
+
+	bl, _ := base.([]any)
+	ol, okMine := mine.([]any)
+	tl, okTheirs := theirs.([]any)
+	if okMine && okTheirs && isNamedList(bl) && isNamedList(ol) && isNamedList(tl) {
+		return mergeNamedLists(path, bl, ol, tl, conflicts)
+	}
+
+	*conflicts = append(*conflicts, displayPath(path))
+	return mine
+}
+
+func mergeMaps(path string, base, mine, theirs map[string]any, conflicts *[]string) map[string]any {
+	out := make(map[string]any)
+	for _, k := range unionKeys(base, mine, theirs) {
+		v := mergeValues(path+"."+k, base[k], mine[k], theirs[k], conflicts)
+		if v != nil {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// mergeNamedLists merges lists of objects identified by their "name" key.
+// The order of the local list is kept, new items from the other side are appended.
+func mergeNamedLists(path string, base, mine, theirs []any, conflicts *[]string) []any {
+	baseByName := indexByName(base)
+	mineByName := indexByName(mine)
+	theirsByName := indexByName(theirs)
+
+	var names []string
+	seen := make(map[string]bool)
+	for _, list := range [][]any{mine, theirs} {
+		for _, item := range list {
+			name := itemName(item)
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+
+	var out []any
+	for _, name := range names {
+		itemPath := fmt.Sprintf("%s[%s]", path, name)
+		v := mergeValues(itemPath, baseByName[name], mineByName[name], theirsByName[name], conflicts)
+		if v != nil {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func isNamedList(list []any) bool {
+	for _, item := range list {
+		if itemName(item) == "" {
+			return false
+		}
+	}
+	return true
+}
+
+func itemName(item any) string {
+	m, ok := item.(map[string]any)
+	if !ok {
+		return ""
+	}
+	name, _ := m["name"].(string)
+	return name
+}
+
+func indexByName(list []any) map[string]any {
+	out := make(map[string]any, len(list))
+	for _, item := range list {
+		out[itemName(item)] = item
+	}
+	return out
+}
+
+func unionKeys(maps ...map[string]any) []string {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, m := range maps {
+		for k := range m {
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func displayPath(path string) string {
+	return strings.TrimPrefix(path, ".")
+}
@@ -0,0 +1,109 @@
+package manifest
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/artemlive/gh-crossplane/internal/domain"
+)
+
+func mergeBase() domain.RepositoriesGroup {
+	var g domain.RepositoriesGroup
+	g.APIVersion = domain.DefaultAPIVersion
+	g.Kind = domain.Kind
+	g.Metadata.Name = "team"
+	g.Spec.Visibility = "private"
+	g.Spec.Topics = []string{"go"}
+	g.Spec.Repositories = []domain.Repository{
+		{Name: "api", Description: "The API"},
+		{Name: "web", Description: "The site"},
+	}
+	return g
+}
+
+func TestMergeKeepsBothSides(t *testing.T) {
+	base := mergeBase()
+
+	mine := mergeBase()
+	mine.Spec.Visibility = "internal"
+	mine.Spec.Repositories[0].Description = "The public API"
+	mine.Spec.Repositories = append(mine.Spec.Repositories, domain.Repository{Name: "docs"})
+
+	theirs := mergeBase()
+	theirs.Spec.Topics = []string{"go", "platform"}
+	theirs.Spec.Repositories[1].Topics = []string{"frontend"}
+	theirs.Spec.Repositories = append(theirs.Spec.Repositories, domain.Repository{Name: "cli"})
+
+	merged, conflicts, err := Merge(base, mine, theirs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(conflicts) > 0 {
+		t.Errorf("conflicts = %v, want none", conflicts)
+	}
+
+	want := mergeBase()
+	want.Spec.Visibility = "internal"
+	want.Spec.Topics = []string{"go", "platform"}
+	// the order of the local list is kept, the new items of the other side are appended
+	want.Spec.Repositories = []domain.Repository{
+		{Name: "api", Description: "The public API"},
+		{Name: "web", Description: "The site", Topics: []string{"frontend"}},
+		{Name: "docs"},
+		{Name: "cli"},
+	}
+	if !reflect.DeepEqual(merged, want) {
+		t.Errorf("merged = %+v\nwant %+v", merged.Spec, want.Spec)
+	}
+}
+
+func TestMergeConflictsKeepMine(t *testing.T) {
+	base := mergeBase()
+
+	mine := mergeBase()
+	mine.Spec.Visibility = "internal"
+	mine.Spec.Repositories[0].Description = "Mine"
+	mine.Spec.Repositories[1].Description = "The web site"
+
+	theirs := mergeBase()
+	theirs.Spec.Visibility = "public"
+	theirs.Spec.Repositories[0].Description = "Theirs"
+	// removed on their side, while changed on mine
+	theirs.Spec.Repositories = theirs.Spec.Repositories[:1]
+
+	merged, conflicts, err := Merge(base, mine, theirs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"spec.repositories[api].description", "spec.repositories[web]", "spec.visibility"}; !slices.Equal(conflicts, want) {
+		t.Errorf("conflicts = %v, want %v", conflicts, want)
+	}
+	if !reflect.DeepEqual(merged, mine) {
+		t.Errorf("merged = %+v\nwant the local values %+v", merged.Spec, mine.Spec)
+	}
+}
+
+func TestMergeRemovals(t *testing.T) {
+	base := mergeBase()
+
+	// removed on one side and unchanged on the other
+	mine := mergeBase()
+	mine.Spec.Topics = nil
+	theirs := mergeBase()
+	theirs.Spec.Repositories = theirs.Spec.Repositories[:1]
+
+	merged, conflicts, err := Merge(base, mine, theirs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(conflicts) > 0 {
+		t.Errorf("conflicts = %v, want none", conflicts)
+	}
+	if merged.Spec.Topics != nil {
+		t.Errorf("topics = %v, want them removed", merged.Spec.Topics)
+	}
+	if len(merged.Spec.Repositories) != 1 || merged.Spec.Repositories[0].Name != "api" {
+		t.Errorf("repositories = %+v, want only api", merged.Spec.Repositories)
+	}
+}
@@ -0,0 +1,124 @@
+package manifest
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// NamePattern is the format of Kubernetes object names, which group names must follow
+var NamePattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// kustomizationFiles are the names kustomize looks for in a directory
+var kustomizationFiles = []string{"kustomization.yaml", "kustomization.yml", "Kustomization"}
+
+// RenameResult describes what RenameGroup changed
+type RenameResult struct {
+	OldName string
+	OldPath string
+	// Kustomizations are the kustomization files the references to the file were updated in
+	Kustomizations []string
+}
+
+// RenameGroup sets the name of the group and saves it. With renameFile the file
+// is moved to <name>.yaml in the same directory, and the kustomization file of
+// the directory is updated to refer to the new file.
+// gf is only updated if everything was written.
+func (m *ManifestLoader) RenameGroup(gf *GroupFile, name string, renameFile bool) (*RenameResult, error) {
+	if !NamePattern.MatchString(name) {
+		return nil, fmt.Errorf("invalid name %q: it must consist of lowercase letters, digits and '-', and start and end with a letter or digit", name)
+	}
+	if other := m.GetGroup(name); other != nil && filepath.Clean(other.Path) != filepath.Clean(gf.Path) {
+		return nil, fmt.Errorf("group '%s' already exists in %s", name, other.Path)
+	}
+
+	res := &RenameResult{OldName: gf.Manifest.Metadata.Name, OldPath: gf.Path}
+	renamed := *gf
+	renamed.Manifest.Metadata.Name = name
+
+	newPath := filepath.Join(filepath.Dir(gf.Path), name+filepath.Ext(gf.Path))
+	if !renameFile || filepath.Clean(newPath) == filepath.Clean(gf.Path) {
+		if err := m.SaveGroupFile(&renamed); err != nil {
+			return nil, err
+		}
+		*gf = renamed
+		return res, nil
+	}
+
+	if _, err := os.Stat(newPath); err == nil {
+		return nil, fmt.Errorf("file %s already exists", newPath)
+	}
+	info, err := os.Stat(gf.Path)
+	if err != nil {
+		return nil, fmt.Errorf("stat %s: %w", gf.Path, err)
+	}
+	if err := checkUnchanged(gf, info); err != nil {
+		return nil, err
+	}
+
+	// the new file is written before the old one is removed,
+	// so the group is never lost
+	renamed.Path = newPath
+	if err := m.SaveGroupFile(&renamed); err != nil {
+		return nil, err
+	}
+	if err := os.Remove(gf.Path); err != nil {
+		return nil, fmt.Errorf("remove %s: %w", gf.Path, err)
+	}
+	if idx := m.indexOf(gf.Path); idx >= 0 {
+		// the slice returned by Groups may still be in use
+		m.groups = slices.Delete(slices.Clone(m.groups), idx, idx+1)
+	}
+	*gf = renamed
+
+	res.Kustomizations, err = renameKustomizationResource(filepath.Dir(newPath), filepath.Base(res.OldPath), filepath.Base(newPath))
+	if err != nil {
+		return res, fmt.Errorf("the group file was renamed, but updating the kustomization failed: %w", err)
+	}
+	return res, nil
+}
+
+// renameKustomizationResource replaces the references to the file in the kustomization
+// files of the directory. The lines are edited in place, so the formatting and the
+// comments of the files are kept. It returns the files which were changed.
+func renameKustomizationResource(dir, oldName, newName string) ([]string, error) {
+	// list items and "path:" fields holding the file name, e.g. "- ./team.yaml"
+	ref := regexp.MustCompile(`^(\s*(?:-\s+)?(?:path:\s+)?["']?(?:\./)?)` + regexp.QuoteMeta(oldName) + `(["']?\s*(?:#.*)?)$`)
+
+	var changed []string
+	for _, name := range kustomizationFiles {
+		path := filepath.Join(dir, name)
+		info, err := os.Stat(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return changed, fmt.Errorf("stat %s: %w", path, err)
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return changed, fmt.Errorf("read %s: %w", path, err)
+		}
+
+		lines := strings.Split(string(content), "\n")
+		found := false
+		for i, line := range lines {
+			if ref.MatchString(line) {
+				lines[i] = ref.ReplaceAllString(line, "${1}"+newName+"${2}")
+				found = true
+			}
+		}
+		if !found {
+			continue
+		}
+		if err := writeFileAtomic(path, []byte(strings.Join(lines, "\n")), info.Mode().Perm()); err != nil {
+			return changed, fmt.Errorf("write file %s: %w", path, err)
+		}
+		changed = append(changed, path)
+	}
+	return changed, nil
+}
@@ -0,0 +1,116 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// debounceInterval is how long the watcher waits for more events
+// before reporting a batch, editors and git usually touch a file several times
+const debounceInterval = 200 * time.Millisecond
+
+// Watcher reports changes of YAML files in the groups directory.
+type Watcher struct {
+	fsw     *fsnotify.Watcher
+	changes chan []string
+	errors  chan error
+	done    chan struct{}
+}
+
+// NewWatcher starts watching the directory (and its subdirectories).
+func NewWatcher(dir string) (*Watcher, error) {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || !info.IsDir() {
+			return err
+		}
+		return fsw.Add(path)
+	})
+	if err != nil {
+		fsw.Close() //nolint:errcheck
+		return nil, err
+	}
+
+	w := &Watcher{
+		fsw:     fsw,
+		changes: make(chan []string),
+		errors:  make(chan error, 1),
+		done:    make(chan struct{}),
+	}
+	go w.run()
+	return w, nil
+}
+
+// Changes returns a channel with batches of changed file paths.
+// The channel is closed when the watcher is closed.
+func (w *Watcher) Changes() <-chan []string {
+	return w.changes
+}
+
+// Errors returns a channel with the errors reported by the underlying watcher.
+func (w *Watcher) Errors() <-chan error {
+	return w.errors
+}
+
+// Close stops the watcher.
+func (w *Watcher) Close() error {
+	close(w.done)
+	return w.fsw.Close()
+}
+
+func (w *Watcher) run() {
+	defer close(w.changes)
+
+	pending := make(map[string]struct{})
+	timer := time.NewTimer(debounceInterval)
+	timer.Stop()
+
+	for {
+		select {
+		case <-w.done:
+			return
+		case ev, ok := <-w.fsw.Events:
+			if !ok {
+				return
+			}
+			if ev.Has(fsnotify.Create) {
+				// watch newly created subdirectories too
+				if info, err := os.Stat(ev.Name); err == nil && info.IsDir() {
+					_ = w.fsw.Add(ev.Name)
+					continue
+				}
+			}
+			if !isYAML(ev.Name) || ev.Has(fsnotify.Chmod) && !ev.Has(fsnotify.Write) {
+				continue
+			}
+			pending[ev.Name] = struct{}{}
+			timer.Reset(debounceInterval)
+		case err, ok := <-w.fsw.Errors:
+			if !ok {
+				return
+			}
+			select {
+			case w.errors <- err:
+			default: // drop it if nobody is listening
+			}
+		case <-timer.C:
+			paths := make([]string, 0, len(pending))
+			for p := range pending {
+				paths = append(paths, p)
+			}
+			clear(pending)
+			select {
+			case w.changes <- paths:
+			case <-w.done:
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package policy
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// segment is a key of the path with an optional selection of list items
+type segment struct {
+	key    string
+	index  int    // -1 if not an index selection
+	all    bool   // [*]
+	field  string // [field=value]
+	value  string
+	filter bool
+}
+
+// parsePath parses the field of an assertion. The path is made of the yaml keys
+// of the effective settings separated by dots, a key of a list selects its items:
+//
+//	protections[0]                    the first item
+//	protections[*]                    every item
+//	protections[name=main]            the items with the name main
+//	permissions[collaborator=*]       the items with a collaborator set
+//	protections[pattern=$defaultBranch] the items with the pattern equal to the default branch
+func parsePath(p string) ([]segment, error) {
+	if p == "" {
+		return nil, fmt.Errorf("the assertion has no field")
+	}
+	var out []segment
+	for p != "" {
+		var part string
+		// the selection may contain dots, e.g. [pattern=release/v1.*]
+		end := strings.IndexByte(p, '.')
+		if open := strings.IndexByte(p, '['); open >= 0 && (end < 0 || open < end) {
+			closing := strings.IndexByte(p[open:], ']')
+			if closing < 0 {
+				return nil, fmt.Errorf("field %s: missing ]", p)
+			}
+			end = open + closing + 1
+			if end < len(p) && p[end] != '.' {
+				return nil, fmt.Errorf("field %s: expected . after ]", p)
+			}
+		}
+		if end < 0 {
+			part, p = p, ""
+		} else {
+			part, p = p[:end], strings.TrimPrefix(p[end:], ".")
+		}
+
+		s, err := parseSegment(part)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+func parseSegment(part string) (segment, error) {
+	s := segment{index: -1}
+	key, sel, hasSel := strings.Cut(part, "[")
+	s.key = key
+	if key == "" {
+		return s, fmt.Errorf("field segment %q has no key", part)
+	}
+	if !hasSel {
+		return s, nil
+	}
+	sel = strings.TrimSuffix(sel, "]")
+	switch field, value, ok := strings.Cut(sel, "="); {
+	case sel == "*":
+		s.all = true
+	case ok:
+		s.filter, s.field, s.value = true, field, value
+	default:
+		idx, err := strconv.Atoi(sel)
+		if err != nil || idx < 0 {
+			return s, fmt.Errorf("field segment %q: invalid selection %q", part, sel)
+		}
+		s.index = idx
+	}
+	return s, nil
+}
+
+// Lookup returns the values at the path of the generic yaml form of the settings,
+// see parsePath for the syntax. It returns nothing if the path is invalid or not set.
+func Lookup(root any, p string) []any {
+	segments, err := parsePath(p)
+	if err != nil {
+		return nil
+	}
+	values := []any{root}
+	for _, s := range segments {
+		var next []any
+		for _, v := range values {
+			m, ok := v.(map[string]any)
+			if !ok {
+				continue
+			}
+			child, ok := m[s.key]
+			if !ok || child == nil {
+				continue
+			}
+			next = append(next, s.selectItems(root, child)...)
+		}
+		values = next
+	}
+	return values
+}
+
+func (s segment) selectItems(root, v any) []any {
+	if !s.all && !s.filter && s.index < 0 {
+		return []any{v}
+	}
+	items, ok := v.([]any)
+	if !ok {
+		return nil
+	}
+	switch {
+	case s.all:
+		return items
+	case s.filter:
+		var out []any
+		for _, item := range items {
+			m, ok := item.(map[string]any)
+			if !ok {
+				continue
+			}
+			if s.matches(root, m[s.field]) {
+				out = append(out, item)
+			}
+		}
+		return out
+	case s.index < len(items):
+		return []any{items[s.index]}
+	}
+	return nil
+}
+
+func (s segment) matches(root, v any) bool {
+	switch {
+	case s.value == "*":
+		return v != nil && fmt.Sprint(v) != ""
+	case strings.HasPrefix(s.value, "$"):
+		refs := Lookup(root, s.value[1:])
+		return len(refs) > 0 && v != nil && fmt.Sprint(refs[0]) == fmt.Sprint(v)
+	}
+	return v != nil && fmt.Sprint(v) == s.value
+}
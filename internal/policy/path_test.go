@@ -0,0 +1,100 @@
+package policy
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestParsePath(t *testing.T) {
+	for _, tc := range []struct {
+		path string
+		want []segment
+	}{
+		{"visibility", []segment{{key: "visibility", index: -1}}},
+		{"protections[0].enforceAdmins", []segment{
+			{key: "protections", index: 0},
+			{key: "enforceAdmins", index: -1},
+		}},
+		{"protections[*]", []segment{{key: "protections", index: -1, all: true}}},
+		{"protections[pattern=release/v1.*].requiredStatusChecks", []segment{
+			{key: "protections", index: -1, filter: true, field: "pattern", value: "release/v1.*"},
+			{key: "requiredStatusChecks", index: -1},
+		}},
+		{"permissions[collaborator=*].permission", []segment{
+			{key: "permissions", index: -1, filter: true, field: "collaborator", value: "*"},
+			{key: "permission", index: -1},
+		}},
+	} {
+		got, err := parsePath(tc.path)
+		if err != nil {
+			t.Errorf("parsePath(%s): %v", tc.path, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("parsePath(%s) = %+v, want %+v", tc.path, got, tc.want)
+		}
+	}
+}
+
+func TestParsePathErrors(t *testing.T) {
+	for path, want := range map[string]string{
+		"":                 "no field",
+		"protections[0":    "missing ]",
+		"protections[0]x":  "expected . after ]",
+		"protections[-1]":  "invalid selection",
+		"protections[abc]": "invalid selection",
+		"a..b":             "has no key",
+	} {
+		_, err := parsePath(path)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("parsePath(%q) = %v, want an error with %q", path, err, want)
+		}
+	}
+}
+
+const lookupSpec = `
+defaultBranch: main
+visibility: public
+protections:
+  - name: main
+    pattern: main
+    enforceAdmins: true
+  - name: release
+    pattern: release/v1.*
+permissions:
+  - team: platform
+    permission: admin
+  - collaborator: alice
+    permission: push
+`
+
+func TestLookup(t *testing.T) {
+	var spec any
+	if err := yaml.Unmarshal([]byte(lookupSpec), &spec); err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		path string
+		want []any
+	}{
+		{"visibility", []any{"public"}},
+		{"protections[1].name", []any{"release"}},
+		{"protections[*].name", []any{"main", "release"}},
+		{"protections[pattern=release/v1.*].name", []any{"release"}},
+		{"protections[pattern=$defaultBranch].enforceAdmins", []any{true}},
+		{"permissions[collaborator=*].permission", []any{"push"}},
+		// missing and invalid paths have no values
+		{"protections[5].name", nil},
+		{"protections[name=dev].name", nil},
+		{"hasWiki", nil},
+		{"visibility[0]", nil},
+		{"protections[0", nil},
+	} {
+		if got := Lookup(spec, tc.path); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("Lookup(%s) = %v, want %v", tc.path, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,292 @@
+// Package policy checks the groups against the org-wide rules, e.g.
+// "public repositories must have secret scanning push protection".
+//
+// The rules live in a yaml file next to the groups dir:
+//
+//	rules:
+//	  - name: public-push-protection
+//	    description: Public repositories must have secret scanning push protection
+//	    selector:
+//	      visibility: [public]
+//	    assert:
+//	      field: securityAndAnalysis[0].secretScanningPushProtection[0].status
+//	      equals: enabled
+//	  - name: no-admin-collaborators
+//	    assert:
+//	      field: permissions[collaborator=*].permission
+//	      notIn: [admin]
+//	exemptions:
+//	  - rule: public-push-protection
+//	    group: docs
+//	    repository: website
+//	    reason: static site without secrets, approved by security
+//
+// The assertions are checked against the effective settings of every repository
+// selected by the rule, see Path for the field syntax.
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"strconv"
+
+	"github.com/artemlive/gh-crossplane/internal/domain"
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the name of the policy file
+const FileName = "policies.yaml"
+
+// Severity of a rule, errors fail the validate command
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+type File struct {
+	Rules      []Rule      `yaml:"rules"`
+	Exemptions []Exemption `yaml:"exemptions,omitempty"`
+}
+
+type Rule struct {
+	Name        string    `yaml:"name"`
+	Description string    `yaml:"description,omitempty"`
+	Severity    string    `yaml:"severity,omitempty"` // error (default) or warning
+	Selector    Selector  `yaml:"selector,omitempty"`
+	Assert      Assertion `yaml:"assert"`
+}
+
+// Selector chooses the repositories a rule applies to, all of them if empty
+type Selector struct {
+	Groups     []string          `yaml:"groups,omitempty"` // group name patterns, e.g. "platform-*"
+	Labels     map[string]string `yaml:"labels,omitempty"` // labels of the group
+	Visibility []string          `yaml:"visibility,omitempty"`
+}
+
+// Assertion is what the values of the field must satisfy.
+// A missing field violates equals, oneOf, min, max and exists: true,
+// it satisfies notIn and exists: false.
+type Assertion struct {
+	Field  string   `yaml:"field"`
+	Exists *bool    `yaml:"exists,omitempty"`
+	Equals any      `yaml:"equals,omitempty"`
+	OneOf  []any    `yaml:"oneOf,omitempty"`
+	NotIn  []any    `yaml:"notIn,omitempty"`
+	Min    *float64 `yaml:"min,omitempty"`
+	Max    *float64 `yaml:"max,omitempty"`
+}
+
+// Exemption excuses a group, or a single repository of it, from a rule
+type Exemption struct {
+	Rule       string `yaml:"rule"`
+	Group      string `yaml:"group"`
+	Repository string `yaml:"repository,omitempty"` // all repositories of the group if empty
+	Reason     string `yaml:"reason"`
+}
+
+// Violation is a repository not satisfying a rule
+type Violation struct {
+	Rule    Rule
+	Group   string
+	Repo    string
+	Message string
+	// Exemption is set if the violation is excused
+	Exemption *Exemption
+}
+
+// Field returns the top level field of the spec the violation is about, e.g. "protections"
+func (v Violation) Field() string {
+	segments, err := parsePath(v.Rule.Assert.Field)
+	if err != nil || len(segments) == 0 {
+		return ""
+	}
+	return segments[0].key
+}
+
+// IsError reports whether the violation should fail the validation
+func (v Violation) IsError() bool {
+	return v.Exemption == nil && v.Rule.Severity != SeverityWarning
+}
+
+func (v Violation) String() string {
+	s := fmt.Sprintf("%s/%s: %s: %s", v.Group, v.Repo, v.Rule.Name, v.Message)
+	if v.Exemption != nil {
+		s += fmt.Sprintf(" (exempt: %s)", v.Exemption.Reason)
+	}
+	return s
+}
+
+// Load reads the policy file, a missing file is the same as an empty one
+func Load(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &File{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var f File
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	if err := f.validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &f, nil
+}
+
+func (f *File) validate() error {
+	var errs []error
+	names := make(map[string]bool)
+	for i, r := range f.Rules {
+		switch {
+		case r.Name == "":
+			errs = append(errs, fmt.Errorf("rule %d has no name", i+1))
+		case names[r.Name]:
+			errs = append(errs, fmt.Errorf("rule %s is declared twice", r.Name))
+		}
+		names[r.Name] = true
+		if r.Severity != "" && r.Severity != SeverityError && r.Severity != SeverityWarning {
+			errs = append(errs, fmt.Errorf("rule %s: unknown severity %q", r.Name, r.Severity))
+		}
+		if _, err := parsePath(r.Assert.Field); err != nil {
+			errs = append(errs, fmt.Errorf("rule %s: %w", r.Name, err))
+		}
+	}
+	for _, e := range f.Exemptions {
+		switch {
+		case !names[e.Rule]:
+			errs = append(errs, fmt.Errorf("exemption of group %s refers to unknown rule %q", e.Group, e.Rule))
+		case e.Reason == "":
+			errs = append(errs, fmt.Errorf("exemption of group %s from rule %s has no reason", e.Group, e.Rule))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Evaluate checks every repository of the group against the rules
+func (f *File) Evaluate(g domain.RepositoriesGroup) []Violation {
+	if f == nil {
+		return nil
+	}
+	var out []Violation
+	for _, repo := range g.Spec.Repositories {
+		spec := domain.Effective(g.Spec, repo)
+		var generic any
+		for _, r := range f.Rules {
+			if !r.Selector.matches(g, spec) {
+				continue
+			}
+			if generic == nil {
+				generic = toValue(spec)
+			}
+			msg, ok := r.Assert.check(generic)
+			if ok {
+				continue
+			}
+			out = append(out, Violation{
+				Rule:      r,
+				Group:     g.Metadata.Name,
+				Repo:      repo.Name,
+				Message:   msg,
+				Exemption: f.exemption(r.Name, g.Metadata.Name, repo.Name),
+			})
+		}
+	}
+	return out
+}
+
+func (f *File) exemption(rule, group, repo string) *Exemption {
+	for i, e := range f.Exemptions {
+		if e.Rule == rule && e.Group == group && (e.Repository == "" || e.Repository == repo) {
+			return &f.Exemptions[i]
+		}
+	}
+	return nil
+}
+
+func (s Selector) matches(g domain.RepositoriesGroup, spec domain.RepositoriesGroupSpec) bool {
+	if len(s.Groups) > 0 && !slices.ContainsFunc(s.Groups, func(p string) bool {
+		ok, _ := path.Match(p, g.Metadata.Name)
+		return ok
+	}) {
+		return false
+	}
+	for k, v := range s.Labels {
+		if g.Metadata.Labels[k] != v {
+			return false
+		}
+	}
+	return len(s.Visibility) == 0 || slices.Contains(s.Visibility, spec.Visibility)
+}
+
+func (a Assertion) check(spec any) (string, bool) {
+	values := Lookup(spec, a.Field)
+	if len(values) == 0 {
+		switch {
+		case a.Exists != nil && !*a.Exists, a.Exists == nil && len(a.NotIn) > 0:
+			return "", true
+		}
+		return fmt.Sprintf("%s is not set", a.Field), false
+	}
+	if a.Exists != nil && !*a.Exists {
+		return fmt.Sprintf("%s must not be set", a.Field), false
+	}
+
+	for _, v := range values {
+		switch {
+		case a.Equals != nil && !equal(v, a.Equals):
+			return fmt.Sprintf("%s is %v, must be %v", a.Field, v, a.Equals), false
+		case len(a.OneOf) > 0 && !slices.ContainsFunc(a.OneOf, func(o any) bool { return equal(v, o) }):
+			return fmt.Sprintf("%s is %v, must be one of %v", a.Field, v, a.OneOf), false
+		case slices.ContainsFunc(a.NotIn, func(o any) bool { return equal(v, o) }):
+			return fmt.Sprintf("%s must not be %v", a.Field, v), false
+		}
+		if a.Min == nil && a.Max == nil {
+			continue
+		}
+		n, ok := number(v)
+		switch {
+		case !ok:
+			return fmt.Sprintf("%s is %v, not a number", a.Field, v), false
+		case a.Min != nil && n < *a.Min:
+			return fmt.Sprintf("%s is %v, must be at least %v", a.Field, v, *a.Min), false
+		case a.Max != nil && n > *a.Max:
+			return fmt.Sprintf("%s is %v, must be at most %v", a.Field, v, *a.Max), false
+		}
+	}
+	return "", true
+}
+
+func equal(a, b any) bool {
+	return fmt.Sprint(a) == fmt.Sprint(b)
+}
+
+func number(v any) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case float64:
+		return n, true
+	case string:
+		f, err := strconv.ParseFloat(n, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// toValue converts the spec to its generic yaml form
+func toValue(spec domain.RepositoriesGroupSpec) any {
+	data, err := yaml.Marshal(spec)
+	if err != nil {
+		return nil
+	}
+	var out any
+	if err := yaml.Unmarshal(data, &out); err != nil {
+		return nil
+	}
+	return out
+}
@@ -0,0 +1,192 @@
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/artemlive/gh-crossplane/internal/domain"
+)
+
+const policies = `
+rules:
+  - name: public-push-protection
+    selector:
+      visibility: [public]
+    assert:
+      field: securityAndAnalysis[0].secretScanningPushProtection[0].status
+      equals: enabled
+  - name: no-admin-collaborators
+    assert:
+      field: permissions[collaborator=*].permission
+      notIn: [admin]
+  - name: platform-reviews
+    severity: warning
+    selector:
+      groups: [platform-*]
+      labels:
+        tier: critical
+    assert:
+      field: protections[pattern=$defaultBranch].requiredPullRequestReviews[0].requiredApprovingReviewCount
+      min: 2
+exemptions:
+  - rule: public-push-protection
+    group: docs
+    repository: website
+    reason: static site without secrets
+`
+
+func loadPolicies(t *testing.T, content string) *File {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), FileName)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	f, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func group(name string, labels map[string]string, spec domain.RepositoriesGroupSpec) domain.RepositoriesGroup {
+	var g domain.RepositoriesGroup
+	g.Metadata.Name = name
+	g.Metadata.Labels = labels
+	g.Spec = spec
+	return g
+}
+
+// violations returns the violations as "repo: rule" strings, the exempt ones end with " (exempt)"
+func violations(f *File, g domain.RepositoriesGroup) []string {
+	var out []string
+	for _, v := range f.Evaluate(g) {
+		s := v.Repo + ": " + v.Rule.Name
+		if v.Exemption != nil {
+			s += " (exempt)"
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+func TestEvaluateSelectors(t *testing.T) {
+	f := loadPolicies(t, policies)
+
+	g := group("web", nil, domain.RepositoriesGroupSpec{
+		Visibility: "private",
+		Repositories: []domain.Repository{
+			{Name: "app"},
+			// the visibility of the repository overrides the one of the group
+			{Name: "site", Visibility: "public"},
+			{Name: "secure", Visibility: "public", SecurityAndAnalysis: []domain.SecAnalysis{{
+				SecretScanningPushProtection: []domain.Status{{Status: "enabled"}},
+			}}},
+		},
+	})
+	if got, want := violations(f, g), []string{"site: public-push-protection"}; !slices.Equal(got, want) {
+		t.Errorf("violations = %q, want %q", got, want)
+	}
+
+	// the group pattern and the labels must both match
+	reviews := []domain.Protection{{Name: "main", Pattern: "main", RequiredPullRequestReviews: []domain.PRReview{{RequiredApprovingReviewCount: 1}}}}
+	spec := domain.RepositoriesGroupSpec{DefaultBranch: "main", Protections: reviews, Repositories: []domain.Repository{{Name: "api"}}}
+	for _, tc := range []struct {
+		name   string
+		labels map[string]string
+		want   []string
+	}{
+		{"platform-core", map[string]string{"tier": "critical"}, []string{"api: platform-reviews"}},
+		{"platform-core", map[string]string{"tier": "low"}, nil},
+		{"web", map[string]string{"tier": "critical"}, nil},
+	} {
+		if got := violations(f, group(tc.name, tc.labels, spec)); !slices.Equal(got, tc.want) {
+			t.Errorf("group %s %v: violations = %q, want %q", tc.name, tc.labels, got, tc.want)
+		}
+	}
+}
+
+func TestEvaluateExemptions(t *testing.T) {
+	f := loadPolicies(t, policies)
+
+	g := group("docs", nil, domain.RepositoriesGroupSpec{
+		Visibility: "public",
+		Permissions: []domain.Permission{
+			{Team: "platform", Permission: "admin"},
+			{Collaborator: "bob", Permission: "admin"},
+		},
+		Repositories: []domain.Repository{{Name: "website"}, {Name: "handbook"}},
+	})
+	want := []string{
+		"website: public-push-protection (exempt)",
+		"website: no-admin-collaborators",
+		"handbook: public-push-protection",
+		"handbook: no-admin-collaborators",
+	}
+	if got := violations(f, g); !slices.Equal(got, want) {
+		t.Errorf("violations = %q, want %q", got, want)
+	}
+	for _, v := range f.Evaluate(g) {
+		if v.IsError() == (v.Exemption != nil) {
+			t.Errorf("%s: IsError = %v", v, v.IsError())
+		}
+	}
+
+	// an exemption without a repository covers the whole group
+	f.Exemptions[0].Repository = ""
+	want = []string{
+		"website: public-push-protection (exempt)",
+		"website: no-admin-collaborators",
+		"handbook: public-push-protection (exempt)",
+		"handbook: no-admin-collaborators",
+	}
+	if got := violations(f, g); !slices.Equal(got, want) {
+		t.Errorf("violations with a group exemption = %q, want %q", got, want)
+	}
+
+	// the exemption of one group doesn't apply to another
+	g.Metadata.Name = "blog"
+	for _, v := range f.Evaluate(g) {
+		if v.Exemption != nil {
+			t.Errorf("%s is exempt in another group", v)
+		}
+	}
+}
+
+func TestEvaluateWarnings(t *testing.T) {
+	f := loadPolicies(t, policies)
+	g := group("platform-core", map[string]string{"tier": "critical"}, domain.RepositoriesGroupSpec{
+		DefaultBranch: "main",
+		Repositories:  []domain.Repository{{Name: "api"}},
+	})
+	v := f.Evaluate(g)
+	if len(v) != 1 || v[0].IsError() || !strings.Contains(v[0].Message, "is not set") {
+		t.Errorf("violations = %v, want a warning about the missing reviews", v)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	for content, want := range map[string]string{
+		"rules:\n  - assert: {field: visibility}\n":                                                        "rule 1 has no name",
+		"rules:\n  - name: a\n    assert: {field: visibility}\n  - name: a\n    assert: {field: topics}\n": "declared twice",
+		"rules:\n  - name: a\n    severity: fatal\n    assert: {field: visibility}\n":                      "unknown severity",
+		"rules:\n  - name: a\n    assert: {field: \"protections[\"}\n":                                     "missing ]",
+		"exemptions:\n  - rule: a\n    group: g\n    reason: r\n":                                          "unknown rule",
+		"rules:\n  - name: a\n    assert: {field: visibility}\nexemptions:\n  - rule: a\n    group: g\n":   "has no reason",
+	} {
+		path := filepath.Join(t.TempDir(), FileName)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(path); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Load(%q) = %v, want an error with %q", content, err, want)
+		}
+	}
+
+	f, err := Load(filepath.Join(t.TempDir(), "missing.yaml"))
+	if err != nil || len(f.Rules) != 0 {
+		t.Errorf("a missing file = %v, %v, want no rules", f, err)
+	}
+}
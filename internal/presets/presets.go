@@ -0,0 +1,120 @@
+// Package presets loads the named templates new groups and repositories start from,
+// e.g. "internal-service" or "public-library". A preset is a yaml file:
+//
+//	name: public-library
+//	description: Open source library with issues and discussions
+//	spec:
+//	  visibility: public
+//	  hasIssues: true
+//	  hasDiscussions: true
+//	repository:
+//	  topics: [library]
+//
+// The spec holds the group settings and the repository the defaults of new repositories.
+package presets
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/artemlive/gh-crossplane/internal/domain"
+	"gopkg.in/yaml.v3"
+)
+
+// DirName is the name of the presets directory
+const DirName = "presets"
+
+type Preset struct {
+	Name        string                       `yaml:"name"`
+	Description string                       `yaml:"description,omitempty"`
+	Spec        domain.RepositoriesGroupSpec `yaml:"spec,omitempty"`
+	Repository  domain.Repository            `yaml:"repository,omitempty"`
+}
+
+func (p Preset) Title() string {
+	return p.Name
+}
+
+func (p Preset) FilterValue() string {
+	return p.Name
+}
+
+// Load reads the presets from the yaml files of the directory, sorted by name.
+// A missing directory has no presets. The name defaults to the file name.
+func Load(dir string) ([]Preset, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var out []Preset
+	for _, e := range entries {
+		ext := filepath.Ext(e.Name())
+		if e.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var p Preset
+		if err := yaml.Unmarshal(data, &p); err != nil {
+			return nil, fmt.Errorf("parse %s: %w", path, err)
+		}
+		if p.Name == "" {
+			p.Name = strings.TrimSuffix(e.Name(), ext)
+		}
+		if slices.ContainsFunc(out, func(o Preset) bool { return o.Name == p.Name }) {
+			return nil, fmt.Errorf("%s: preset %s is declared twice", path, p.Name)
+		}
+		out = append(out, p)
+	}
+	slices.SortFunc(out, func(a, b Preset) int { return strings.Compare(a.Name, b.Name) })
+	return out, nil
+}
+
+// Find returns the preset with the name, nil if there's none
+func Find(presets []Preset, name string) *Preset {
+	for i := range presets {
+		if presets[i].Name == name {
+			return &presets[i]
+		}
+	}
+	return nil
+}
+
+// NewGroup creates a group with the settings of the preset
+func (p Preset) NewGroup(name, apiVersion string) domain.RepositoriesGroup {
+	spec := domain.DeepCopy(p.Spec)
+	spec.Repositories = nil
+	return domain.RepositoriesGroup{
+		APIVersion: apiVersion,
+		Kind:       domain.Kind,
+		Metadata:   domain.Metadata{Name: name},
+		Spec:       spec,
+	}
+}
+
+// NewRepository creates a repository with the defaults of the preset
+func (p Preset) NewRepository(name, description string) domain.Repository {
+	repo := p.Repository.Clone()
+	repo.Name = name
+	if description != "" {
+		repo.Description = description
+	}
+	return repo
+}
+
+// Apply returns the group with the settings of the preset merged in, see domain.Overlay
+func (p Preset) Apply(g domain.RepositoriesGroup) domain.RepositoriesGroup {
+	g.Spec = domain.Overlay(g.Spec, domain.DeepCopy(p.Spec))
+	return g
+}
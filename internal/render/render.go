@@ -0,0 +1,212 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/artemlive/gh-crossplane/internal/domain"
+	"github.com/artemlive/gh-crossplane/internal/reponame"
+	"github.com/artemlive/gh-crossplane/internal/util"
+	"gopkg.in/yaml.v3"
+)
+
+// The API groups of the provider-github managed resources
+const (
+	RepoAPIVersion = "repo.github.upbound.io/v1alpha1"
+	TeamAPIVersion = "team.github.upbound.io/v1alpha1"
+)
+
+// GroupLabel is set on every resource to the name of the group producing it
+const GroupLabel = "github.platform.crossplane.io/group"
+
+// Resource is a Crossplane managed resource
+type Resource struct {
+	APIVersion string       `yaml:"apiVersion"`
+	Kind       string       `yaml:"kind"`
+	Metadata   ObjectMeta   `yaml:"metadata"`
+	Spec       ResourceSpec `yaml:"spec"`
+}
+
+type ObjectMeta struct {
+	Name        string            `yaml:"name"`
+	Labels      map[string]string `yaml:"labels,omitempty"`
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+}
+
+type ResourceSpec struct {
+	DeletionPolicy     string         `yaml:"deletionPolicy,omitempty"`
+	ManagementPolicies []string       `yaml:"managementPolicies,omitempty"`
+	ForProvider        map[string]any `yaml:"forProvider"`
+}
+
+// notRepositoryFields are the spec fields which aren't set on the Repository resource,
+// they are rendered as separate resources or apply to all of them
+var notRepositoryFields = map[string]bool{
+	"Repositories":       true,
+	"Permissions":        true,
+	"Protections":        true,
+	"AutolinkReferences": true,
+	"DefaultBranch":      true,
+	"DeletionPolicy":     true,
+	"ManagementPolicies": true,
+}
+
+// Group renders the managed resources of every repository of the group:
+// the Repository, its default branch, the branch protections, the team and
+// collaborator permissions and the autolink references.
+func Group(g domain.RepositoriesGroup) []Resource {
+	var out []Resource
+	for _, repo := range g.Spec.Repositories {
+		out = append(out, Repository(g, repo)...)
+	}
+	return out
+}
+
+// Repository renders the managed resources of a single repository of the group.
+func Repository(g domain.RepositoriesGroup, repo domain.Repository) []Resource {
+	spec := domain.Effective(g.Spec, repo)
+	r := renderer{group: g, repo: repo}
+
+	var out []Resource
+	repoResource := r.resource(RepoAPIVersion, "Repository", repo.Name, r.repositoryParameters(spec))
+	// the object name is sanitized, the external name keeps the repository name as is
+	repoResource.Metadata.Annotations = map[string]string{reponame.ExternalNameAnnotation: repo.Name}
+	for k, v := range repo.Annotations {
+		repoResource.Metadata.Annotations[k] = v
+	}
+	out = append(out, repoResource)
+
+	if spec.DefaultBranch != "" {
+		out = append(out, r.resource(RepoAPIVersion, "BranchDefault", repo.Name+"-default-branch", map[string]any{
+			"repository": repo.Name,
+			"branch":     spec.DefaultBranch,
+		}))
+	}
+
+	for _, p := range spec.Protections {
+		params := toParameters(p)
+		delete(params, "name")
+		params["repositoryIdRef"] = map[string]any{"name": resourceName(repo.Name)}
+		out = append(out, r.resource(RepoAPIVersion, "BranchProtection", repo.Name+"-"+p.Name, params))
+	}
+
+	for _, p := range spec.Permissions {
+		switch {
+		case p.Team != "":
+			out = append(out, r.resource(TeamAPIVersion, "TeamRepository", repo.Name+"-team-"+p.Team, map[string]any{
+				"repository": repo.Name,
+				"teamId":     p.Team,
+				"permission": p.Permission,
+			}))
+		case p.Collaborator != "":
+			out = append(out, r.resource(RepoAPIVersion, "RepositoryCollaborator", repo.Name+"-collaborator-"+p.Collaborator, map[string]any{
+				"repository": repo.Name,
+				"username":   p.Collaborator,
+				"permission": p.Permission,
+			}))
+		}
+	}
+
+	for _, a := range spec.AutolinkReferences {
+		params := toParameters(a)
+		delete(params, "name")
+		params["repository"] = repo.Name
+		out = append(out, r.resource(RepoAPIVersion, "RepositoryAutolinkReference", repo.Name+"-autolink-"+a.Name, params))
+	}
+	return out
+}
+
+type renderer struct {
+	group domain.RepositoriesGroup
+	repo  domain.Repository
+}
+
+func (r renderer) resource(apiVersion, kind, name string, params map[string]any) Resource {
+	labels := map[string]string{GroupLabel: r.group.Metadata.Name}
+	for k, v := range r.group.Metadata.Labels {
+		labels[k] = v
+	}
+	return Resource{
+		APIVersion: apiVersion,
+		Kind:       kind,
+		Metadata: ObjectMeta{
+			Name:   resourceName(name),
+			Labels: labels,
+		},
+		Spec: ResourceSpec{
+			DeletionPolicy:     r.group.Spec.DeletionPolicy,
+			ManagementPolicies: r.group.Spec.ManagementPolicies,
+			ForProvider:        params,
+		},
+	}
+}
+
+// repositoryParameters are the set settings of the effective spec the Repository resource takes
+func (r renderer) repositoryParameters(spec domain.RepositoriesGroupSpec) map[string]any {
+	params := make(map[string]any)
+	v := reflect.ValueOf(spec)
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if notRepositoryFields[sf.Name] || v.Field(i).IsZero() {
+			continue
+		}
+		params[util.YAMLName(sf)] = toValue(v.Field(i).Interface())
+	}
+	if r.repo.Description != "" {
+		params["description"] = r.repo.Description
+	}
+	if r.repo.Archived != nil {
+		params["archived"] = *r.repo.Archived
+	}
+	return params
+}
+
+// toParameters converts a struct to a map keyed by the yaml names of its set fields
+func toParameters(v any) map[string]any {
+	out, _ := toValue(v).(map[string]any)
+	if out == nil {
+		out = make(map[string]any)
+	}
+	return out
+}
+
+// toValue converts a domain value to its generic yaml form
+func toValue(v any) any {
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var out any
+	if err := yaml.Unmarshal(data, &out); err != nil {
+		return nil
+	}
+	return out
+}
+
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// resourceName turns the name into a valid Kubernetes object name
+func resourceName(name string) string {
+	name = invalidNameChars.ReplaceAllString(strings.ToLower(name), "-")
+	return strings.Trim(name, "-")
+}
+
+// Encode writes the resources as a multi document yaml stream, nothing if there are none
+func Encode(w io.Writer, resources []Resource) error {
+	if len(resources) == 0 {
+		// the encoder fails to close a stream without documents
+		return nil
+	}
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	for _, r := range resources {
+		if err := enc.Encode(r); err != nil {
+			return fmt.Errorf("encode %s %s: %w", r.Kind, r.Metadata.Name, err)
+		}
+	}
+	return enc.Close()
+}
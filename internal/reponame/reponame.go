@@ -0,0 +1,226 @@
+package reponame
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+
+	"github.com/artemlive/gh-crossplane/internal/domain"
+	"github.com/artemlive/gh-crossplane/internal/github"
+	"github.com/artemlive/gh-crossplane/internal/importer"
+	"github.com/artemlive/gh-crossplane/internal/manifest"
+)
+
+// ExternalNameAnnotation is the name of the GitHub repository a managed Repository refers to.
+// Without it Crossplane takes the repository name as the external name.
+const ExternalNameAnnotation = "crossplane.io/external-name"
+
+// Lookup is the part of the API used to check whether a repository exists.
+// github.Client implements it, github.NewFixtureClient serves it from files.
+type Lookup interface {
+	GetRepository(owner, name string) (*github.Repository, error)
+}
+
+// Availability tells where a repository name is already used
+type Availability struct {
+	Name     string
+	Group    string // group managing the repository, empty if none
+	OnGitHub bool   // the repository exists in the organization
+}
+
+// Managed looks the name up in the loaded groups only, it doesn't need the network.
+func Managed(groups []manifest.GroupFile, name string) Availability {
+	return Availability{
+		Name:  name,
+		Group: importer.ManagedRepositories(groups)[name],
+	}
+}
+
+// Check looks the name up in the loaded groups and in the organization.
+func Check(gh Lookup, owner string, groups []manifest.GroupFile, name string) (Availability, error) {
+	a := Managed(groups, name)
+	_, err := gh.GetRepository(owner, name)
+	switch {
+	case github.IsNotFound(err):
+	case err != nil:
+		return a, fmt.Errorf("check repository %s/%s: %w", owner, name, err)
+	default:
+		a.OnGitHub = true
+	}
+	return a, nil
+}
+
+// Available reports whether a new repository can be created with the name
+func (a Availability) Available() bool {
+	return a.Group == "" && !a.OnGitHub
+}
+
+// Warning explains why the name can't be used for a new repository
+func (a Availability) Warning() string {
+	switch {
+	case a.Group != "":
+		return fmt.Sprintf("Repository '%s' is already managed by group '%s'.", a.Name, a.Group)
+	case a.OnGitHub:
+		return fmt.Sprintf("Repository '%s' already exists on GitHub but isn't managed by any group, import it instead.", a.Name)
+	}
+	return ""
+}
+
+// Rename is a repository of a group whose name was changed
+type Rename struct {
+	Group string
+	From  string
+	To    string
+}
+
+// Renames finds the repositories renamed between two versions of a group.
+// The names gone from the group are matched to the new names: first by the
+// external name pointing to the old name, then by the settings left unchanged,
+// and the rest by their order if as many names were removed as added.
+func Renames(base, mine domain.RepositoriesGroup) []Rename {
+	removed := newNames(base, mine)
+	added := newNames(mine, base)
+
+	var out []Rename
+	match := func(from domain.Repository, ai int) {
+		out = append(out, Rename{
+			Group: mine.Metadata.Name,
+			From:  from.Name,
+			To:    added[ai].Name,
+		})
+		added = slices.Delete(added, ai, ai+1)
+	}
+
+	// the external name tells the old name for sure
+	removed = slices.DeleteFunc(removed, func(r domain.Repository) bool {
+		ai := slices.IndexFunc(added, func(a domain.Repository) bool {
+			return a.Annotations[ExternalNameAnnotation] == r.Name
+		})
+		if ai >= 0 {
+			match(r, ai)
+		}
+		return ai >= 0
+	})
+	// only the name was edited
+	removed = slices.DeleteFunc(removed, func(r domain.Repository) bool {
+		ai := slices.IndexFunc(added, func(a domain.Repository) bool { return sameSettings(a, r) })
+		if ai >= 0 {
+			match(r, ai)
+		}
+		return ai >= 0
+	})
+	// the name and the settings were edited, the editor keeps the order
+	if len(removed) == len(added) {
+		for _, r := range removed {
+			match(r, 0)
+		}
+	}
+
+	slices.SortStableFunc(out, func(a, b Rename) int {
+		return repositoryIndex(mine, a.To) - repositoryIndex(mine, b.To)
+	})
+	return out
+}
+
+// newNames returns the repositories of g whose names aren't in other
+func newNames(g, other domain.RepositoriesGroup) []domain.Repository {
+	var out []domain.Repository
+	for _, r := range g.Spec.Repositories {
+		if r.Name != "" && findRepository(other, r.Name) == nil {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// sameSettings reports whether the repositories differ only by their names and annotations
+func sameSettings(a, b domain.Repository) bool {
+	a.Name, b.Name = "", ""
+	a.Annotations, b.Annotations = nil, nil
+	return reflect.DeepEqual(a, b)
+}
+
+func repositoryIndex(g domain.RepositoriesGroup, name string) int {
+	return slices.IndexFunc(g.Spec.Repositories, func(r domain.Repository) bool { return r.Name == name })
+}
+
+// RenameWarnings warns about the renamed repositories Crossplane would delete and
+// recreate, which are those without an external name pointing to the old name.
+func RenameWarnings(base, mine domain.RepositoriesGroup) []string {
+	var warnings []string
+	for _, r := range Renames(base, mine) {
+		if repo := findRepository(mine, r.To); repo != nil && repo.Annotations[ExternalNameAnnotation] == r.From {
+			continue
+		}
+		warnings = append(warnings, fmt.Sprintf(
+			"renaming %s to %s makes Crossplane recreate the repository, set the %s annotation to %s to rename it in place",
+			r.From, r.To, ExternalNameAnnotation, r.From))
+	}
+	return warnings
+}
+
+func findRepository(g domain.RepositoriesGroup, name string) *domain.Repository {
+	for i := range g.Spec.Repositories {
+		if g.Spec.Repositories[i].Name == name {
+			return &g.Spec.Repositories[i]
+		}
+	}
+	return nil
+}
+
+// GroupRenameWarnings explains what Crossplane does when the group is renamed:
+// the composite with the old name is deleted along with its managed repositories,
+// and a new one is created for the new name.
+func GroupRenameWarnings(g domain.RepositoriesGroup) []string {
+	warnings := []string{
+		"Crossplane deletes the group with the old name and its managed resources, then creates them again for the new name.",
+	}
+	if g.Spec.DeletionPolicy != "Orphan" {
+		policy := g.Spec.DeletionPolicy
+		if policy == "" {
+			policy = "Delete (the default)"
+		}
+		warnings = append(warnings, fmt.Sprintf(
+			"The deletion policy is %s, so the GitHub repositories are deleted with the old group. Set it to Orphan and roll that out before renaming.", policy))
+	}
+	if n := MissingExternalNames(g); n > 0 {
+		warnings = append(warnings, fmt.Sprintf(
+			"%d repo(s) have no %s annotation, without it the new resources may not adopt the existing repositories.", n, ExternalNameAnnotation))
+	}
+	return warnings
+}
+
+// MissingExternalNames counts the repositories without an external name
+func MissingExternalNames(g domain.RepositoriesGroup) int {
+	n := 0
+	for _, r := range g.Spec.Repositories {
+		if r.Annotations[ExternalNameAnnotation] == "" {
+			n++
+		}
+	}
+	return n
+}
+
+// SetExternalNames points the repositories without an external name to the
+// repository of the same name, so they are adopted instead of created when
+// their managed resources are recreated. It returns the number of repositories set.
+// The repositories and their annotations are copied, g may share them with other groups.
+func SetExternalNames(g *domain.RepositoriesGroup) int {
+	repos := slices.Clone(g.Spec.Repositories)
+	n := 0
+	for i := range repos {
+		r := &repos[i]
+		if r.Annotations[ExternalNameAnnotation] != "" || r.Name == "" {
+			continue
+		}
+		r.Annotations = maps.Clone(r.Annotations)
+		if r.Annotations == nil {
+			r.Annotations = make(map[string]string)
+		}
+		r.Annotations[ExternalNameAnnotation] = r.Name
+		n++
+	}
+	g.Spec.Repositories = repos
+	return n
+}
@@ -0,0 +1,142 @@
+package reponame
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/artemlive/gh-crossplane/internal/domain"
+	"github.com/artemlive/gh-crossplane/internal/github"
+	"github.com/artemlive/gh-crossplane/internal/manifest"
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// fakeLookup knows the repositories of the organization, the other names aren't found
+type fakeLookup struct {
+	repos map[string]bool
+	err   error
+}
+
+func (f fakeLookup) GetRepository(owner, name string) (*github.Repository, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	if !f.repos[owner+"/"+name] {
+		return nil, &api.HTTPError{StatusCode: http.StatusNotFound}
+	}
+	return &github.Repository{Name: name, FullName: owner + "/" + name}, nil
+}
+
+func group(name string, repos ...domain.Repository) domain.RepositoriesGroup {
+	g := domain.RepositoriesGroup{Metadata: domain.Metadata{Name: name}}
+	g.Spec.Repositories = repos
+	return g
+}
+
+func TestCheck(t *testing.T) {
+	groups := []manifest.GroupFile{{Manifest: group("team", domain.Repository{Name: "api"})}}
+	gh := fakeLookup{repos: map[string]bool{"acme/api": true, "acme/legacy": true}}
+
+	for _, tc := range []struct {
+		name      string
+		want      Availability
+		available bool
+	}{
+		{"api", Availability{Name: "api", Group: "team", OnGitHub: true}, false},
+		{"legacy", Availability{Name: "legacy", OnGitHub: true}, false},
+		{"web", Availability{Name: "web"}, true},
+	} {
+		got, err := Check(gh, "acme", groups, tc.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tc.want || got.Available() != tc.available {
+			t.Errorf("Check(%q) = %+v, want %+v", tc.name, got, tc.want)
+		}
+		if (got.Warning() == "") != tc.available {
+			t.Errorf("Check(%q) warning = %q", tc.name, got.Warning())
+		}
+	}
+
+	if _, err := Check(fakeLookup{err: errors.New("rate limited")}, "acme", groups, "web"); err == nil {
+		t.Error("expected the lookup error")
+	}
+}
+
+func TestRenames(t *testing.T) {
+	private := domain.Repository{Visibility: "private"}
+	repo := func(name string, settings domain.Repository) domain.Repository {
+		settings.Name = name
+		return settings
+	}
+
+	for _, tc := range []struct {
+		name       string
+		base, mine domain.RepositoriesGroup
+		want       []Rename
+	}{
+		{
+			name: "in place",
+			base: group("team", repo("api", private), repo("web", domain.Repository{})),
+			mine: group("team", repo("api-v2", private), repo("web", domain.Repository{})),
+			want: []Rename{{"team", "api", "api-v2"}},
+		},
+		{
+			name: "removed and renamed",
+			base: group("team", repo("old", domain.Repository{}), repo("api", private)),
+			mine: group("team", repo("api-v2", private)),
+			want: []Rename{{"team", "api", "api-v2"}},
+		},
+		{
+			name: "renamed and added",
+			base: group("team", repo("api", private)),
+			mine: group("team", repo("new", domain.Repository{}), repo("api-v2", private)),
+			want: []Rename{{"team", "api", "api-v2"}},
+		},
+		{
+			name: "by external name",
+			base: group("team", repo("api", private), repo("web", domain.Repository{})),
+			mine: group("team",
+				repo("site", domain.Repository{Annotations: map[string]string{ExternalNameAnnotation: "web"}}),
+				repo("service", private)),
+			want: []Rename{{"team", "web", "site"}, {"team", "api", "service"}},
+		},
+		{
+			name: "renamed and edited",
+			base: group("team", repo("api", private)),
+			mine: group("team", repo("api-v2", domain.Repository{Visibility: "public"})),
+			want: []Rename{{"team", "api", "api-v2"}},
+		},
+		{
+			name: "replaced",
+			base: group("team", repo("api", private), repo("web", domain.Repository{})),
+			mine: group("team", repo("docs", domain.Repository{Visibility: "public"})),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Renames(tc.base, tc.mine); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Renames = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRenameWarnings(t *testing.T) {
+	base := group("team", domain.Repository{Name: "api"})
+	annotated := func(external string) domain.RepositoriesGroup {
+		return group("team", domain.Repository{
+			Name:        "api-v2",
+			Annotations: map[string]string{ExternalNameAnnotation: external},
+		})
+	}
+
+	if w := RenameWarnings(base, annotated("api")); len(w) != 0 {
+		t.Errorf("warnings = %q, want none when the external name is the old name", w)
+	}
+	w := RenameWarnings(base, annotated("api-v2"))
+	if len(w) != 1 || !strings.Contains(w[0], "set the "+ExternalNameAnnotation+" annotation to api") {
+		t.Errorf("warnings = %q, want one asking for the old name", w)
+	}
+}
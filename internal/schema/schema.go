@@ -0,0 +1,144 @@
+package schema
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/artemlive/gh-crossplane/internal/domain"
+	"github.com/artemlive/gh-crossplane/internal/util"
+)
+
+// Schema is the subset of an OpenAPI v3 schema used by Kubernetes CRDs
+type Schema struct {
+	Type                 string             `yaml:"type,omitempty"`
+	Description          string             `yaml:"description,omitempty"`
+	Enum                 []string           `yaml:"enum,omitempty"`
+	Default              any                `yaml:"default,omitempty"`
+	Properties           map[string]*Schema `yaml:"properties,omitempty"`
+	Required             []string           `yaml:"required,omitempty"`
+	Items                *Schema            `yaml:"items,omitempty"`
+	AdditionalProperties *Schema            `yaml:"additionalProperties,omitempty"`
+}
+
+// Spec returns the schema of the RepositoriesGroup spec
+func Spec() *Schema {
+	return FromType(reflect.TypeOf(domain.RepositoriesGroupSpec{}))
+}
+
+// FromType derives the schema of a domain type. The properties are named after
+// the yaml tags, the fields without omitempty are required. The ui tag provides
+// the description (the label) and the allowed values (enum=a|b).
+func FromType(t reflect.Type) *Schema {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return FromType(t.Elem())
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		return &Schema{Type: "integer"}
+	case reflect.Slice:
+		return &Schema{Type: "array", Items: FromType(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: FromType(t.Elem())}
+	case reflect.Struct:
+		s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			name := util.YAMLName(sf)
+			if name == "-" {
+				continue
+			}
+			prop := FromType(sf.Type)
+			meta := util.ParseTag(sf.Tag.Get("ui"))
+			prop.Description = meta["label"]
+			if enum := meta["enum"]; enum != "" {
+				prop.Enum = strings.Split(enum, "|")
+			}
+			s.Properties[name] = prop
+			if !strings.Contains(sf.Tag.Get("yaml"), "omitempty") {
+				s.Required = append(s.Required, name)
+			}
+		}
+		return s
+	}
+	return &Schema{}
+}
+
+// Mismatch is a difference between two schemas
+type Mismatch struct {
+	Path    string // e.g. "spec.protections[].pattern"
+	Problem string
+}
+
+func (m Mismatch) String() string {
+	return m.Path + ": " + m.Problem
+}
+
+// Compare reports where the schema of the domain types (want) and the schema
+// found in the XRD (got) disagree on the properties, types, enums and required fields.
+// Descriptions and defaults are not compared.
+func Compare(path string, want, got *Schema) []Mismatch {
+	var out []Mismatch
+	add := func(problem string, args ...any) {
+		out = append(out, Mismatch{Path: path, Problem: fmt.Sprintf(problem, args...)})
+	}
+
+	if want.Type != got.Type {
+		add("type is %s in the domain types, %s in the XRD", orNone(want.Type), orNone(got.Type))
+		return out
+	}
+	if !sameSet(want.Enum, got.Enum) {
+		add("allowed values are [%s] in the domain types, [%s] in the XRD", strings.Join(want.Enum, ", "), strings.Join(got.Enum, ", "))
+	}
+	if !sameSet(want.Required, got.Required) {
+		add("required fields are [%s] in the domain types, [%s] in the XRD", strings.Join(sorted(want.Required), ", "), strings.Join(sorted(got.Required), ", "))
+	}
+
+	for _, name := range sortedKeys(want.Properties) {
+		gp, ok := got.Properties[name]
+		if !ok {
+			out = append(out, Mismatch{Path: path + "." + name, Problem: "missing in the XRD"})
+			continue
+		}
+		out = append(out, Compare(path+"."+name, want.Properties[name], gp)...)
+	}
+	for _, name := range sortedKeys(got.Properties) {
+		if _, ok := want.Properties[name]; !ok {
+			out = append(out, Mismatch{Path: path + "." + name, Problem: "missing in the domain types"})
+		}
+	}
+
+	if want.Items != nil && got.Items != nil {
+		out = append(out, Compare(path+"[]", want.Items, got.Items)...)
+	}
+	if want.AdditionalProperties != nil && got.AdditionalProperties != nil {
+		out = append(out, Compare(path+"{}", want.AdditionalProperties, got.AdditionalProperties)...)
+	}
+	return out
+}
+
+func sameSet(a, b []string) bool {
+	return slices.Equal(sorted(a), sorted(b))
+}
+
+func sorted(s []string) []string {
+	s = slices.Clone(s)
+	slices.Sort(s)
+	return s
+}
+
+func sortedKeys(m map[string]*Schema) []string {
+	return slices.Sorted(maps.Keys(m))
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "<none>"
+	}
+	return s
+}
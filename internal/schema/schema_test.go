@@ -0,0 +1,176 @@
+package schema
+
+import (
+	"bytes"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/artemlive/gh-crossplane/internal/domain"
+	"gopkg.in/yaml.v3"
+)
+
+const apiVersion = "github.platform.crossplane.io/v1alpha1"
+
+func TestFromType(t *testing.T) {
+	type item struct {
+		Name string `yaml:"name"`
+	}
+	type spec struct {
+		Visibility string            `yaml:"visibility,omitempty" ui:"type=text,label=Visibility,enum=public|private"`
+		HasWiki    *bool             `yaml:"hasWiki,omitempty" ui:"type=checkbox,label=Has Wiki"`
+		Count      int               `yaml:"count"`
+		Items      []item            `yaml:"items,omitempty"`
+		Labels     map[string]string `yaml:"labels,omitempty"`
+		Ignored    string            `yaml:"-"`
+	}
+
+	want := &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"visibility": {Type: "string", Description: "Visibility", Enum: []string{"public", "private"}},
+			"hasWiki":    {Type: "boolean", Description: "Has Wiki"},
+			"count":      {Type: "integer"},
+			"items": {Type: "array", Items: &Schema{
+				Type:       "object",
+				Properties: map[string]*Schema{"name": {Type: "string"}},
+				Required:   []string{"name"},
+			}},
+			"labels": {Type: "object", AdditionalProperties: &Schema{Type: "string"}},
+		},
+		Required: []string{"count"},
+	}
+	if got := FromType(reflect.TypeOf(spec{})); !reflect.DeepEqual(got, want) {
+		t.Errorf("FromType = %s, want %s", encode(t, got), encode(t, want))
+	}
+}
+
+// TestSpecMatchesXRD fails when the domain types change without the XRD,
+// regenerate it with "gh crossplane schema > internal/schema/testdata/xrd.yaml"
+// and apply it to the cluster along with the change.
+func TestSpecMatchesXRD(t *testing.T) {
+	xrd, err := LoadXRD("testdata/xrd.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mismatches, err := Check(xrd, apiVersion, Spec())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range mismatches {
+		t.Error(m)
+	}
+}
+
+func TestCheckReportsDrift(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		change func(spec *Schema)
+		want   []Mismatch
+	}{
+		{
+			name:   "missing field",
+			change: func(spec *Schema) { delete(spec.Properties, "visibility") },
+			want:   []Mismatch{{Path: "spec.visibility", Problem: "missing in the XRD"}},
+		},
+		{
+			name: "unknown field",
+			change: func(spec *Schema) {
+				spec.Properties["hasProjects"] = &Schema{Type: "boolean"}
+			},
+			want: []Mismatch{{Path: "spec.hasProjects", Problem: "missing in the domain types"}},
+		},
+		{
+			name:   "type",
+			change: func(spec *Schema) { spec.Properties["hasWiki"].Type = "string" },
+			want:   []Mismatch{{Path: "spec.hasWiki", Problem: "type is boolean in the domain types, string in the XRD"}},
+		},
+		{
+			name: "enum",
+			change: func(spec *Schema) {
+				spec.Properties["visibility"].Enum = []string{"public", "private"}
+			},
+			want: []Mismatch{{Path: "spec.visibility", Problem: "allowed values are [public, private, internal] in the domain types, [public, private] in the XRD"}},
+		},
+		{
+			name: "nested",
+			change: func(spec *Schema) {
+				perm := spec.Properties["repositories"].Items.Properties["permissions"].Items
+				perm.Properties["permission"].Enum = append(perm.Properties["permission"].Enum, "owner")
+				perm.Required = nil
+			},
+			want: []Mismatch{
+				{Path: "spec.repositories[].permissions[]", Problem: "required fields are [permission] in the domain types, [] in the XRD"},
+				{Path: "spec.repositories[].permissions[].permission", Problem: "allowed values are [pull, triage, push, maintain, admin] in the domain types, [pull, triage, push, maintain, admin, owner] in the XRD"},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			xrd := generate(t)
+			spec, err := xrd.SpecSchema("v1alpha1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			tc.change(spec)
+
+			got, err := Check(xrd, apiVersion, Spec())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("Check = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGenerateXRD(t *testing.T) {
+	xrd := generate(t)
+	if xrd.Spec.ClaimNames.Kind != domain.Kind || xrd.Spec.Names.Kind != "X"+domain.Kind {
+		t.Errorf("names = %+v, claim names = %+v", xrd.Spec.Names, xrd.Spec.ClaimNames)
+	}
+	if xrd.Metadata.Name != "xrepositoriesgroups.github.platform.crossplane.io" {
+		t.Errorf("name = %s", xrd.Metadata.Name)
+	}
+	if _, err := xrd.SpecSchema("v1beta1"); err == nil {
+		t.Error("the XRD has a version it wasn't generated for")
+	}
+
+	// the generated XRD matches the domain types
+	mismatches, err := Check(xrd, apiVersion, Spec())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mismatches) > 0 {
+		t.Errorf("mismatches in the generated XRD: %v", mismatches)
+	}
+
+	if _, err := GenerateXRD("v1alpha1", Spec()); err == nil {
+		t.Error("GenerateXRD accepted an apiVersion without a group")
+	}
+}
+
+// generate returns a generated XRD the way it's read from a file
+func generate(t *testing.T) *XRD {
+	t.Helper()
+	xrd, err := GenerateXRD(apiVersion, Spec())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out XRD
+	if err := yaml.Unmarshal([]byte(encode(t, xrd)), &out); err != nil {
+		t.Fatal(err)
+	}
+	return &out
+}
+
+func encode(t *testing.T, v any) string {
+	t.Helper()
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(v); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
@@ -0,0 +1,120 @@
+package schema
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/artemlive/gh-crossplane/internal/domain"
+	"gopkg.in/yaml.v3"
+)
+
+// XRD is a Crossplane CompositeResourceDefinition
+type XRD struct {
+	APIVersion string      `yaml:"apiVersion"`
+	Kind       string      `yaml:"kind"`
+	Metadata   XRDMetadata `yaml:"metadata"`
+	Spec       XRDSpec     `yaml:"spec"`
+}
+
+type XRDMetadata struct {
+	Name string `yaml:"name"`
+}
+
+type XRDSpec struct {
+	Group      string       `yaml:"group"`
+	Names      XRDNames     `yaml:"names"`
+	ClaimNames *XRDNames    `yaml:"claimNames,omitempty"`
+	Versions   []XRDVersion `yaml:"versions"`
+}
+
+type XRDNames struct {
+	Kind   string `yaml:"kind"`
+	Plural string `yaml:"plural"`
+}
+
+type XRDVersion struct {
+	Name          string `yaml:"name"`
+	Served        bool   `yaml:"served"`
+	Referenceable bool   `yaml:"referenceable"`
+	Schema        struct {
+		OpenAPIV3Schema *Schema `yaml:"openAPIV3Schema"`
+	} `yaml:"schema"`
+}
+
+// GenerateXRD builds the XRD of the RepositoriesGroup claims with the given apiVersion,
+// e.g. "github.platform.crossplane.io/v1alpha1", and spec schema. The composite kind
+// is the claim kind prefixed with X, as usual in Crossplane.
+func GenerateXRD(apiVersion string, spec *Schema) (*XRD, error) {
+	group, version, ok := strings.Cut(apiVersion, "/")
+	if !ok {
+		return nil, fmt.Errorf("invalid apiVersion %q, expected <group>/<version>", apiVersion)
+	}
+
+	kind := domain.Kind
+	plural := strings.ToLower(kind) + "s"
+	xrd := &XRD{
+		APIVersion: "apiextensions.crossplane.io/v1",
+		Kind:       "CompositeResourceDefinition",
+		Metadata:   XRDMetadata{Name: "x" + plural + "." + group},
+		Spec: XRDSpec{
+			Group:      group,
+			Names:      XRDNames{Kind: "X" + kind, Plural: "x" + plural},
+			ClaimNames: &XRDNames{Kind: kind, Plural: plural},
+		},
+	}
+
+	v := XRDVersion{Name: version, Served: true, Referenceable: true}
+	v.Schema.OpenAPIV3Schema = &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"spec": spec,
+		},
+		Required: []string{"spec"},
+	}
+	xrd.Spec.Versions = []XRDVersion{v}
+	return xrd, nil
+}
+
+// LoadXRD reads a CompositeResourceDefinition from a yaml file
+func LoadXRD(path string) (*XRD, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var xrd XRD
+	if err := yaml.Unmarshal(data, &xrd); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	if xrd.Kind != "CompositeResourceDefinition" {
+		return nil, fmt.Errorf("%s is a %s, not a CompositeResourceDefinition", path, xrd.Kind)
+	}
+	return &xrd, nil
+}
+
+// SpecSchema returns the schema of the spec in the given version of the XRD,
+// the first version if version is empty
+func (x *XRD) SpecSchema(version string) (*Schema, error) {
+	for _, v := range x.Spec.Versions {
+		if version != "" && v.Name != version {
+			continue
+		}
+		root := v.Schema.OpenAPIV3Schema
+		if root == nil || root.Properties["spec"] == nil {
+			return nil, fmt.Errorf("version %s of %s has no spec schema", v.Name, x.Metadata.Name)
+		}
+		return root.Properties["spec"], nil
+	}
+	return nil, fmt.Errorf("%s has no version %s", x.Metadata.Name, version)
+}
+
+// Check compares the spec schema of the XRD with the expected one.
+// The version is taken from apiVersion, e.g. "github.platform.crossplane.io/v1alpha1".
+func Check(x *XRD, apiVersion string, want *Schema) ([]Mismatch, error) {
+	_, version, _ := strings.Cut(apiVersion, "/")
+	got, err := x.SpecSchema(version)
+	if err != nil {
+		return nil, err
+	}
+	return Compare("spec", want, got), nil
+}
@@ -0,0 +1,88 @@
+package field
+
+import (
+	"slices"
+	"strings"
+
+	ui "github.com/artemlive/gh-crossplane/internal/ui/shared"
+	"github.com/artemlive/gh-crossplane/internal/ui/style"
+	tea "github.com/charmbracelet/bubbletea/v2"
+)
+
+// maxSuggestions is the number of matching suggestions listed below the input
+const maxSuggestions = 5
+
+// AutocompleteComponent is a text input completing the values returned by source,
+// e.g. the team slugs of the organization. Tab accepts the suggestion,
+// ctrl+n and ctrl+p cycle through the matches.
+type AutocompleteComponent struct {
+	*TextInputComponent
+	source func() []string
+}
+
+// compile-time check to ensure AutocompleteComponent implements the FieldComponent interface
+var _ FieldComponent = (*AutocompleteComponent)(nil)
+
+func NewAutocompleteComponent(label string, val *string, source func() []string) *AutocompleteComponent {
+	c := &AutocompleteComponent{
+		TextInputComponent: NewTextInputComponent(label, val),
+		source:             source,
+	}
+	c.ti.ShowSuggestions = true
+	return c
+}
+
+// refresh picks up the values loaded since the last update
+func (c *AutocompleteComponent) refresh() {
+	if c.source == nil {
+		return
+	}
+	c.ti.SetSuggestions(c.source())
+}
+
+func (c *AutocompleteComponent) Update(msg tea.Msg, mode ui.FocusMode) (FieldComponent, tea.Cmd) {
+	c.refresh()
+	_, cmd := c.TextInputComponent.Update(msg, mode)
+	return c, cmd
+}
+
+func (c *AutocompleteComponent) Focus() tea.Cmd {
+	c.refresh()
+	return c.TextInputComponent.Focus()
+}
+
+// Warning returns a warning if the value isn't one of the known values.
+// Nothing is reported while the known values aren't loaded.
+func (c *AutocompleteComponent) Warning() string {
+	if c.source == nil {
+		return ""
+	}
+	known := c.source()
+	val := strings.TrimSpace(c.Value())
+	if val == "" || len(known) == 0 || slices.Contains(known, val) {
+		return ""
+	}
+	return "unknown " + strings.ToLower(c.label) + " " + val
+}
+
+func (c *AutocompleteComponent) View() string {
+	lines := []string{c.TextInputComponent.View()}
+
+	var matches []string
+	if c.focused && c.mode == ui.ModeEditing && c.Value() != "" {
+		matches = c.ti.MatchedSuggestions()
+		if len(matches) > 1 || (len(matches) == 1 && matches[0] != c.Value()) {
+			for _, s := range matches[:min(len(matches), maxSuggestions)] {
+				lines = append(lines, style.InactiveTextStyle.Render("  "+s))
+			}
+			if len(matches) > maxSuggestions {
+				lines = append(lines, style.InactiveTextStyle.Render("  ..."))
+			}
+		}
+	}
+	// don't warn while the value is still being typed
+	if w := c.Warning(); w != "" && len(matches) == 0 {
+		lines = append(lines, style.WarningMessageStyle.Render("  ⚠ "+w))
+	}
+	return ui.JoinVertical(lines)
+}
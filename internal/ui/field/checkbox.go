@@ -3,8 +3,10 @@ package field
 import (
 	"fmt"
 
+	"github.com/artemlive/gh-crossplane/internal/keymap"
 	ui "github.com/artemlive/gh-crossplane/internal/ui/shared"
 	"github.com/artemlive/gh-crossplane/internal/ui/style"
+	"github.com/charmbracelet/bubbles/v2/key"
 	tea "github.com/charmbracelet/bubbletea/v2"
 )
 
@@ -12,6 +14,9 @@ type CheckboxComponent struct {
 	label   string
 	value   *bool
 	Focused bool
+	// ref is the optional field the value belongs to, it's set
+	// when the value is allocated on the first toggle
+	ref **bool
 }
 
 func NewCheckboxComponent(label string, ptr *bool) *CheckboxComponent {
@@ -21,6 +26,16 @@ func NewCheckboxComponent(label string, ptr *bool) *CheckboxComponent {
 	}
 }
 
+// NewOptionalCheckboxComponent edits an optional field, an unset field
+// is set when the checkbox is toggled for the first time
+func NewOptionalCheckboxComponent(label string, ref **bool) *CheckboxComponent {
+	return &CheckboxComponent{
+		label: label,
+		value: *ref,
+		ref:   ref,
+	}
+}
+
 func (c *CheckboxComponent) Init() tea.Cmd {
 	return nil
 }
@@ -35,19 +50,25 @@ func (c *CheckboxComponent) Update(msg tea.Msg, mode ui.FocusMode) (FieldCompone
 	}
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "space", "enter":
+		keys := keymap.Keys
+		switch {
+		case key.Matches(msg, keys.Toggle):
 			if c.value == nil {
 				c.value = new(bool)
 				// init to true if it was nil
 				*c.value = true
+				if c.ref != nil {
+					*c.ref = c.value
+				}
 			} else {
 				*c.value = !*c.value
 			}
-		case "up", "k":
+		case key.Matches(msg, keys.Done):
+			return c, func() tea.Msg { return FieldDoneMsg{} }
+		case key.Matches(msg, keys.Up):
 			// Move focus to the previous component
 			return c, func() tea.Msg { return FieldDoneUpMsg{} }
-		case "down", "j":
+		case key.Matches(msg, keys.Down):
 			// Move focus to the next component
 			return c, func() tea.Msg { return FieldDoneDownMsg{} }
 		}
@@ -55,6 +76,11 @@ func (c *CheckboxComponent) Update(msg tea.Msg, mode ui.FocusMode) (FieldCompone
 	return c, nil
 }
 
+func (c *CheckboxComponent) KeyHelp() []key.Binding {
+	keys := keymap.Keys
+	return []key.Binding{keys.Toggle, keys.Done, keys.Up, keys.Down}
+}
+
 func (c *CheckboxComponent) View() string {
 	checked := " "
 	if c.value != nil && *c.value {
@@ -83,3 +109,8 @@ func (c *CheckboxComponent) Blur() {
 func (c *CheckboxComponent) IsFocused() bool {
 	return c.Focused
 }
+
+// Value returns the current value, nil if the checkbox was never set
+func (c *CheckboxComponent) Value() *bool {
+	return c.value
+}
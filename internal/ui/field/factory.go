@@ -4,10 +4,17 @@ import (
 	"reflect"
 	"strings"
 
+	"github.com/artemlive/gh-crossplane/internal/domain"
 	"github.com/artemlive/gh-crossplane/internal/util"
 )
 
 func GenerateComponentsByPaths(obj any, paths []string) []FieldComponent {
+	return GenerateComponents(obj, paths, nil)
+}
+
+// GenerateComponents builds the components of the fields at the paths,
+// labels replace the labels of the ui tags by path
+func GenerateComponents(obj any, paths []string, labels map[string]string) []FieldComponent {
 	root := reflect.ValueOf(obj)
 	// unwrap pointer if necessary
 	// it turns *GroupFile to GroupFile
@@ -32,11 +39,38 @@ func GenerateComponentsByPaths(obj any, paths []string) []FieldComponent {
 				structField, _ := t.FieldByName(part)
 				tag := structField.Tag.Get("ui")
 				meta := util.ParseTag(tag)
+				if label, ok := labels[path]; ok {
+					meta["label"] = label
+				}
 
 				switch meta["type"] {
 				case "checkbox":
-					if fieldVal.Kind() == reflect.Ptr && fieldVal.Type().Elem().Kind() == reflect.Bool {
-						components = append(components, NewCheckboxComponent(meta["label"], fieldVal.Interface().(*bool)))
+					if ref, ok := fieldVal.Addr().Interface().(**bool); ok {
+						components = append(components, NewOptionalCheckboxComponent(meta["label"], ref))
+					}
+				case "permissions":
+					if perms, ok := fieldVal.Addr().Interface().(*[]domain.Permission); ok {
+						components = append(components, NewPermissionsComponent(meta["label"], perms))
+					}
+				case "protections":
+					if protections, ok := fieldVal.Addr().Interface().(*[]domain.Protection); ok {
+						components = append(components, NewProtectionsComponent(meta["label"], protections))
+					}
+				case "labels":
+					if values, ok := fieldVal.Addr().Interface().(*map[string]string); ok {
+						components = append(components, NewLabelsComponent(meta["label"], values))
+					}
+				case "annotations":
+					if values, ok := fieldVal.Addr().Interface().(*map[string]string); ok {
+						components = append(components, NewAnnotationsComponent(meta["label"], values))
+					}
+				case "strings":
+					if values, ok := fieldVal.Addr().Interface().(*[]string); ok {
+						components = append(components, NewStringListComponent(meta["label"], values))
+					}
+				case "security":
+					if value, ok := fieldVal.Addr().Interface().(*[]domain.SecAnalysis); ok {
+						components = append(components, NewSecurityComponent(meta["label"], value))
 					}
 				case "text":
 					if fieldVal.Kind() == reflect.Ptr && fieldVal.Type().Elem().Kind() == reflect.String {
@@ -0,0 +1,263 @@
+package field
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/artemlive/gh-crossplane/internal/domain"
+	"github.com/artemlive/gh-crossplane/internal/keymap"
+	ui "github.com/artemlive/gh-crossplane/internal/ui/shared"
+	"github.com/artemlive/gh-crossplane/internal/ui/style"
+	"github.com/charmbracelet/bubbles/v2/key"
+	tea "github.com/charmbracelet/bubbletea/v2"
+)
+
+// KeyValueComponent is a map of keys and values, such as the labels of a group.
+// While editing: up/down select, a adds an entry, enter edits it as key=value,
+// d deletes it. An entry is only stored once its key and value are valid.
+type KeyValueComponent struct {
+	label    string
+	values   *map[string]string
+	validate func(key, value string) error
+	index    int
+	focused  bool
+	mode     ui.FocusMode
+
+	// input edits the selected entry, key is the one it had, empty for a new entry
+	input *TextInputComponent
+	key   string
+	err   string
+}
+
+// compile-time check to ensure KeyValueComponent implements the FieldComponent interface
+var _ FieldComponent = (*KeyValueComponent)(nil)
+
+// NewKeyValueComponent edits the map, validate checks the entries before they are stored
+func NewKeyValueComponent(label string, values *map[string]string, validate func(key, value string) error) *KeyValueComponent {
+	return &KeyValueComponent{
+		label:    label,
+		values:   values,
+		validate: validate,
+	}
+}
+
+// NewLabelsComponent edits Kubernetes labels
+func NewLabelsComponent(label string, values *map[string]string) *KeyValueComponent {
+	return NewKeyValueComponent(label, values, func(key, value string) error {
+		if err := domain.ValidateMetadataKey(key); err != nil {
+			return err
+		}
+		return domain.ValidateLabelValue(value)
+	})
+}
+
+// NewAnnotationsComponent edits Kubernetes annotations, only their keys are restricted
+func NewAnnotationsComponent(label string, values *map[string]string) *KeyValueComponent {
+	return NewKeyValueComponent(label, values, func(key, _ string) error {
+		return domain.ValidateMetadataKey(key)
+	})
+}
+
+// keys are the keys of the map in a stable order
+func (c *KeyValueComponent) keys() []string {
+	return slices.Sorted(maps.Keys(*c.values))
+}
+
+func (c *KeyValueComponent) View() string {
+	lines := []string{style.LabelStyle.Render(c.label + ":")}
+
+	keys := c.keys()
+	if len(keys) == 0 && c.input == nil {
+		lines = append(lines, style.InactiveTextStyle.Render("None"))
+	}
+	for i, k := range keys {
+		if c.input != nil && c.key == k {
+			lines = append(lines, "  "+c.input.View())
+			continue
+		}
+		line := k + "=" + (*c.values)[k]
+		if c.focused && c.index == i && c.input == nil {
+			lines = append(lines, style.FocusedTextStyle.Render(style.FocusedPrefix+" "+line))
+		} else {
+			lines = append(lines, "  "+line)
+		}
+	}
+	if c.input != nil && c.key == "" {
+		lines = append(lines, "  "+c.input.View())
+	}
+	if c.err != "" {
+		lines = append(lines, style.ErrorMessageStyle.Render(c.err))
+	}
+	if c.focused && c.mode == ui.ModeEditing && c.input == nil {
+		lines = append(lines, style.InactiveTextStyle.Render(keymap.Hints(c.actions()...)))
+	}
+
+	return style.FieldBlockStyle.Render(ui.JoinVertical(lines))
+}
+
+func (c *KeyValueComponent) Update(msg tea.Msg, mode ui.FocusMode) (FieldComponent, tea.Cmd) {
+	c.mode = mode
+	if mode != ui.ModeEditing {
+		c.closeInput()
+		return c, nil
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	km := keymap.Keys
+	if c.input != nil {
+		if ok && key.Matches(keyMsg, km.Done, km.FieldUp, km.FieldDown) {
+			c.store()
+			return c, nil
+		}
+		c.err = ""
+		_, cmd := c.input.Update(msg, mode)
+		return c, cmd
+	}
+	if !ok {
+		return c, nil
+	}
+
+	keys := c.keys()
+	switch {
+	case key.Matches(keyMsg, km.Up):
+		if c.index > 0 {
+			c.index--
+		}
+	case key.Matches(keyMsg, km.Down):
+		if c.index < len(keys)-1 {
+			c.index++
+		}
+	case key.Matches(keyMsg, km.Add):
+		return c, c.openInput("")
+	case key.Matches(keyMsg, km.Select):
+		if c.index < len(keys) {
+			return c, c.openInput(keys[c.index])
+		}
+	case key.Matches(keyMsg, km.Delete):
+		if c.index < len(keys) {
+			c.set(keys[c.index], "", "")
+			c.index = max(0, min(c.index, len(keys)-2))
+		}
+	}
+	return c, nil
+}
+
+// actions are the keys changing the entries
+func (c *KeyValueComponent) actions() []key.Binding {
+	km := keymap.Keys
+	return []key.Binding{keymap.Describe(km.Add, "add"), keymap.Describe(km.Select, "edit"), keymap.Describe(km.Delete, "delete")}
+}
+
+func (c *KeyValueComponent) KeyHelp() []key.Binding {
+	if c.input != nil {
+		return nil
+	}
+	return append([]key.Binding{keymap.Keys.Up, keymap.Keys.Down}, c.actions()...)
+}
+
+// openInput starts editing the entry with the key, a new one if it's empty
+func (c *KeyValueComponent) openInput(key string) tea.Cmd {
+	c.key = key
+	c.err = ""
+	c.input = NewTextInputComponent("key=value", nil)
+	c.input.ti.SetWidth(60)
+	if key != "" {
+		c.input.SetValue(key + "=" + (*c.values)[key])
+	}
+	return c.input.Focus()
+}
+
+// store validates the entry being edited and writes it to the map.
+// An empty entry is dropped, an invalid one stays in the input.
+func (c *KeyValueComponent) store() {
+	text := strings.TrimSpace(c.input.Value())
+	if text == "" {
+		c.closeInput()
+		return
+	}
+	key, value, _ := strings.Cut(text, "=")
+	key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+	if err := c.validate(key, value); err != nil {
+		c.err = err.Error()
+		return
+	}
+	if _, exists := (*c.values)[key]; exists && key != c.key {
+		c.err = "key " + key + " is already set"
+		return
+	}
+	c.set(c.key, key, value)
+	c.index = slices.Index(c.keys(), key)
+	c.input = nil
+}
+
+// set replaces the entry at the old key, which is deleted if the new key is empty.
+// The map is copied first, it's shared with the loaded group.
+func (c *KeyValueComponent) set(old, key, value string) {
+	values := maps.Clone(*c.values)
+	if values == nil {
+		values = make(map[string]string)
+	}
+	if old != "" {
+		delete(values, old)
+	}
+	if key != "" {
+		values[key] = value
+	}
+	if len(values) == 0 {
+		values = nil
+	}
+	*c.values = values
+}
+
+// closeInput discards the entry being edited
+func (c *KeyValueComponent) closeInput() {
+	c.input = nil
+	c.err = ""
+}
+
+func (c *KeyValueComponent) Cursor() *tea.Cursor {
+	if c.input == nil {
+		return nil
+	}
+	cur := c.input.Cursor()
+	if cur == nil {
+		return nil
+	}
+	row := len(c.keys())
+	if c.key != "" {
+		row = slices.Index(c.keys(), c.key)
+	}
+	// the label line and the entries above
+	return tea.NewCursor(cur.X, cur.Y+1+row)
+}
+
+func (c *KeyValueComponent) Focus() tea.Cmd {
+	c.focused = true
+	return nil
+}
+
+func (c *KeyValueComponent) Blur() {
+	c.focused = false
+	c.closeInput()
+}
+
+func (c *KeyValueComponent) IsFocused() bool {
+	return c.focused
+}
+
+func (c *KeyValueComponent) Init() tea.Cmd {
+	return nil
+}
+
+func (c *KeyValueComponent) Label() string {
+	return c.label
+}
+
+func (c *KeyValueComponent) CursorOffset() int {
+	if c.input == nil {
+		return 0
+	}
+	// the block padding, the indent and the input label
+	return 1 + 2 + c.input.CursorOffset()
+}
@@ -0,0 +1,116 @@
+package field
+
+import (
+	"strings"
+
+	"github.com/artemlive/gh-crossplane/internal/keymap"
+	"github.com/artemlive/gh-crossplane/internal/presets"
+	ui "github.com/artemlive/gh-crossplane/internal/ui/shared"
+	"github.com/artemlive/gh-crossplane/internal/ui/style"
+	"github.com/charmbracelet/bubbles/v2/key"
+	tea "github.com/charmbracelet/bubbletea/v2"
+)
+
+// ListComponent picks one of a few options, e.g. the preset of a new group.
+// Up/down move the selection, enter finishes with FieldDoneMsg.
+type ListComponent struct {
+	label   string
+	options []string
+	details []string // shown beside the options, may be shorter than options
+	index   int
+	focused bool
+}
+
+// compile-time check to ensure ListComponent implements the FieldComponent interface
+var _ FieldComponent = (*ListComponent)(nil)
+
+func NewListComponent(label string, options, details []string) *ListComponent {
+	return &ListComponent{
+		label:   label,
+		options: options,
+		details: details,
+	}
+}
+
+// NewPresetListComponent lists the presets after a "None" option,
+// the preset of the selected index i is presets[i-1]
+func NewPresetListComponent(list []presets.Preset) *ListComponent {
+	options, details := []string{"None"}, []string{"start with empty settings"}
+	for _, p := range list {
+		options = append(options, p.Name)
+		details = append(details, p.Description)
+	}
+	return NewListComponent("Preset", options, details)
+}
+
+// Selected returns the index of the selected option
+func (c *ListComponent) Selected() int {
+	return c.index
+}
+
+func (c *ListComponent) View() string {
+	lines := []string{style.LabelStyle.Render(c.label + ":")}
+	for i, o := range c.options {
+		line := o
+		if i < len(c.details) && c.details[i] != "" {
+			line += style.InactiveTextStyle.Render(" - " + c.details[i])
+		}
+		if i == c.index {
+			lines = append(lines, style.FocusedTextStyle.Render(style.FocusedPrefix+" ")+line)
+		} else {
+			lines = append(lines, "  "+line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (c *ListComponent) Update(msg tea.Msg, mode ui.FocusMode) (FieldComponent, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok || mode != ui.ModeEditing {
+		return c, nil
+	}
+	keys := keymap.Keys
+	switch {
+	case key.Matches(keyMsg, keys.Up):
+		if c.index > 0 {
+			c.index--
+		}
+	case key.Matches(keyMsg, keys.Down):
+		if c.index < len(c.options)-1 {
+			c.index++
+		}
+	case key.Matches(keyMsg, keys.Done):
+		return c, func() tea.Msg { return FieldDoneMsg{} }
+	}
+	return c, nil
+}
+
+func (c *ListComponent) KeyHelp() []key.Binding {
+	keys := keymap.Keys
+	return []key.Binding{keys.Up, keys.Down, keys.Done}
+}
+
+func (c *ListComponent) Focus() tea.Cmd {
+	c.focused = true
+	return nil
+}
+
+func (c *ListComponent) Blur() {
+	c.focused = false
+}
+
+func (c *ListComponent) IsFocused() bool {
+	return c.focused
+}
+
+func (c *ListComponent) Init() tea.Cmd {
+	return nil
+}
+
+func (c *ListComponent) Label() string {
+	return c.label
+}
+
+func (c *ListComponent) CursorOffset() int {
+	return 0
+}
@@ -0,0 +1,259 @@
+package field
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/artemlive/gh-crossplane/internal/domain"
+	"github.com/artemlive/gh-crossplane/internal/keymap"
+	ui "github.com/artemlive/gh-crossplane/internal/ui/shared"
+	"github.com/artemlive/gh-crossplane/internal/ui/style"
+	"github.com/charmbracelet/bubbles/v2/key"
+	tea "github.com/charmbracelet/bubbletea/v2"
+)
+
+// PermissionLevels are the repository roles, from the lowest to the highest
+var PermissionLevels = []string{"pull", "triage", "push", "maintain", "admin"}
+
+// Lookup provides the known teams and members of the organization
+type Lookup interface {
+	Teams() []string
+	Members() []string
+}
+
+// PermissionsComponent is a list of team and collaborator permissions.
+// While editing: up/down select, t/c add a team/collaborator, enter edits the
+// name with autocompletion, p cycles the permission, d deletes.
+type PermissionsComponent struct {
+	label   string
+	perms   *[]domain.Permission
+	lookup  Lookup
+	index   int
+	focused bool
+	mode    ui.FocusMode
+
+	// input edits the team or the collaborator of the selected permission
+	input *AutocompleteComponent
+}
+
+// compile-time check to ensure PermissionsComponent implements the FieldComponent interface
+var _ FieldComponent = (*PermissionsComponent)(nil)
+
+func NewPermissionsComponent(label string, perms *[]domain.Permission) *PermissionsComponent {
+	return &PermissionsComponent{
+		label: label,
+		perms: perms,
+	}
+}
+
+// SetLookup sets the source of the completions, nil disables them
+func (c *PermissionsComponent) SetLookup(lookup Lookup) {
+	c.lookup = lookup
+}
+
+func (c *PermissionsComponent) teams() []string {
+	if c.lookup == nil {
+		return nil
+	}
+	return c.lookup.Teams()
+}
+
+func (c *PermissionsComponent) members() []string {
+	if c.lookup == nil {
+		return nil
+	}
+	return c.lookup.Members()
+}
+
+func (c *PermissionsComponent) View() string {
+	lines := []string{style.LabelStyle.Render(c.label + ":")}
+
+	if len(*c.perms) == 0 {
+		lines = append(lines, style.InactiveTextStyle.Render("No permissions"))
+	}
+	for i, p := range *c.perms {
+		if c.input != nil && i == c.index {
+			lines = append(lines, "  "+c.input.View())
+			continue
+		}
+		line := fmt.Sprintf("%s: %s", c.subject(p), p.Permission)
+		if w := c.warning(p); w != "" {
+			line += " " + style.WarningMessageStyle.Render("⚠ "+w)
+		}
+		if c.focused && c.index == i {
+			lines = append(lines, style.FocusedTextStyle.Render(style.FocusedPrefix+" "+line))
+		} else {
+			lines = append(lines, "  "+line)
+		}
+	}
+	if c.focused && c.mode == ui.ModeEditing && c.input == nil {
+		lines = append(lines, style.InactiveTextStyle.Render(keymap.Hints(c.actions()...)))
+	}
+
+	return style.FieldBlockStyle.Render(ui.JoinVertical(lines))
+}
+
+func (c *PermissionsComponent) subject(p domain.Permission) string {
+	if p.Team != "" {
+		return "team " + p.Team
+	}
+	return "collaborator " + p.Collaborator
+}
+
+// warning flags the teams and users which aren't in the organization
+func (c *PermissionsComponent) warning(p domain.Permission) string {
+	switch {
+	case p.Team != "":
+		if teams := c.teams(); len(teams) > 0 && !slices.Contains(teams, p.Team) {
+			return "unknown team"
+		}
+	case p.Collaborator != "":
+		if members := c.members(); len(members) > 0 && !slices.Contains(members, p.Collaborator) {
+			return "not an org member"
+		}
+	}
+	return ""
+}
+
+func (c *PermissionsComponent) Update(msg tea.Msg, mode ui.FocusMode) (FieldComponent, tea.Cmd) {
+	c.mode = mode
+	if mode != ui.ModeEditing {
+		c.closeInput()
+		return c, nil
+	}
+
+	km := keymap.Keys
+	if c.input != nil {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok && key.Matches(keyMsg, km.Done, km.FieldUp, km.FieldDown) {
+			c.closeInput()
+			return c, nil
+		}
+		_, cmd := c.input.Update(msg, mode)
+		return c, cmd
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return c, nil
+	}
+	switch {
+	case key.Matches(keyMsg, km.Up):
+		if c.index > 0 {
+			c.index--
+		}
+	case key.Matches(keyMsg, km.Down):
+		if c.index < len(*c.perms)-1 {
+			c.index++
+		}
+	case key.Matches(keyMsg, km.AddTeam):
+		*c.perms = append(*c.perms, domain.Permission{Permission: "pull"})
+		c.index = len(*c.perms) - 1
+		return c, c.openInput(true)
+	case key.Matches(keyMsg, km.AddCollaborator):
+		*c.perms = append(*c.perms, domain.Permission{Permission: "pull"})
+		c.index = len(*c.perms) - 1
+		return c, c.openInput(false)
+	case key.Matches(keyMsg, km.Select):
+		if c.index < len(*c.perms) {
+			return c, c.openInput((*c.perms)[c.index].Collaborator == "")
+		}
+	case key.Matches(keyMsg, km.Permission):
+		if c.index < len(*c.perms) {
+			p := &(*c.perms)[c.index]
+			next := (slices.Index(PermissionLevels, p.Permission) + 1) % len(PermissionLevels)
+			p.Permission = PermissionLevels[next]
+		}
+	case key.Matches(keyMsg, km.Delete):
+		if c.index < len(*c.perms) {
+			*c.perms = slices.Delete(*c.perms, c.index, c.index+1)
+			c.index = max(0, min(c.index, len(*c.perms)-1))
+		}
+	}
+	return c, nil
+}
+
+// actions are the keys changing the permissions
+func (c *PermissionsComponent) actions() []key.Binding {
+	km := keymap.Keys
+	return []key.Binding{
+		keymap.Describe(km.AddTeam, "add team"),
+		keymap.Describe(km.AddCollaborator, "add collaborator"),
+		keymap.Describe(km.Select, "rename"),
+		keymap.Describe(km.Permission, "change permission"),
+		keymap.Describe(km.Delete, "delete"),
+	}
+}
+
+func (c *PermissionsComponent) KeyHelp() []key.Binding {
+	if c.input != nil {
+		return nil
+	}
+	return append([]key.Binding{keymap.Keys.Up, keymap.Keys.Down}, c.actions()...)
+}
+
+// openInput starts editing the team or the collaborator of the selected permission
+func (c *PermissionsComponent) openInput(team bool) tea.Cmd {
+	p := &(*c.perms)[c.index]
+	if team {
+		p.Collaborator = ""
+		c.input = NewAutocompleteComponent("Team", &p.Team, c.teams)
+	} else {
+		p.Team = ""
+		c.input = NewAutocompleteComponent("Collaborator", &p.Collaborator, c.members)
+	}
+	return c.input.Focus()
+}
+
+// closeInput finishes editing, dropping the permission if no name was entered
+func (c *PermissionsComponent) closeInput() {
+	if c.input == nil {
+		return
+	}
+	c.input = nil
+	if p := (*c.perms)[c.index]; p.Team == "" && p.Collaborator == "" {
+		*c.perms = slices.Delete(*c.perms, c.index, c.index+1)
+		c.index = max(0, min(c.index, len(*c.perms)-1))
+	}
+}
+
+func (c *PermissionsComponent) Cursor() *tea.Cursor {
+	if c.input == nil {
+		return nil
+	}
+	cur := c.input.Cursor()
+	if cur == nil {
+		return nil
+	}
+	// the label line and the items above
+	return tea.NewCursor(cur.X, cur.Y+1+c.index)
+}
+
+func (c *PermissionsComponent) Focus() tea.Cmd {
+	c.focused = true
+	return nil
+}
+
+func (c *PermissionsComponent) Blur() {
+	c.focused = false
+	c.closeInput()
+}
+
+func (c *PermissionsComponent) IsFocused() bool {
+	return c.focused
+}
+
+func (c *PermissionsComponent) Init() tea.Cmd {
+	return nil
+}
+
+func (c *PermissionsComponent) Label() string {
+	return c.label
+}
+
+func (c *PermissionsComponent) CursorOffset() int {
+	if c.input == nil {
+		return 0
+	}
+	// the block padding, the indent and the input label
+	return 1 + 2 + c.input.CursorOffset()
+}
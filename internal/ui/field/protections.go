@@ -0,0 +1,307 @@
+package field
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/artemlive/gh-crossplane/internal/domain"
+	"github.com/artemlive/gh-crossplane/internal/keymap"
+	ui "github.com/artemlive/gh-crossplane/internal/ui/shared"
+	"github.com/artemlive/gh-crossplane/internal/ui/style"
+	"github.com/charmbracelet/bubbles/v2/key"
+	tea "github.com/charmbracelet/bubbletea/v2"
+)
+
+// ProtectionsComponent is a list of branch protections.
+// While editing: up/down select, a adds a protection, d deletes it,
+// e/s/r toggle enforce admins/signed commits/conversation resolution,
+// c adds a required status check with suggestions, x removes the last one,
+// t toggles strict status checks.
+type ProtectionsComponent struct {
+	label       string
+	protections *[]domain.Protection
+	contexts    func() []string // suggested status check contexts
+	index       int
+	focused     bool
+	mode        ui.FocusMode
+
+	// input edits the pattern of a new protection or a new status check context
+	input      *AutocompleteComponent
+	inputValue string
+	addingItem bool // the input is the pattern of a new protection
+}
+
+// compile-time check to ensure ProtectionsComponent implements the FieldComponent interface
+var _ FieldComponent = (*ProtectionsComponent)(nil)
+
+func NewProtectionsComponent(label string, protections *[]domain.Protection) *ProtectionsComponent {
+	return &ProtectionsComponent{
+		label:       label,
+		protections: protections,
+	}
+}
+
+// SetContextSource sets where the status check suggestions come from
+func (c *ProtectionsComponent) SetContextSource(contexts func() []string) {
+	c.contexts = contexts
+}
+
+func (c *ProtectionsComponent) View() string {
+	lines := []string{style.LabelStyle.Render(c.label + ":")}
+
+	if len(*c.protections) == 0 && !c.addingItem {
+		lines = append(lines, style.InactiveTextStyle.Render("No protections"))
+	}
+	for i, p := range *c.protections {
+		line := p.Name
+		if p.Pattern != p.Name {
+			line += fmt.Sprintf(" (%s)", p.Pattern)
+		}
+		if flags := protectionFlags(p); len(flags) > 0 {
+			line += ": " + strings.Join(flags, ", ")
+		}
+		if c.focused && c.index == i {
+			lines = append(lines, style.FocusedTextStyle.Render(style.FocusedPrefix+" "+line))
+		} else {
+			lines = append(lines, "  "+line)
+		}
+		if checks := statusCheckLine(p); checks != "" {
+			lines = append(lines, "    "+checks)
+		}
+		if c.input != nil && !c.addingItem && i == c.index {
+			lines = append(lines, "    "+c.input.View())
+		}
+	}
+	if c.input != nil && c.addingItem {
+		lines = append(lines, "  "+c.input.View())
+	}
+	if c.focused && c.mode == ui.ModeEditing && c.input == nil {
+		lines = append(lines, style.InactiveTextStyle.Render(keymap.Hints(c.actions()...)))
+	}
+
+	return style.FieldBlockStyle.Render(ui.JoinVertical(lines))
+}
+
+func protectionFlags(p domain.Protection) []string {
+	var flags []string
+	if p.EnforceAdmins {
+		flags = append(flags, "enforce admins")
+	}
+	if p.RequireSignedCommits {
+		flags = append(flags, "signed commits")
+	}
+	if p.RequireConversationResolution {
+		flags = append(flags, "conversation resolution")
+	}
+	for _, r := range p.RequiredPullRequestReviews {
+		flags = append(flags, fmt.Sprintf("%d approvals", r.RequiredApprovingReviewCount))
+	}
+	return flags
+}
+
+func statusCheckLine(p domain.Protection) string {
+	if len(p.RequiredStatusChecks) == 0 {
+		return ""
+	}
+	sc := p.RequiredStatusChecks[0]
+	label := "checks"
+	if sc.Strict {
+		label += " (strict)"
+	}
+	if len(sc.Contexts) == 0 {
+		return style.InactiveTextStyle.Render(label + ": none")
+	}
+	return style.InactiveTextStyle.Render(label + ": " + strings.Join(sc.Contexts, ", "))
+}
+
+func (c *ProtectionsComponent) Update(msg tea.Msg, mode ui.FocusMode) (FieldComponent, tea.Cmd) {
+	c.mode = mode
+	if mode != ui.ModeEditing {
+		c.closeInput(false)
+		return c, nil
+	}
+
+	km := keymap.Keys
+	if c.input != nil {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok && key.Matches(keyMsg, km.Done) {
+			c.closeInput(true)
+			return c, nil
+		}
+		_, cmd := c.input.Update(msg, mode)
+		return c, cmd
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return c, nil
+	}
+	if key.Matches(keyMsg, km.Add) {
+		c.addingItem = true
+		return c, c.openInput("Pattern", nil)
+	}
+	if c.index >= len(*c.protections) {
+		return c, nil
+	}
+
+	p := &(*c.protections)[c.index]
+	switch {
+	case key.Matches(keyMsg, km.Up):
+		if c.index > 0 {
+			c.index--
+		}
+	case key.Matches(keyMsg, km.Down):
+		if c.index < len(*c.protections)-1 {
+			c.index++
+		}
+	case key.Matches(keyMsg, km.Delete):
+		*c.protections = slices.Delete(*c.protections, c.index, c.index+1)
+		c.index = max(0, min(c.index, len(*c.protections)-1))
+	case key.Matches(keyMsg, km.EnforceAdmins):
+		p.EnforceAdmins = !p.EnforceAdmins
+	case key.Matches(keyMsg, km.SignedCommits):
+		p.RequireSignedCommits = !p.RequireSignedCommits
+	case key.Matches(keyMsg, km.ConversationResolution):
+		p.RequireConversationResolution = !p.RequireConversationResolution
+	case key.Matches(keyMsg, km.StrictChecks):
+		sc := statusCheck(p)
+		sc.Strict = !sc.Strict
+	case key.Matches(keyMsg, km.AddCheck):
+		return c, c.openInput("Check", c.contexts)
+	case key.Matches(keyMsg, km.RemoveCheck):
+		if len(p.RequiredStatusChecks) > 0 {
+			sc := &p.RequiredStatusChecks[0]
+			if n := len(sc.Contexts); n > 0 {
+				sc.Contexts = sc.Contexts[:n-1]
+			}
+		}
+	}
+	return c, nil
+}
+
+// actions are the keys changing the protections
+func (c *ProtectionsComponent) actions() []key.Binding {
+	km := keymap.Keys
+	return []key.Binding{
+		keymap.Describe(km.Add, "add"),
+		keymap.Describe(km.Delete, "delete"),
+		keymap.Describe(km.EnforceAdmins, "admins"),
+		keymap.Describe(km.SignedCommits, "signed"),
+		keymap.Describe(km.ConversationResolution, "conversations"),
+		keymap.Describe(km.AddCheck, "add check"),
+		keymap.Describe(km.RemoveCheck, "remove check"),
+		keymap.Describe(km.StrictChecks, "strict"),
+	}
+}
+
+func (c *ProtectionsComponent) KeyHelp() []key.Binding {
+	if c.input != nil {
+		return nil
+	}
+	km := keymap.Keys
+	return []key.Binding{
+		km.Up, km.Down,
+		keymap.Describe(km.Add, "add a protection"),
+		keymap.Describe(km.Delete, "delete the protection"),
+		km.EnforceAdmins, km.SignedCommits, km.ConversationResolution,
+		km.AddCheck, km.RemoveCheck, km.StrictChecks,
+	}
+}
+
+// statusCheck returns the status check settings of the protection, adding them if missing
+func statusCheck(p *domain.Protection) *domain.StatusCheck {
+	if len(p.RequiredStatusChecks) == 0 {
+		p.RequiredStatusChecks = []domain.StatusCheck{{}}
+	}
+	return &p.RequiredStatusChecks[0]
+}
+
+func (c *ProtectionsComponent) openInput(label string, source func() []string) tea.Cmd {
+	c.inputValue = ""
+	c.input = NewAutocompleteComponent(label, &c.inputValue, source)
+	return c.input.Focus()
+}
+
+// closeInput finishes editing, applying the entered value if apply is set
+func (c *ProtectionsComponent) closeInput(apply bool) {
+	if c.input == nil {
+		return
+	}
+	val := strings.TrimSpace(c.inputValue)
+	adding := c.addingItem
+	c.input = nil
+	c.addingItem = false
+	if !apply || val == "" {
+		return
+	}
+
+	if adding {
+		*c.protections = append(*c.protections, domain.Protection{Name: val, Pattern: val})
+		c.index = len(*c.protections) - 1
+		return
+	}
+	sc := statusCheck(&(*c.protections)[c.index])
+	if !slices.Contains(sc.Contexts, val) {
+		sc.Contexts = append(sc.Contexts, val)
+	}
+}
+
+func (c *ProtectionsComponent) Cursor() *tea.Cursor {
+	if c.input == nil {
+		return nil
+	}
+	cur := c.input.Cursor()
+	if cur == nil {
+		return nil
+	}
+	return tea.NewCursor(cur.X, cur.Y+c.inputLine())
+}
+
+// inputLine is the line of the input within the view
+func (c *ProtectionsComponent) inputLine() int {
+	line := 1 // label
+	for i, p := range *c.protections {
+		line++
+		if statusCheckLine(p) != "" {
+			line++
+		}
+		if !c.addingItem && i == c.index {
+			return line
+		}
+	}
+	return line
+}
+
+func (c *ProtectionsComponent) Focus() tea.Cmd {
+	c.focused = true
+	return nil
+}
+
+func (c *ProtectionsComponent) Blur() {
+	c.focused = false
+	c.closeInput(false)
+}
+
+func (c *ProtectionsComponent) IsFocused() bool {
+	return c.focused
+}
+
+func (c *ProtectionsComponent) Init() tea.Cmd {
+	return nil
+}
+
+func (c *ProtectionsComponent) Label() string {
+	return c.label
+}
+
+func (c *ProtectionsComponent) CursorOffset() int {
+	if c.input == nil {
+		return 0
+	}
+	indent := 4
+	if c.addingItem {
+		indent = 2
+	}
+	// the block padding, the indent and the input label
+	return 1 + indent + c.input.CursorOffset()
+}
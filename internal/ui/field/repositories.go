@@ -4,9 +4,11 @@ import (
 	"fmt"
 
 	"github.com/artemlive/gh-crossplane/internal/domain"
+	"github.com/artemlive/gh-crossplane/internal/keymap"
 	ui "github.com/artemlive/gh-crossplane/internal/ui/shared"
 	"github.com/artemlive/gh-crossplane/internal/ui/style"
 	"github.com/artemlive/gh-crossplane/internal/util"
+	"github.com/charmbracelet/bubbles/v2/key"
 	tea "github.com/charmbracelet/bubbletea/v2"
 	"github.com/charmbracelet/lipgloss/v2"
 )
@@ -52,16 +54,17 @@ func (c *RepositoriesComponent) View() string {
 func (c *RepositoriesComponent) Update(msg tea.Msg, mode ui.FocusMode) (FieldComponent, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "up", "k":
+		keys := keymap.Keys
+		switch {
+		case key.Matches(msg, keys.Up):
 			if c.index > 0 {
 				c.index--
 			}
-		case "down", "j":
+		case key.Matches(msg, keys.Down):
 			if c.index < len(*c.repos)-1 {
 				c.index++
 			}
-		case "enter":
+		case key.Matches(msg, keys.Select) && len(*c.repos) > 0:
 			return c, func() tea.Msg {
 				return FieldOpenMsg{
 					Value: &(*c.repos)[c.index],
@@ -74,6 +77,11 @@ func (c *RepositoriesComponent) Update(msg tea.Msg, mode ui.FocusMode) (FieldCom
 	return c, nil
 }
 
+func (c *RepositoriesComponent) KeyHelp() []key.Binding {
+	keys := keymap.Keys
+	return []key.Binding{keys.Up, keys.Down, keymap.Describe(keys.Select, "edit the repository")}
+}
+
 func (c *RepositoriesComponent) Focus() tea.Cmd {
 	c.focused = true
 	return nil
@@ -102,6 +110,33 @@ func (c *RepositoriesComponent) CursorOffset() int {
 	return lipgloss.Width((*c.repos)[c.index].Name) + 2 // +2 for the prefix and space
 }
 
+// FocusLine returns the line of the focused repository, below the label
+func (c *RepositoriesComponent) FocusLine() int {
+	if len(*c.repos) == 0 {
+		return 0
+	}
+	return 1 + c.index
+}
+
+// Selected returns the focused repository, nil if there are none
+func (c *RepositoriesComponent) Selected() *domain.Repository {
+	if len(*c.repos) == 0 || c.index >= len(*c.repos) {
+		return nil
+	}
+	return &(*c.repos)[c.index]
+}
+
+// Select moves the focus to the repository with the name, it reports whether it was found
+func (c *RepositoriesComponent) Select(name string) bool {
+	for i, r := range *c.repos {
+		if r.Name == name {
+			c.index = i
+			return true
+		}
+	}
+	return false
+}
+
 func (c *RepositoriesComponent) PreviewLines() []string {
 	if len(*c.repos) == 0 || c.index >= len(*c.repos) {
 		return nil
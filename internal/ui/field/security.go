@@ -0,0 +1,171 @@
+package field
+
+import (
+	"fmt"
+
+	"github.com/artemlive/gh-crossplane/internal/domain"
+	"github.com/artemlive/gh-crossplane/internal/keymap"
+	ui "github.com/artemlive/gh-crossplane/internal/ui/shared"
+	"github.com/artemlive/gh-crossplane/internal/ui/style"
+	"github.com/charmbracelet/bubbles/v2/key"
+	tea "github.com/charmbracelet/bubbletea/v2"
+)
+
+// securityFeatures are the settings of the security and analysis block
+var securityFeatures = []struct {
+	label string
+	get   func(*domain.SecAnalysis) *[]domain.Status
+}{
+	{"Advanced Security", func(s *domain.SecAnalysis) *[]domain.Status { return &s.AdvancedSecurity }},
+	{"Secret Scanning", func(s *domain.SecAnalysis) *[]domain.Status { return &s.SecretScanning }},
+	{"Secret Scanning Push Protection", func(s *domain.SecAnalysis) *[]domain.Status { return &s.SecretScanningPushProtection }},
+}
+
+// securityStatuses are the values a feature cycles through, the first one is unset
+var securityStatuses = []string{"", "enabled", "disabled"}
+
+// SecurityComponent edits the security and analysis settings, which the provider
+// expects as a single item list of single item lists. While editing: up/down
+// select a feature, space or left/right cycle between unset, enabled and disabled.
+type SecurityComponent struct {
+	label   string
+	value   *[]domain.SecAnalysis
+	index   int
+	focused bool
+	mode    ui.FocusMode
+}
+
+// compile-time check to ensure SecurityComponent implements the FieldComponent interface
+var _ FieldComponent = (*SecurityComponent)(nil)
+
+func NewSecurityComponent(label string, value *[]domain.SecAnalysis) *SecurityComponent {
+	return &SecurityComponent{
+		label: label,
+		value: value,
+	}
+}
+
+// status returns the status of the feature, empty if unset
+func (c *SecurityComponent) status(i int) string {
+	if len(*c.value) == 0 {
+		return ""
+	}
+	statuses := *securityFeatures[i].get(&(*c.value)[0])
+	if len(statuses) == 0 {
+		return ""
+	}
+	return statuses[0].Status
+}
+
+// setStatus replaces the block with one having the status of the feature set,
+// the old block may be shared with the loaded group
+func (c *SecurityComponent) setStatus(i int, status string) {
+	var sa domain.SecAnalysis
+	if len(*c.value) > 0 {
+		sa = (*c.value)[0]
+	}
+	var statuses []domain.Status
+	if status != "" {
+		statuses = []domain.Status{{Status: status}}
+	}
+	*securityFeatures[i].get(&sa) = statuses
+
+	if sa.AdvancedSecurity == nil && sa.SecretScanning == nil && sa.SecretScanningPushProtection == nil {
+		*c.value = nil
+		return
+	}
+	*c.value = []domain.SecAnalysis{sa}
+}
+
+func (c *SecurityComponent) cycle(delta int) {
+	n := len(securityStatuses)
+	idx := 0
+	for i, s := range securityStatuses {
+		if s == c.status(c.index) {
+			idx = i
+		}
+	}
+	c.setStatus(c.index, securityStatuses[(idx+delta+n)%n])
+}
+
+func (c *SecurityComponent) View() string {
+	lines := []string{style.LabelStyle.Render(c.label + ":")}
+	for i, f := range securityFeatures {
+		val := c.status(i)
+		if val == "" {
+			val = style.InactiveTextStyle.Render("<unset>")
+		}
+		line := fmt.Sprintf("%s: %s", f.label, val)
+		if c.focused && c.mode == ui.ModeEditing && c.index == i {
+			lines = append(lines, style.FocusedTextStyle.Render(style.FocusedPrefix+" "+line))
+		} else {
+			lines = append(lines, "  "+line)
+		}
+	}
+	if c.focused && c.mode == ui.ModeEditing {
+		lines = append(lines, style.InactiveTextStyle.Render(keymap.Hints(keymap.Describe(keymap.Keys.Toggle, "change the status"))))
+	}
+	return style.FieldBlockStyle.Render(ui.JoinVertical(lines))
+}
+
+func (c *SecurityComponent) Update(msg tea.Msg, mode ui.FocusMode) (FieldComponent, tea.Cmd) {
+	c.mode = mode
+	if mode != ui.ModeEditing {
+		return c, nil
+	}
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return c, nil
+	}
+	keys := keymap.Keys
+	switch {
+	case key.Matches(keyMsg, keys.Up):
+		if c.index > 0 {
+			c.index--
+		}
+	case key.Matches(keyMsg, keys.Down):
+		if c.index < len(securityFeatures)-1 {
+			c.index++
+		}
+	case key.Matches(keyMsg, keys.Toggle, keys.Right):
+		c.cycle(1)
+	case key.Matches(keyMsg, keys.Left):
+		c.cycle(-1)
+	}
+	return c, nil
+}
+
+func (c *SecurityComponent) KeyHelp() []key.Binding {
+	keys := keymap.Keys
+	return []key.Binding{
+		keys.Up, keys.Down,
+		keymap.Describe(keys.Toggle, "change the status"),
+		keymap.Describe(keys.Left, "previous status"),
+		keymap.Describe(keys.Back, "finish editing"),
+	}
+}
+
+func (c *SecurityComponent) Focus() tea.Cmd {
+	c.focused = true
+	return nil
+}
+
+func (c *SecurityComponent) Blur() {
+	c.focused = false
+}
+
+func (c *SecurityComponent) IsFocused() bool {
+	return c.focused
+}
+
+func (c *SecurityComponent) Init() tea.Cmd {
+	return nil
+}
+
+func (c *SecurityComponent) Label() string {
+	return c.label
+}
+
+func (c *SecurityComponent) CursorOffset() int {
+	return 0
+}
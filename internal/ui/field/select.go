@@ -0,0 +1,121 @@
+package field
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/artemlive/gh-crossplane/internal/keymap"
+	ui "github.com/artemlive/gh-crossplane/internal/ui/shared"
+	"github.com/artemlive/gh-crossplane/internal/ui/style"
+	"github.com/charmbracelet/bubbles/v2/key"
+	tea "github.com/charmbracelet/bubbletea/v2"
+)
+
+// SelectComponent picks one of the allowed values of a field.
+// While editing: left/right or space cycle through the options, the first option is "unset".
+type SelectComponent struct {
+	label   string
+	value   *string
+	options []string
+	focused bool
+	mode    ui.FocusMode
+}
+
+// compile-time check to ensure SelectComponent implements the FieldComponent interface
+var _ FieldComponent = (*SelectComponent)(nil)
+
+func NewSelectComponent(label string, value *string, options []string) *SelectComponent {
+	return &SelectComponent{
+		label:   label,
+		value:   value,
+		options: options,
+	}
+}
+
+func (c *SelectComponent) View() string {
+	val := *c.value
+	if val == "" {
+		val = style.InactiveTextStyle.Render("<unset>")
+	}
+	if c.focused && c.mode == ui.ModeEditing {
+		val = "< " + val + " >"
+	}
+	cursor := " "
+	if c.focused {
+		cursor = style.FocusedPrefix
+	}
+	return fmt.Sprintf("%s %s: %s", cursor, c.label, val)
+}
+
+func (c *SelectComponent) Update(msg tea.Msg, mode ui.FocusMode) (FieldComponent, tea.Cmd) {
+	c.mode = mode
+	if mode != ui.ModeEditing {
+		return c, nil
+	}
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return c, nil
+	}
+	keys := keymap.Keys
+	switch {
+	case key.Matches(keyMsg, keys.Right, keys.Toggle):
+		c.cycle(1)
+	case key.Matches(keyMsg, keys.Left):
+		c.cycle(-1)
+	case key.Matches(keyMsg, keys.Done):
+		return c, func() tea.Msg { return FieldDoneMsg{} }
+	case key.Matches(keyMsg, keys.Up):
+		return c, func() tea.Msg { return FieldDoneUpMsg{} }
+	case key.Matches(keyMsg, keys.Down):
+		return c, func() tea.Msg { return FieldDoneDownMsg{} }
+	}
+	return c, nil
+}
+
+func (c *SelectComponent) KeyHelp() []key.Binding {
+	keys := keymap.Keys
+	return []key.Binding{
+		keymap.Describe(keys.Right, "next value"),
+		keymap.Describe(keys.Left, "previous value"),
+		keymap.Describe(keys.Toggle, "next value"),
+		keys.Done, keys.Up, keys.Down,
+	}
+}
+
+// cycle moves to the next or the previous option, "unset" being the one before the first
+func (c *SelectComponent) cycle(delta int) {
+	// index 0 is unset
+	n := len(c.options) + 1
+	idx := slices.Index(c.options, *c.value) + 1
+	idx = (idx + delta + n) % n
+	if idx == 0 {
+		*c.value = ""
+		return
+	}
+	*c.value = c.options[idx-1]
+}
+
+func (c *SelectComponent) Focus() tea.Cmd {
+	c.focused = true
+	return nil
+}
+
+func (c *SelectComponent) Blur() {
+	c.focused = false
+}
+
+func (c *SelectComponent) IsFocused() bool {
+	return c.focused
+}
+
+func (c *SelectComponent) Init() tea.Cmd {
+	return nil
+}
+
+func (c *SelectComponent) Label() string {
+	return c.label
+}
+
+func (c *SelectComponent) CursorOffset() int {
+	return 0
+}
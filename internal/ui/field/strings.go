@@ -0,0 +1,51 @@
+package field
+
+import (
+	"strings"
+
+	ui "github.com/artemlive/gh-crossplane/internal/ui/shared"
+	tea "github.com/charmbracelet/bubbletea/v2"
+)
+
+// StringListComponent edits a list of strings, such as the topics,
+// as a comma separated text
+type StringListComponent struct {
+	*TextInputComponent
+	values *[]string
+}
+
+// compile-time check to ensure StringListComponent implements the FieldComponent interface
+var _ FieldComponent = (*StringListComponent)(nil)
+
+func NewStringListComponent(label string, values *[]string) *StringListComponent {
+	text := strings.Join(*values, ", ")
+	c := &StringListComponent{
+		TextInputComponent: NewTextInputComponent(label, &text),
+		values:             values,
+	}
+	c.ti.SetWidth(40)
+	c.SetPlaceholder("comma separated")
+	return c
+}
+
+func (c *StringListComponent) Update(msg tea.Msg, mode ui.FocusMode) (FieldComponent, tea.Cmd) {
+	_, cmd := c.TextInputComponent.Update(msg, mode)
+	if mode == ui.ModeEditing {
+		c.store()
+	}
+	return c, cmd
+}
+
+// store writes the list back, the slice is replaced rather than
+// changed in place since it may be shared with the loaded group
+func (c *StringListComponent) store() {
+	var out []string
+	for _, item := range strings.Split(c.Value(), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	if strings.Join(out, ",") != strings.Join(*c.values, ",") {
+		*c.values = out
+	}
+}
@@ -4,8 +4,10 @@ import (
 	"fmt"
 
 	"github.com/artemlive/gh-crossplane/debug"
+	"github.com/artemlive/gh-crossplane/internal/keymap"
 	ui "github.com/artemlive/gh-crossplane/internal/ui/shared"
 	"github.com/artemlive/gh-crossplane/internal/ui/style"
+	"github.com/charmbracelet/bubbles/v2/key"
 	"github.com/charmbracelet/bubbles/v2/textinput"
 	tea "github.com/charmbracelet/bubbletea/v2"
 	"github.com/charmbracelet/lipgloss"
@@ -29,7 +31,7 @@ func NewTextInputComponent(label string, val *string) *TextInputComponent {
 		ti.SetValue(*val)
 	}
 
-	ti.Styles = textinput.DefaultStyles(true)
+	ti.Styles = style.TextInputStyles()
 
 	return &TextInputComponent{
 		label:   label,
@@ -105,13 +107,14 @@ func (c *TextInputComponent) Update(msg tea.Msg, mode ui.FocusMode) (FieldCompon
 		c.ti, cmd = c.ti.Update(msg)
 		c.SetValue(c.ti.Value())
 
-		if key, ok := msg.(tea.KeyMsg); ok {
-			switch key.String() {
-			case "enter":
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			keys := keymap.Keys
+			switch {
+			case key.Matches(keyMsg, keys.Done):
 				return c, func() tea.Msg { return FieldDoneMsg{} }
-			case "up":
+			case key.Matches(keyMsg, keys.FieldUp):
 				return c, func() tea.Msg { return FieldDoneUpMsg{} }
-			case "down":
+			case key.Matches(keyMsg, keys.FieldDown):
 				return c, func() tea.Msg { return FieldDoneDownMsg{} }
 			}
 		}
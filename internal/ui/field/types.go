@@ -2,6 +2,7 @@ package field
 
 import (
 	ui "github.com/artemlive/gh-crossplane/internal/ui/shared"
+	"github.com/charmbracelet/bubbles/v2/key"
 	tea "github.com/charmbracelet/bubbletea/v2"
 )
 
@@ -28,12 +29,28 @@ type PreviewableComponent interface {
 	PreviewLines() []string
 }
 
+// KeyHelper is implemented by the components with keys of their own while
+// they are edited, the help overlay lists them
+type KeyHelper interface {
+	// KeyHelp returns nil while a text input of the component is edited
+	KeyHelp() []key.Binding
+}
+
 type Cursorer interface {
 	Cursor() *tea.Cursor
 }
+
+// FocusLiner is implemented by the components listing many items, the editor
+// scrolls to the line of the focused item instead of the top of the component
+type FocusLiner interface {
+	// FocusLine returns the line of the focused item in the view of the component
+	FocusLine() int
+}
+
 type RenderResult struct {
 	Components []RenderedComponent // main fields, for cursor tracking
 	ExtraLines []string            // previews, modals, footers
+	SideLines  []string            // previews, beside the fields if the terminal is wide enough, below otherwise
 }
 
 type RenderedComponent struct {
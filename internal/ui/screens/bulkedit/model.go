@@ -0,0 +1,282 @@
+package bulkedit
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/artemlive/gh-crossplane/internal/bulk"
+	"github.com/artemlive/gh-crossplane/internal/gitops"
+	"github.com/artemlive/gh-crossplane/internal/keymap"
+	"github.com/artemlive/gh-crossplane/internal/ui/field"
+	ui "github.com/artemlive/gh-crossplane/internal/ui/shared"
+	"github.com/artemlive/gh-crossplane/internal/ui/style"
+	"github.com/charmbracelet/bubbles/v2/key"
+	"github.com/charmbracelet/bubbles/v2/viewport"
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
+)
+
+const (
+	inputGroups = iota
+	inputLabels
+	inputRepos
+	inputField
+	inputValue
+)
+
+// BulkEditModel sets a field in the selected groups or repositories:
+// the selection and the value are entered in a form, then the diffs
+// of all the files are previewed before they are written at once
+type BulkEditModel struct {
+	services ui.Services
+	inputs   []*field.TextInputComponent
+	focused  int
+	width    int
+	height   int
+	message  ui.Message
+
+	// changes are previewed in the viewport, nil while the form is shown
+	changes []bulk.Change
+	preview *viewport.Model
+}
+
+func NewBulkEditModel(services ui.Services, width, height int) *BulkEditModel {
+	m := &BulkEditModel{
+		services: services,
+		width:    width,
+		height:   height,
+	}
+	for _, in := range []struct{ label, placeholder string }{
+		{"Groups", "all, or platform-*"},
+		{"Labels", "team=platform"},
+		{"Repositories", "none, or api-*"},
+		{"Field", "deleteBranchOnMerge"},
+		{"Value", "true"},
+	} {
+		ti := field.NewTextInputComponent(in.label, nil)
+		ti.SetPlaceholder(in.placeholder)
+		m.inputs = append(m.inputs, ti)
+	}
+	return m
+}
+
+func (m *BulkEditModel) Init() tea.Cmd {
+	return m.inputs[m.focused].Focus()
+}
+
+func (m *BulkEditModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		return m, nil
+	case tea.KeyMsg:
+		if m.preview != nil {
+			return m.updatePreview(msg)
+		}
+		keys := keymap.Keys
+		switch {
+		case key.Matches(msg, keys.Back):
+			return m, func() tea.Msg { return ui.SwitchToMenuMsg{} }
+		case key.Matches(msg, keys.NextField, keys.FieldDown):
+			return m, m.focus((m.focused + 1) % len(m.inputs))
+		case key.Matches(msg, keys.PrevField, keys.FieldUp):
+			return m, m.focus((m.focused - 1 + len(m.inputs)) % len(m.inputs))
+		case key.Matches(msg, keys.Select):
+			m.openPreview()
+			return m, nil
+		}
+		m.message = ui.Message{}
+	}
+	if m.preview != nil {
+		return m, nil
+	}
+	_, cmd := m.inputs[m.focused].Update(msg, ui.ModeEditing)
+	return m, cmd
+}
+
+func (m *BulkEditModel) focus(i int) tea.Cmd {
+	m.inputs[m.focused].Blur()
+	m.focused = i
+	return m.inputs[m.focused].Focus()
+}
+
+func (m *BulkEditModel) value(i int) string {
+	return strings.TrimSpace(m.inputs[i].Value())
+}
+
+// edit builds the edit from the form
+func (m *BulkEditModel) edit() (bulk.Edit, error) {
+	labels, err := bulk.ParseLabels(m.value(inputLabels))
+	if err != nil {
+		return bulk.Edit{}, err
+	}
+	return bulk.Edit{
+		Selector: bulk.Selector{
+			Groups: bulk.ParseList(m.value(inputGroups)),
+			Labels: labels,
+			Repos:  bulk.ParseList(m.value(inputRepos)),
+		},
+		Field: m.value(inputField),
+		Value: m.value(inputValue),
+	}, nil
+}
+
+// openPreview plans the edit and shows the diffs of the files
+func (m *BulkEditModel) openPreview() {
+	e, err := m.edit()
+	if err == nil {
+		m.changes, err = bulk.Plan(m.services.Loader.Groups(), e)
+	}
+	switch {
+	case errors.Is(err, bulk.ErrNoChanges):
+		m.message = ui.InfoMessage("Nothing to change, " + err.Error())
+		return
+	case err != nil:
+		m.message = ui.ErrorMessage(err.Error())
+		return
+	}
+
+	var sections []string
+	for _, c := range m.changes {
+		sections = append(sections, style.LabelStyle.Render(c.Group.Path)+"\n"+ui.FormatDiff(c.Diff))
+	}
+	// leave room for the title and the help line
+	h, v := style.AppStyle.GetFrameSize()
+	vp := viewport.New(viewport.WithWidth(max(m.width-h, 20)), viewport.WithHeight(max(m.height-v-2, 5)))
+	vp.KeyMap = keymap.Viewport()
+	vp.SetContent(strings.Join(sections, "\n\n"))
+	m.preview = &vp
+	m.message = ui.Message{}
+}
+
+func (m *BulkEditModel) closePreview() {
+	m.preview = nil
+	m.changes = nil
+}
+
+func (m *BulkEditModel) updatePreview(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	keys := keymap.Keys
+	switch {
+	case key.Matches(msg, keys.Cancel):
+		m.closePreview()
+		return m, nil
+	case key.Matches(msg, keys.Confirm):
+		m.apply(false)
+		return m, nil
+	case key.Matches(msg, keys.Commit):
+		m.apply(true)
+		return m, nil
+	}
+	vp, cmd := m.preview.Update(msg)
+	m.preview = &vp
+	return m, cmd
+}
+
+// apply writes the changed files, and commits them if asked to
+func (m *BulkEditModel) apply(commit bool) {
+	if commit && m.services.Repo == nil {
+		m.message = ui.ErrorMessage(fmt.Sprintf("'%s' is not inside a git repository", m.services.Loader.Dir()))
+		return
+	}
+	changes := m.changes
+	summary := bulk.Summary(changes)
+	err := bulk.Apply(m.services.Loader, changes)
+	m.closePreview()
+	if err != nil {
+		m.message = ui.ErrorMessage("Error saving the changes: " + err.Error())
+		return
+	}
+	m.message = ui.InfoMessage(fmt.Sprintf("Changed %s.", summary))
+	if !commit {
+		return
+	}
+
+	res, err := gitops.CommitGroupChanges(m.services.Repo, bulk.Groups(changes), time.Now())
+	switch {
+	case errors.Is(err, gitops.ErrNothingToCommit):
+		m.message = ui.WarningMessage("Nothing to commit, the group files are unchanged.")
+	case err != nil:
+		m.message = ui.ErrorMessage(fmt.Sprintf("Changed %s, but committing failed: %s", summary, err.Error()))
+	default:
+		m.message = ui.InfoMessage(fmt.Sprintf("Changed %s, committed %d file(s) to branch '%s'.", summary, len(res.Files), res.Branch))
+	}
+}
+
+// matching describes how many groups the selection of the form matches
+func (m *BulkEditModel) matching() string {
+	e, err := m.edit()
+	if err != nil {
+		return ""
+	}
+	n := 0
+	for _, gf := range m.services.Loader.Groups() {
+		if e.Selector.MatchGroup(gf) {
+			n++
+		}
+	}
+	return fmt.Sprintf("%d group(s) selected", n)
+}
+
+func (m *BulkEditModel) View() (string, *tea.Cursor) {
+	if m.preview != nil {
+		title := style.LabelStyle.Render(fmt.Sprintf("Changes in %s", bulk.Summary(m.changes)))
+		help := style.InactiveTextStyle.Render(keymap.Hints(m.keys()...))
+		return style.AppStyle.Render(ui.JoinVertical([]string{title, m.preview.View(), help})), nil
+	}
+
+	var layers []*lipgloss.Layer
+	var cursor *tea.Cursor
+	layoutY := 0
+
+	layers = append(layers, lipgloss.NewLayer(style.LabelStyle.Render("Bulk edit")).Y(layoutY))
+	layoutY += 2
+
+	for i, in := range m.inputs {
+		view := in.View()
+		layers = append(layers, lipgloss.NewLayer(view).Y(layoutY))
+		if i == m.focused {
+			if cur := in.Cursor(); cur != nil {
+				cursor = tea.NewCursor(in.CursorOffset()+cur.X, layoutY+cur.Y)
+			}
+		}
+		layoutY += lipgloss.Height(view)
+		if i == inputRepos {
+			layers = append(layers, lipgloss.NewLayer(style.InactiveTextStyle.Render(m.matching())).Y(layoutY))
+			layoutY += 2
+		}
+	}
+
+	layoutY++
+	help := style.InactiveTextStyle.Render("Patterns and labels are comma separated. Without repositories the group field is set, an empty value removes the field.\n" + keymap.Hints(m.keys()...))
+	layers = append(layers, lipgloss.NewLayer(help).Y(layoutY))
+	layoutY += lipgloss.Height(help)
+
+	if m.message.Msg != "" {
+		layers = append(layers, lipgloss.NewLayer("\n"+ui.FormatMessage(m.message)).Y(layoutY))
+	}
+	return lipgloss.NewCanvas(layers...).Render(), cursor
+}
+
+// keys are the keys of the form, or of the preview of the changes
+func (m *BulkEditModel) keys() []key.Binding {
+	keys := keymap.Keys
+	if m.preview != nil {
+		return []key.Binding{
+			keymap.Describe(keys.Up, "scroll up"), keymap.Describe(keys.Down, "scroll down"),
+			keymap.Describe(keys.Confirm, "save"), keymap.Describe(keys.Commit, "save and commit"),
+			keymap.Describe(keys.Cancel, "back"),
+		}
+	}
+	return []key.Binding{
+		keys.NextField, keys.PrevField,
+		keymap.Describe(keys.Select, "preview the changes"),
+		keymap.Describe(keys.Back, "return to main menu"),
+	}
+}
+
+// KeyHelp lists the keys of the form, the values are typed while there's no preview
+func (m *BulkEditModel) KeyHelp() ([][]key.Binding, bool) {
+	return [][]key.Binding{m.keys()}, m.preview == nil
+}
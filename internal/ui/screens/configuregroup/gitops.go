@@ -0,0 +1,83 @@
+package configuregroup
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/artemlive/gh-crossplane/internal/gitops"
+	ui "github.com/artemlive/gh-crossplane/internal/ui/shared"
+	tea "github.com/charmbracelet/bubbletea/v2"
+)
+
+// proposeDoneMsg is sent when the background push and pull request creation finishes
+type proposeDoneMsg struct {
+	result *gitops.ProposeResult
+	err    error
+}
+
+// commitChanges saves the group and commits all changed group files
+// to a new branch of the groups dir repository.
+// It returns false if there was nothing committed because of an error.
+func (m *ConfigureGroupModel) commitChanges() bool {
+	if m.services.Repo == nil {
+		m.message = ui.ErrorMessage(fmt.Sprintf("'%s' is not inside a git repository", m.services.Loader.Dir()))
+		return false
+	}
+
+	if m.group.Modified() {
+		if err := m.saveGroup(); err != nil {
+			// the message explains why
+			return false
+		}
+	}
+
+	res, err := gitops.CommitGroupChanges(m.services.Repo, m.services.Loader.Groups(), time.Now())
+	switch {
+	case errors.Is(err, gitops.ErrNothingToCommit):
+		m.message = ui.WarningMessage("Nothing to commit, the group files are unchanged.")
+	case err != nil:
+		m.message = ui.ErrorMessage("Error committing changes: " + err.Error())
+		return false
+	default:
+		m.message = ui.InfoMessage(fmt.Sprintf("Committed %d file(s) to branch '%s'.", len(res.Files), res.Branch))
+	}
+	return true
+}
+
+// proposeChanges commits the pending changes, then pushes the branch
+// and opens a pull request in the background
+func (m *ConfigureGroupModel) proposeChanges() tea.Cmd {
+	if m.proposing {
+		return nil
+	}
+	if m.services.GitHub == nil {
+		m.message = ui.ErrorMessage("GitHub client is not available, run 'gh auth login' first")
+		return nil
+	}
+	if !m.commitChanges() {
+		return nil
+	}
+
+	m.proposing = true
+	m.message = ui.InfoMessage("Pushing the branch and opening a pull request...")
+
+	repo, gh, groups := m.services.Repo, m.services.GitHub, m.services.Loader.Groups()
+	return func() tea.Msg {
+		res, err := gitops.Propose(repo, gh, groups)
+		return proposeDoneMsg{result: res, err: err}
+	}
+}
+
+func (m *ConfigureGroupModel) handleProposeDone(msg proposeDoneMsg) (tea.Model, tea.Cmd) {
+	m.proposing = false
+	switch {
+	case msg.err != nil:
+		m.message = ui.ErrorMessage("Error proposing changes: " + msg.err.Error())
+	case msg.result.Existing:
+		m.message = ui.InfoMessage("Pushed to the existing pull request " + msg.result.PullRequest.HTMLURL)
+	default:
+		m.message = ui.InfoMessage("Opened pull request " + msg.result.PullRequest.HTMLURL)
+	}
+	return m, nil
+}
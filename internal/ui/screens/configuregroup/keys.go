@@ -0,0 +1,59 @@
+package configuregroup
+
+import (
+	"github.com/artemlive/gh-crossplane/internal/keymap"
+	"github.com/artemlive/gh-crossplane/internal/layout"
+	"github.com/artemlive/gh-crossplane/internal/ui/field"
+	ui "github.com/artemlive/gh-crossplane/internal/ui/shared"
+	"github.com/charmbracelet/bubbles/v2/key"
+)
+
+// compile-time check to ensure the editor lists its keys in the help overlay
+var _ ui.KeyHelper = ConfigureGroupModel{}
+
+// KeyHelp lists the keys of the prompt, the repository editor or the field shown
+func (m ConfigureGroupModel) KeyHelp() ([][]key.Binding, bool) {
+	keys := keymap.Keys
+	switch {
+	case m.pendingChange != nil:
+		return [][]key.Binding{{keys.Merge, keys.Reload, keys.Keep}}, false
+	case m.preview != nil:
+		return [][]key.Binding{previewKeys()}, false
+	case m.presetPrompt != nil:
+		return [][]key.Binding{m.presetPrompt.keys()}, false
+	case m.groupRename != nil:
+		// the name is typed in the first field
+		return [][]key.Binding{renameKeys()}, m.groupRename.focused == 0
+	case m.isModalOpen():
+		if h, ok := m.modal.(ui.KeyHelper); ok {
+			return h.KeyHelp()
+		}
+		return nil, false
+	}
+
+	var focused field.FieldComponent
+	if comps := m.fieldComponents[m.activeTab]; m.focusedIndex < len(comps) {
+		focused = comps[m.focusedIndex]
+	}
+	h, _ := focused.(field.KeyHelper)
+	if m.mode == ui.ModeEditing {
+		finish := keymap.Describe(keys.Back, "finish editing")
+		if h == nil || h.KeyHelp() == nil {
+			return [][]key.Binding{{keys.Done, keys.FieldUp, keys.FieldDown, finish}}, true
+		}
+		return [][]key.Binding{h.KeyHelp(), {finish}}, false
+	}
+
+	moving := []key.Binding{keys.Up, keys.Down, keys.NextField, keys.PrevField, keys.Left, keys.Right, keys.Edit}
+	if m.tabs[m.activeTab].Kind == layout.KindRepositories && h != nil {
+		moving = append(h.KeyHelp(), keys.Left, keys.Right)
+	}
+	if m.repoEdit != nil {
+		return [][]key.Binding{moving, m.repositoryKeys()}, false
+	}
+	return [][]key.Binding{
+		moving,
+		{keys.Save, keys.Commit, keys.Propose, keys.Render, keys.Preset, keys.Rename, keys.XRDForm},
+		{keymap.Describe(keys.Back, "main menu"), keys.Quit, keys.ForceQuit},
+	}, false
+}
@@ -3,14 +3,21 @@ package configuregroup
 import (
 	"fmt"
 	"reflect"
-	"strings"
+	"slices"
 	"time"
 
+	"github.com/artemlive/gh-crossplane/debug"
+	"github.com/artemlive/gh-crossplane/internal/directory"
+	"github.com/artemlive/gh-crossplane/internal/domain"
+	"github.com/artemlive/gh-crossplane/internal/keymap"
+	"github.com/artemlive/gh-crossplane/internal/layout"
 	"github.com/artemlive/gh-crossplane/internal/manifest"
 	"github.com/artemlive/gh-crossplane/internal/ui/field"
 	ui "github.com/artemlive/gh-crossplane/internal/ui/shared"
 	"github.com/artemlive/gh-crossplane/internal/ui/style"
 	"github.com/artemlive/gh-crossplane/internal/util"
+	"github.com/charmbracelet/bubbles/v2/key"
+	"github.com/charmbracelet/bubbles/v2/viewport"
 	tea "github.com/charmbracelet/bubbletea/v2"
 	"github.com/charmbracelet/lipgloss/v2"
 )
@@ -82,7 +89,7 @@ func (m *ConfigureGroupModel) isModalOpen() bool {
 }
 
 type ConfigureGroupModel struct {
-	tabs            []field.FieldGroup
+	tabs            []layout.Tab
 	fieldComponents [][]field.FieldComponent // one slice per tab
 	activeTab       int
 	group           *manifest.GroupFile
@@ -90,45 +97,154 @@ type ConfigureGroupModel struct {
 	width           int
 	height          int
 	focusedIndex    int
+	scroll          int // first line of the fields shown, see keepFocusVisible
 
-	mode    ui.FocusMode // current focus mode, either navigation or editing
-	loader  *manifest.ManifestLoader
-	message ui.Message
+	mode ui.FocusMode // current focus mode, either navigation or editing
+	// services are the dependencies the editor was opened with
+	services ui.Services
+	message  ui.Message
+
+	proposing bool // a pull request is being opened in the background
 
 	tabHandlers []TabHandler
 	modal       ui.ViewableModel
+
+	// pendingChange is set when the group file changed on disk
+	// while there are unsaved edits
+	pendingChange *manifest.GroupFile
+
+	// preview shows the rendered managed resources, nil when closed
+	preview *viewport.Model
+	// presetPrompt applies a preset to the group, nil when closed
+	presetPrompt *presetPrompt
+	// groupRename renames the group, nil when closed
+	groupRename *groupRename
+	// repoEdit is set when a single repository is edited, see NewRepositoryEditor
+	repoEdit *repoEdit
 }
 
-func NewConfigureGroupModel(group *manifest.GroupFile, loader *manifest.ManifestLoader, width, height int) *ConfigureGroupModel {
+func NewConfigureGroupModel(group *manifest.GroupFile, services ui.Services, width, height int) *ConfigureGroupModel {
+	lay := services.Layout.For(group.Manifest)
 	m := ConfigureGroupModel{
-		tabs:         field.FieldGroups,
+		tabs:         lay.Tabs,
 		activeTab:    0,
 		group:        group,
 		repoIndex:    0,
 		width:        width,
 		height:       height,
 		focusedIndex: 0,
-		loader:       loader,
+		services:     services,
 	}
 
 	// initialize field components for each tab
-	m.tabHandlers = make([]TabHandler, len(field.FieldGroups))
-	for i, fg := range field.FieldGroups {
-		if fg.GroupLevel {
-			components := field.GenerateComponentsByPaths(&group.Manifest, fg.FieldPaths)
-			m.fieldComponents = append(m.fieldComponents, components)
-			m.tabHandlers[i] = &GenericTabHandler{}
-		} else {
+	m.tabHandlers = make([]TabHandler, len(m.tabs))
+	for i, tab := range m.tabs {
+		switch tab.Kind {
+		case layout.KindRepositories:
 			repoComponent := field.NewRepositoriesComponent("Repositories", &group.Manifest.Spec.Repositories)
 			m.fieldComponents = append(m.fieldComponents, []field.FieldComponent{repoComponent})
 			m.tabHandlers[i] = &RepositoryTabHandler{}
+		default:
+			components := field.GenerateComponents(&group.Manifest, tab.Fields, tab.Labels)
+			m.setSources(components)
+			m.fieldComponents = append(m.fieldComponents, components)
+			m.tabHandlers[i] = &GenericTabHandler{}
 		}
 	}
 	return &m
 }
 
+// AddRepository appends a new repository to the group and selects it,
+// the group isn't saved
+func (m *ConfigureGroupModel) AddRepository(repo domain.Repository) {
+	// don't append to the slice shared with the loaded copy
+	repos := slices.Clone(m.group.Manifest.Spec.Repositories)
+	m.group.Manifest.Spec.Repositories = append(repos, repo)
+	m.FocusRepository(repo.Name)
+	m.message = ui.InfoMessage(fmt.Sprintf("Repository '%s' added, press %s to save.", repo.Name, keymap.Name(keymap.Keys.Save)))
+}
+
+// FocusRepository switches to the repositories tab and selects the repository,
+// it reports whether the group has the repository
+func (m *ConfigureGroupModel) FocusRepository(name string) bool {
+	for i, h := range m.tabHandlers {
+		if _, ok := h.(*RepositoryTabHandler); !ok {
+			continue
+		}
+		for _, c := range m.fieldComponents[i] {
+			if rc, ok := c.(*field.RepositoriesComponent); ok && rc.Select(name) {
+				m.activeTab = i
+				m.focusedIndex = 0
+				m.mode = ui.ModeNavigation
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// setSources connects the components to the completion sources
+func (m *ConfigureGroupModel) setSources(components []field.FieldComponent) {
+	for _, c := range components {
+		switch c := c.(type) {
+		case *field.PermissionsComponent:
+			if m.services.Directory != nil {
+				c.SetLookup(m.services.Directory)
+			}
+		case *field.ProtectionsComponent:
+			// the model is copied on every update, the group file isn't
+			group, cache := m.group, m.services.Checks
+			c.SetContextSource(func() []string {
+				return cache.Contexts(repositoryNames(group))
+			})
+		}
+	}
+}
+
+func repositoryNames(group *manifest.GroupFile) []string {
+	var names []string
+	for _, r := range group.Manifest.Spec.Repositories {
+		names = append(names, r.Name)
+	}
+	return names
+}
+
+// refreshChecks fetches the status checks of the group repositories in the background,
+// they show up as suggestions once fetched
+func (m *ConfigureGroupModel) refreshChecks() tea.Cmd {
+	if m.services.Checks == nil || m.services.GitHub == nil {
+		return nil
+	}
+	branches := make(map[string]string)
+	for _, r := range m.group.Manifest.Spec.Repositories {
+		branches[r.Name] = domain.Effective(m.group.Manifest.Spec, r).DefaultBranch
+	}
+	c := m.services.Checks
+	return func() tea.Msg {
+		if err := c.Refresh(branches, time.Now()); err != nil {
+			debug.Log.Printf("Failed to refresh the status checks: %v", err)
+		}
+		return nil
+	}
+}
+
+// lookupCollaborators checks in the background whether the collaborators
+// of the group exist, the unknown ones are reported when the group is saved
+func (m *ConfigureGroupModel) lookupCollaborators() tea.Cmd {
+	if m.services.Directory == nil || m.services.GitHub == nil {
+		return nil
+	}
+	d, logins := m.services.Directory, directory.Collaborators(m.group.Manifest)
+	return func() tea.Msg {
+		if err := d.LookupUsers(logins); err != nil {
+			debug.Log.Printf("Failed to look up the collaborators: %v", err)
+		}
+		return nil
+	}
+}
+
 func (m *ConfigureGroupModel) Init() tea.Cmd {
-	var cmds []tea.Cmd
+	cmds := []tea.Cmd{m.refreshChecks(), m.lookupCollaborators()}
 
 	// focus first field if present
 	comps := m.fieldComponents[m.activeTab]
@@ -170,7 +286,7 @@ func (m *ConfigureGroupModel) handlePrevField() (tea.Model, tea.Cmd) {
 func (m *ConfigureGroupModel) switchTab(delta int) (tea.Model, tea.Cmd) {
 	m.activeTab = (m.activeTab + delta + len(m.tabs)) % len(m.tabs)
 
-	if m.tabs[m.activeTab].TabName == "Repositories" {
+	if m.tabs[m.activeTab].Kind == layout.KindRepositories {
 		// force repositories tab to be in navigation mode
 		m.mode = ui.ModeNavigation
 	}
@@ -186,71 +302,104 @@ func (m *ConfigureGroupModel) switchTab(delta int) (tea.Model, tea.Cmd) {
 }
 
 func (m ConfigureGroupModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	return m.tabHandlers[m.activeTab].Update(&m, msg)
+	switch msg := msg.(type) {
+	case ui.GroupsReloadedMsg:
+		if m.repoEdit != nil {
+			// the repository is saved over the file it was opened from
+			return &m, nil
+		}
+		return m.handleGroupsReloaded(msg)
+	case proposeDoneMsg:
+		return m.handleProposeDone(msg)
+	case tea.WindowSizeMsg:
+		// the repository editor gets the size from the tab handler below
+		m.width, m.height = msg.Width, msg.Height
+		if m.preview != nil {
+			m.sizeViewport(m.preview)
+		}
+		if m.presetPrompt != nil && m.presetPrompt.diff != nil {
+			m.sizeViewport(m.presetPrompt.diff)
+		}
+	}
+	if msg, ok := msg.(tea.KeyMsg); ok && m.pendingChange != nil {
+		return m.handleReloadPrompt(msg)
+	}
+	if msg, ok := msg.(tea.KeyMsg); ok && m.repoEdit != nil && m.preview == nil && m.mode == ui.ModeNavigation {
+		if cmd, handled := m.handleRepositoryKeys(msg); handled {
+			return &m, cmd
+		}
+	}
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case m.preview != nil:
+			return m.handleRenderPreview(msg)
+		case m.presetPrompt != nil:
+			return m.handlePresetPrompt(msg)
+		case m.groupRename != nil:
+			return m.handleGroupRename(msg)
+		case key.Matches(msg, keymap.Keys.Render) && !m.isModalOpen():
+			m.openRenderPreview()
+			return &m, nil
+		case key.Matches(msg, keymap.Keys.Preset) && !m.isModalOpen() && m.mode == ui.ModeNavigation:
+			m.openPresetPrompt()
+			return &m, nil
+		case key.Matches(msg, keymap.Keys.Rename) && !m.isModalOpen() && m.mode == ui.ModeNavigation:
+			cmd := m.openGroupRename()
+			return &m, cmd
+		case key.Matches(msg, keymap.Keys.XRDForm) && !m.isModalOpen() && m.mode == ui.ModeNavigation && m.repoEdit == nil:
+			return &m, m.openXRDForm()
+		}
+	}
+	model, cmd := m.tabHandlers[m.activeTab].Update(&m, msg)
+	if cm, ok := model.(*ConfigureGroupModel); ok {
+		cm.keepFocusVisible()
+	}
+	return model, cmd
 }
 
 // View renders the entire view of the ConfigureGroupModel.
 // The cursor position must be calculated based on the curren layout
 // the component itself knows it's X cursor offset, but the Y position is determined by the layout
 func (m ConfigureGroupModel) View() (string, *tea.Cursor) {
-	var layers []*lipgloss.Layer
-	var globalCursor *tea.Cursor
-	layoutY := 0
-
-	// === TABS HEADER ===
-	tabs := m.renderTabs()
-	tabLines := strings.Split(tabs, "\n")
-	layers = append(layers, lipgloss.NewLayer(tabs).Y(layoutY))
-	layoutY += len(tabLines)
-
-	// === SPACER ===
-	layers = append(layers, lipgloss.NewLayer("").Y(layoutY))
-	layoutY++
-
-	// === DELEGATED RENDERING ===
-	res := m.tabHandlers[m.activeTab].Render(&m)
-
-	for _, rc := range res.Components {
-		view := strings.Join(rc.Lines, "\n")
-		layers = append(layers, lipgloss.NewLayer(view).Y(layoutY))
-
-		// Track cursor if focused
-		if rc.Component.IsFocused() {
-			if c, ok := rc.Component.(field.Cursorer); ok {
-				cursor := c.Cursor()
-				if cursor != nil {
-					globalCursor = tea.NewCursor(
-						rc.Component.CursorOffset()+cursor.X,
-						layoutY+cursor.Y,
-					)
-				}
-			}
-		}
-
-		layoutY += len(rc.Lines)
+	if m.pendingChange != nil {
+		return m.renderReloadPrompt(), nil
 	}
-
-	// === EXTRAS (Previews, Modals etc.) ===
-	for _, line := range res.ExtraLines {
-		layers = append(layers, lipgloss.NewLayer(line).Y(layoutY))
-		layoutY += lipgloss.Height(line)
+	if m.preview != nil {
+		return m.renderPreviewView(), nil
+	}
+	if m.presetPrompt != nil {
+		return m.renderPresetPrompt(), nil
+	}
+	if m.groupRename != nil {
+		return m.renderGroupRename()
+	}
+	if m.modal != nil {
+		return m.modal.View()
 	}
 
-	// === STATUS BAR ===
-	status := m.tabHandlers[m.activeTab].StatusBarText(&m)
-	statusLines := strings.Split(status, "\n")
-	layers = append(layers, lipgloss.NewLayer(status).Y(layoutY))
-	layoutY += len(statusLines)
+	header := m.renderHeader()
+	y := lipgloss.Height(header)
+	fields, cursor := m.renderFields(y)
+	footer := m.renderFooter()
+
+	return ui.JoinVertical([]string{header, fields, footer}), cursor
+}
 
-	// === MESSAGE ===
-	if msg := m.renderMessage(); msg != "" {
-		msgLines := strings.Split(msg, "\n")
-		layers = append(layers, lipgloss.NewLayer(msg).Y(layoutY))
-		layoutY += len(msgLines)
+// renderHeader renders the lines above the fields, the tabs and the repository
+// edited alone
+func (m ConfigureGroupModel) renderHeader() string {
+	var lines []string
+	if m.repoEdit != nil {
+		lines = append(lines, m.repositoryHeader(), "")
 	}
+	return m.wrap(ui.JoinVertical(append(lines, m.renderTabs(), "")))
+}
 
-	return lipgloss.NewCanvas(layers...).Render(), globalCursor
+// renderFooter renders the lines below the fields, the status bar and the message
+func (m ConfigureGroupModel) renderFooter() string {
+	return m.wrap(m.tabHandlers[m.activeTab].StatusBarText(&m) + m.renderMessage())
 }
+
 func (m ConfigureGroupModel) renderMessage() string {
 	if m.message.Msg == "" {
 		return ""
@@ -259,6 +408,10 @@ func (m ConfigureGroupModel) renderMessage() string {
 	return "\n\n" + ui.FormatMessage(m.message)
 }
 
+// maxTabRows is the number of rows the tabs wrap to on a narrow terminal,
+// only the active tab is shown if they need more
+const maxTabRows = 2
+
 func (m ConfigureGroupModel) renderTabs() string {
 	var rendered []string
 	for i, tab := range m.tabs {
@@ -266,7 +419,38 @@ func (m ConfigureGroupModel) renderTabs() string {
 		if i == m.activeTab {
 			curStyle = style.ActiveTabStyle
 		}
-		rendered = append(rendered, curStyle.Render(tab.TabName))
+		rendered = append(rendered, curStyle.Render(tab.Name))
+	}
+	row := lipgloss.JoinHorizontal(lipgloss.Top, rendered...)
+	if m.width <= 0 || lipgloss.Width(row) <= m.width {
+		return row
+	}
+	if rows := wrapTabs(rendered, m.width); len(rows) <= maxTabRows {
+		return lipgloss.JoinVertical(lipgloss.Left, rows...)
+	}
+	tab := fmt.Sprintf("◀ %s (%d/%d) ▶", m.tabs[m.activeTab].Name, m.activeTab+1, len(m.tabs))
+	if collapsed := style.ActiveTabStyle.Render(tab); lipgloss.Width(collapsed) <= m.width {
+		return collapsed
+	}
+	// the border doesn't fit either
+	return style.LabelStyle.Render(tab)
+}
+
+// wrapTabs packs the rendered tabs into the rows fitting the width
+func wrapTabs(tabs []string, width int) []string {
+	var rows, row []string
+	rowWidth := 0
+	for _, tab := range tabs {
+		w := lipgloss.Width(tab)
+		if len(row) > 0 && rowWidth+w > width {
+			rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, row...))
+			row, rowWidth = nil, 0
+		}
+		row = append(row, tab)
+		rowWidth += w
+	}
+	if len(row) > 0 {
+		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, row...))
 	}
-	return lipgloss.JoinHorizontal(lipgloss.Top, rendered...)
+	return rows
 }
@@ -0,0 +1,133 @@
+package configuregroup
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/artemlive/gh-crossplane/internal/domain"
+	"github.com/artemlive/gh-crossplane/internal/policy"
+	"github.com/artemlive/gh-crossplane/internal/ui/field"
+	"github.com/artemlive/gh-crossplane/internal/ui/style"
+	"github.com/artemlive/gh-crossplane/internal/util"
+)
+
+// specLabels maps the yaml keys of the spec fields to the labels of their components
+var specLabels = func() map[string]string {
+	labels := make(map[string]string)
+	t := reflect.TypeOf(domain.RepositoriesGroupSpec{})
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if label := util.ParseTag(sf.Tag.Get("ui"))["label"]; label != "" {
+			labels[util.YAMLName(sf)] = label
+		}
+	}
+	return labels
+}()
+
+// activeViolations drops the exempted violations
+func activeViolations(vs []policy.Violation) []policy.Violation {
+	return slices.DeleteFunc(vs, func(v policy.Violation) bool { return v.Exemption != nil })
+}
+
+// groupViolations returns the violations caused by the group settings,
+// the ones of repositories overriding the field are shown with the repository
+func (m *ConfigureGroupModel) groupViolations() []policy.Violation {
+	if m.repoEdit != nil {
+		return m.repoViolations()
+	}
+	return slices.DeleteFunc(m.services.Policies.Evaluate(m.group.Manifest), func(v policy.Violation) bool {
+		for _, r := range m.group.Manifest.Spec.Repositories {
+			if r.Name == v.Repo {
+				return overrides(r, v.Field())
+			}
+		}
+		return false
+	})
+}
+
+// overrides reports whether the repository replaces the group value of the spec field
+func overrides(repo domain.Repository, key string) bool {
+	v := reflect.ValueOf(repo)
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if util.YAMLName(t.Field(i)) == key {
+			return !v.Field(i).IsZero() && !domain.IsAdditive(t.Field(i).Name)
+		}
+	}
+	return false
+}
+
+// fieldViolations returns the violations shown beside the component
+func fieldViolations(vs []policy.Violation, component field.FieldComponent) []policy.Violation {
+	return slices.DeleteFunc(slices.Clone(vs), func(v policy.Violation) bool {
+		return specLabels[v.Field()] != component.Label()
+	})
+}
+
+// unplacedViolations returns the violations of the fields without a component in any tab
+func (m *ConfigureGroupModel) unplacedViolations(vs []policy.Violation) []policy.Violation {
+	return slices.DeleteFunc(slices.Clone(vs), func(v policy.Violation) bool {
+		for _, comps := range m.fieldComponents {
+			for _, c := range comps {
+				if specLabels[v.Field()] == c.Label() {
+					return true
+				}
+			}
+		}
+		return false
+	})
+}
+
+// violationLines renders the violations, one line per rule with the affected repositories,
+// the exempted ones apart
+func violationLines(vs []policy.Violation) []string {
+	type key struct {
+		rule   string
+		exempt bool
+	}
+	var lines []string
+	var keys []key
+	repos := make(map[key][]string)
+	first := make(map[key]policy.Violation)
+	for _, v := range vs {
+		k := key{v.Rule.Name, v.Exemption != nil}
+		if _, ok := first[k]; !ok {
+			keys = append(keys, k)
+			first[k] = v
+		}
+		repos[k] = append(repos[k], v.Repo)
+	}
+	for _, k := range keys {
+		v := first[k]
+		text := v.Rule.Description
+		if text == "" {
+			text = v.Message
+		}
+		line := fmt.Sprintf("%s: %s (%s)", k.rule, text, strings.Join(repos[k], ", "))
+		switch {
+		case v.Exemption != nil:
+			lines = append(lines, style.InactiveTextStyle.Render("  exempt from "+line+": "+v.Exemption.Reason))
+		case v.IsError():
+			lines = append(lines, style.ErrorMessageStyle.Render("  ✖ "+line))
+		default:
+			lines = append(lines, style.WarningMessageStyle.Render("  ⚠ "+line))
+		}
+	}
+	return lines
+}
+
+// repoViolationLines renders the violations of the repository
+func (m *ConfigureGroupModel) repoViolationLines(repo *domain.Repository) []string {
+	if repo == nil {
+		return nil
+	}
+	vs := slices.DeleteFunc(m.services.Policies.Evaluate(m.group.Manifest), func(v policy.Violation) bool {
+		return v.Repo != repo.Name
+	})
+	if len(vs) == 0 {
+		return nil
+	}
+	return append([]string{style.LabelStyle.Render("Policy violations:")}, violationLines(vs)...)
+}
@@ -0,0 +1,123 @@
+package configuregroup
+
+import (
+	"fmt"
+
+	"github.com/artemlive/gh-crossplane/internal/domain"
+	"github.com/artemlive/gh-crossplane/internal/keymap"
+	"github.com/artemlive/gh-crossplane/internal/manifest"
+	"github.com/artemlive/gh-crossplane/internal/presets"
+	"github.com/artemlive/gh-crossplane/internal/ui/field"
+	ui "github.com/artemlive/gh-crossplane/internal/ui/shared"
+	"github.com/artemlive/gh-crossplane/internal/ui/style"
+	"github.com/charmbracelet/bubbles/v2/key"
+	"github.com/charmbracelet/bubbles/v2/viewport"
+	tea "github.com/charmbracelet/bubbletea/v2"
+)
+
+// presetPrompt is the preset being applied to the group:
+// first the preset is picked, then the diff of the result is confirmed
+type presetPrompt struct {
+	picker *field.ListComponent
+	// the rest is set once a preset was picked
+	preset *presets.Preset
+	result domain.RepositoriesGroup
+	diff   *viewport.Model
+}
+
+// openPresetPrompt shows the presets the group settings can be merged with
+func (m *ConfigureGroupModel) openPresetPrompt() {
+	if len(m.services.Presets) == 0 {
+		m.message = ui.WarningMessage("There are no presets, add them to the presets directory")
+		return
+	}
+	m.presetPrompt = &presetPrompt{picker: field.NewPresetListComponent(m.services.Presets)}
+	m.presetPrompt.picker.Focus()
+}
+
+// handlePresetPrompt picks the preset, then applies or discards it
+func (m *ConfigureGroupModel) handlePresetPrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	p := m.presetPrompt
+	keys := keymap.Keys
+	if p.preset == nil {
+		switch {
+		case key.Matches(msg, keys.Back, keys.Quit):
+			m.presetPrompt = nil
+		case key.Matches(msg, keys.Select):
+			i := p.picker.Selected()
+			if i == 0 {
+				m.presetPrompt = nil
+				return m, nil
+			}
+			m.previewPreset(&m.services.Presets[i-1])
+		default:
+			_, cmd := p.picker.Update(msg, ui.ModeEditing)
+			return m, cmd
+		}
+		return m, nil
+	}
+
+	switch {
+	case key.Matches(msg, keys.Cancel):
+		m.presetPrompt = nil
+		return m, nil
+	case key.Matches(msg, keys.Confirm):
+		gf := *m.group
+		gf.Manifest = p.result
+		m.presetPrompt = nil
+		cmd := m.replaceGroup(&gf)
+		m.message = ui.InfoMessage(fmt.Sprintf("Preset '%s' applied, press %s to save.", p.preset.Name, keymap.Name(keys.Save)))
+		return m, cmd
+	}
+	vp, cmd := p.diff.Update(msg)
+	p.diff = &vp
+	return m, cmd
+}
+
+// previewPreset merges the preset into the group and shows what would change
+func (m *ConfigureGroupModel) previewPreset(preset *presets.Preset) {
+	result := preset.Apply(m.group.Manifest)
+	lines, err := manifest.Diff(m.group.Manifest, result)
+	if err != nil {
+		m.presetPrompt = nil
+		m.message = ui.ErrorMessage("Error comparing the preset: " + err.Error())
+		return
+	}
+	if len(lines) == 0 {
+		m.presetPrompt = nil
+		m.message = ui.InfoMessage(fmt.Sprintf("The group already has the settings of preset '%s'.", preset.Name))
+		return
+	}
+
+	vp := viewport.New()
+	m.sizeViewport(&vp)
+	vp.KeyMap = keymap.Viewport()
+	vp.SetContent(ui.FormatDiff(lines))
+	m.presetPrompt.preset = preset
+	m.presetPrompt.result = result
+	m.presetPrompt.diff = &vp
+}
+
+func (m ConfigureGroupModel) renderPresetPrompt() string {
+	p := m.presetPrompt
+	if p.preset == nil {
+		title := style.LabelStyle.Render("Apply a preset to group '" + m.group.Title() + "'")
+		help := style.InactiveTextStyle.Render(keymap.Hints(p.keys()...))
+		return style.AppStyle.Render(ui.JoinVertical([]string{title, "", p.picker.View(), "", help}))
+	}
+	title := style.LabelStyle.Render(fmt.Sprintf("Changes of preset '%s'", p.preset.Name))
+	help := style.InactiveTextStyle.Render(keymap.Hints(p.keys()...))
+	return style.AppStyle.Render(ui.JoinVertical([]string{title, p.diff.View(), help}))
+}
+
+// keys are the keys of the preset picker, then of the preview of the changes
+func (p *presetPrompt) keys() []key.Binding {
+	keys := keymap.Keys
+	if p.preset == nil {
+		return []key.Binding{keys.Up, keys.Down, keymap.Describe(keys.Select, "preview"), keymap.Describe(keys.Back, "cancel")}
+	}
+	return []key.Binding{
+		keymap.Describe(keys.Up, "scroll up"), keymap.Describe(keys.Down, "scroll down"),
+		keymap.Describe(keys.Confirm, "apply"), keys.Cancel,
+	}
+}
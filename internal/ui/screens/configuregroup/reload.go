@@ -0,0 +1,156 @@
+package configuregroup
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/artemlive/gh-crossplane/internal/keymap"
+	"github.com/artemlive/gh-crossplane/internal/manifest"
+	"github.com/artemlive/gh-crossplane/internal/reponame"
+	ui "github.com/artemlive/gh-crossplane/internal/ui/shared"
+	"github.com/artemlive/gh-crossplane/internal/ui/style"
+	"github.com/charmbracelet/bubbles/v2/key"
+	tea "github.com/charmbracelet/bubbletea/v2"
+)
+
+// saveGroup writes the group to disk. If the file was changed by someone else
+// in the meantime, the changed version is loaded and the merge prompt is shown.
+// The message tells the outcome, the error is returned if the group wasn't saved.
+func (m *ConfigureGroupModel) saveGroup() error {
+	err := m.services.Loader.SaveGroupFile(m.group)
+	switch {
+	case errors.Is(err, manifest.ErrFileChanged):
+		if _, err := m.services.Loader.Reload([]string{m.group.Path}); err != nil {
+			m.message = ui.ErrorMessage(fmt.Sprintf("Error reloading group '%s': %s", m.group.Title(), err.Error()))
+			return err
+		}
+		m.pendingChange = m.services.Loader.GetGroupByPath(m.group.Path)
+		m.message = ui.WarningMessage(fmt.Sprintf("Group '%s' was not saved: the file changed on disk.", m.group.Title()))
+		return err
+	case err != nil:
+		m.message = ui.ErrorMessage(fmt.Sprintf("Error saving group '%s': %s", m.group.Title(), err.Error()))
+		return err
+	}
+
+	m.message = ui.InfoMessage(fmt.Sprintf("Group '%s' saved successfully.", m.group.Title()))
+	warnings := m.renameWarnings()
+	if d := m.services.Directory.Directory(); d != nil {
+		warnings = append(warnings, d.Check(m.group.Manifest)...)
+	}
+	if n := len(activeViolations(m.services.Policies.Evaluate(m.group.Manifest))); n > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d policy violations", n))
+	}
+	if len(warnings) > 0 {
+		m.message = ui.WarningMessage(fmt.Sprintf("Group '%s' saved with warnings: %s", m.group.Title(), strings.Join(warnings, "; ")))
+	}
+	return nil
+}
+
+// renameWarnings warns about the repositories renamed since the group was loaded,
+// which Crossplane would recreate
+func (m *ConfigureGroupModel) renameWarnings() []string {
+	base, err := m.group.Base()
+	if err != nil {
+		// a new group, nothing to rename
+		return nil
+	}
+	return reponame.RenameWarnings(base, m.group.Manifest)
+}
+
+// handleGroupsReloaded reacts to the group file being changed on disk.
+// Without local edits the group is reloaded right away, otherwise
+// the user has to choose between merging and discarding the edits.
+func (m *ConfigureGroupModel) handleGroupsReloaded(msg ui.GroupsReloadedMsg) (tea.Model, tea.Cmd) {
+	if !slices.Contains(msg.Paths, m.group.Path) {
+		return m, nil
+	}
+
+	fresh := m.services.Loader.GetGroupByPath(m.group.Path)
+	if fresh == nil {
+		m.message = ui.WarningMessage(fmt.Sprintf("File '%s' was removed on disk, saving will recreate it.", m.group.Path))
+		return m, nil
+	}
+
+	if !m.group.Modified() {
+		cmd := m.replaceGroup(fresh)
+		m.message = ui.InfoMessage(fmt.Sprintf("Group '%s' was reloaded from disk.", m.group.Title()))
+		return m, cmd
+	}
+
+	m.pendingChange = fresh
+	return m, nil
+}
+
+// handleReloadPrompt handles the keys while the "changed on disk" prompt is shown
+func (m *ConfigureGroupModel) handleReloadPrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	fresh := m.pendingChange
+
+	keys := keymap.Keys
+	switch {
+	case key.Matches(msg, keys.Merge):
+		base, err := m.group.Base()
+		if err != nil {
+			m.message = ui.ErrorMessage("Error reading the merge base: " + err.Error())
+			return m, nil
+		}
+		merged, conflicts, err := manifest.Merge(base, m.group.Manifest, fresh.Manifest)
+		if err != nil {
+			m.message = ui.ErrorMessage("Error merging changes: " + err.Error())
+			return m, nil
+		}
+		// the version on disk becomes the new base, the merged result stays unsaved
+		gf := *m.group
+		gf.Manifest = merged
+		gf.Rebase(*fresh)
+		m.pendingChange = nil
+		cmd := m.replaceGroup(&gf)
+		if len(conflicts) > 0 {
+			m.message = ui.WarningMessage("Merged with conflicts, kept local values for: " + strings.Join(conflicts, ", "))
+		} else {
+			m.message = ui.InfoMessage(fmt.Sprintf("Merged changes from disk, press %s to save.", keymap.Name(keys.Save)))
+		}
+		return m, cmd
+	case key.Matches(msg, keys.Reload):
+		m.pendingChange = nil
+		cmd := m.replaceGroup(fresh)
+		m.message = ui.InfoMessage(fmt.Sprintf("Group '%s' was reloaded from disk, local edits were discarded.", m.group.Title()))
+		return m, cmd
+	case key.Matches(msg, keys.Keep):
+		m.group.Rebase(*fresh)
+		m.pendingChange = nil
+		m.message = ui.WarningMessage("Kept local edits, saving will overwrite the changes on disk.")
+		return m, nil
+	}
+	return m, nil
+}
+
+// replaceGroup rebuilds the field components for the new group file,
+// keeping the active tab
+func (m *ConfigureGroupModel) replaceGroup(gf *manifest.GroupFile) tea.Cmd {
+	activeTab := m.activeTab
+	*m = *NewConfigureGroupModel(gf, m.services, m.width, m.height)
+	m.activeTab = activeTab
+
+	comps := m.fieldComponents[m.activeTab]
+	if len(comps) == 0 {
+		return nil
+	}
+	return comps[0].Focus()
+}
+
+func (m ConfigureGroupModel) renderReloadPrompt() string {
+	lines := []string{
+		style.WarningMessageStyle.Render("Group file changed on disk"),
+		"",
+		m.group.Path,
+		"was modified outside of the editor while you have unsaved edits.",
+		"",
+	}
+	keys := keymap.Keys
+	for _, b := range []key.Binding{keys.Merge, keys.Reload, keys.Keep} {
+		lines = append(lines, fmt.Sprintf("[%s] %s", keymap.Name(b), b.Help().Desc))
+	}
+	return style.StyleModalBox(strings.Join(lines, "\n"), m.width, m.height)
+}
@@ -0,0 +1,175 @@
+package configuregroup
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/artemlive/gh-crossplane/internal/keymap"
+	"github.com/artemlive/gh-crossplane/internal/reponame"
+	"github.com/artemlive/gh-crossplane/internal/ui/field"
+	ui "github.com/artemlive/gh-crossplane/internal/ui/shared"
+	"github.com/artemlive/gh-crossplane/internal/ui/style"
+	"github.com/charmbracelet/bubbles/v2/key"
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
+)
+
+// groupRename asks for the new name of the group and how to rename it.
+// The group is saved with the new name right away.
+type groupRename struct {
+	name          *field.TextInputComponent
+	renameFile    bool
+	externalNames bool
+	fields        []field.FieldComponent
+	focused       int
+	message       ui.Message
+}
+
+// openGroupRename shows the rename prompt filled with the current name
+func (m *ConfigureGroupModel) openGroupRename() tea.Cmd {
+	p := &groupRename{
+		renameFile:    true,
+		externalNames: reponame.MissingExternalNames(m.group.Manifest) > 0,
+	}
+	p.name = field.NewTextInputComponent("New name", nil)
+	p.name.SetValue(m.group.Manifest.Metadata.Name)
+	p.fields = []field.FieldComponent{
+		p.name,
+		field.NewCheckboxComponent("Rename the file and its kustomization references", &p.renameFile),
+		field.NewCheckboxComponent(fmt.Sprintf("Set %s on the repositories", reponame.ExternalNameAnnotation), &p.externalNames),
+	}
+	m.groupRename = p
+	return p.name.Focus()
+}
+
+// handleGroupRename moves between the fields, enter renames the group
+func (m *ConfigureGroupModel) handleGroupRename(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	p := m.groupRename
+	keys := keymap.Keys
+	switch {
+	case key.Matches(msg, keys.Back):
+		m.groupRename = nil
+		return m, nil
+	case key.Matches(msg, keys.Select):
+		return m, m.applyGroupRename()
+	case key.Matches(msg, keys.NextField, keys.FieldDown):
+		return m, p.focus(p.focused + 1)
+	case key.Matches(msg, keys.PrevField, keys.FieldUp):
+		return m, p.focus(p.focused - 1)
+	}
+	p.message = ui.Message{}
+	if p.focused > 0 && !key.Matches(msg, keys.Toggle) {
+		// the checkboxes would move the focus themselves on j/k
+		return m, nil
+	}
+	_, cmd := p.fields[p.focused].Update(msg, ui.ModeEditing)
+	return m, cmd
+}
+
+func (p *groupRename) focus(i int) tea.Cmd {
+	p.fields[p.focused].Blur()
+	p.focused = (i + len(p.fields)) % len(p.fields)
+	return p.fields[p.focused].Focus()
+}
+
+// applyGroupRename saves the group with the new name, including the unsaved edits
+func (m *ConfigureGroupModel) applyGroupRename() tea.Cmd {
+	p := m.groupRename
+	name := strings.TrimSpace(p.name.Value())
+	oldName, oldPath := m.group.Title(), m.group.Path
+	if name == oldName && !p.renameFile && !p.externalNames {
+		m.groupRename = nil
+		return nil
+	}
+
+	gf := *m.group
+	adopted := 0
+	if p.externalNames {
+		adopted = reponame.SetExternalNames(&gf.Manifest)
+	}
+	res, err := m.services.Loader.RenameGroup(&gf, name, p.renameFile)
+	if res == nil {
+		p.message = ui.ErrorMessage(err.Error())
+		return nil
+	}
+
+	m.groupRename = nil
+	cmd := m.replaceGroup(&gf)
+	if err != nil {
+		m.message = ui.ErrorMessage(err.Error())
+		return cmd
+	}
+
+	done := []string{fmt.Sprintf("Group '%s' renamed to '%s'", oldName, name)}
+	if gf.Path != oldPath {
+		done = append(done, fmt.Sprintf("moved to %s", filepath.Base(gf.Path)))
+	}
+	for _, k := range res.Kustomizations {
+		done = append(done, fmt.Sprintf("updated %s", filepath.Base(k)))
+	}
+	if adopted > 0 {
+		done = append(done, fmt.Sprintf("set the external name of %d repo(s)", adopted))
+	}
+	text := strings.Join(done, ", ") + "."
+	if gf.Path != oldPath {
+		// the commit of the editor only stages the group files
+		text += fmt.Sprintf(" Commit the removal of %s and the kustomization with git.", filepath.Base(oldPath))
+	}
+	m.message = ui.InfoMessage(text)
+	return cmd
+}
+
+func (m ConfigureGroupModel) renderGroupRename() (string, *tea.Cursor) {
+	p := m.groupRename
+	var layers []*lipgloss.Layer
+	var cursor *tea.Cursor
+	layoutY := 0
+
+	header := []string{
+		style.LabelStyle.Render(fmt.Sprintf("Rename group '%s'", m.group.Title())),
+		style.InactiveTextStyle.Render(m.group.Path),
+		"",
+	}
+	layers = append(layers, lipgloss.NewLayer(ui.JoinVertical(header)).Y(layoutY))
+	layoutY += len(header)
+
+	for i, f := range p.fields {
+		view := f.View()
+		layers = append(layers, lipgloss.NewLayer(view).Y(layoutY))
+		if i == p.focused {
+			if cur := p.name.Cursor(); i == 0 && cur != nil {
+				cursor = tea.NewCursor(f.CursorOffset()+cur.X, layoutY+cur.Y)
+			}
+		}
+		layoutY += lipgloss.Height(view)
+	}
+	layoutY++
+
+	var warnings []string
+	for _, w := range reponame.GroupRenameWarnings(m.group.Manifest) {
+		warnings = append(warnings, style.WarningMessageStyle.Render("! "+w))
+	}
+	if m.group.Modified() {
+		warnings = append(warnings, style.InactiveTextStyle.Render("The unsaved edits are saved with the new name."))
+	}
+	warnings = append(warnings, "", style.InactiveTextStyle.Render(keymap.Hints(renameKeys()...)))
+	view := lipgloss.NewStyle().Width(max(m.width-2, 40)).Render(ui.JoinVertical(warnings))
+	layers = append(layers, lipgloss.NewLayer(view).Y(layoutY))
+	layoutY += lipgloss.Height(view)
+
+	if p.message.Msg != "" {
+		layers = append(layers, lipgloss.NewLayer("\n"+ui.FormatMessage(p.message)).Y(layoutY))
+	}
+	return lipgloss.NewCanvas(layers...).Render(), cursor
+}
+
+// renameKeys are the keys of the rename prompt
+func renameKeys() []key.Binding {
+	keys := keymap.Keys
+	return []key.Binding{
+		keys.NextField, keys.PrevField, keys.Toggle,
+		keymap.Describe(keys.Select, "rename"),
+		keymap.Describe(keys.Back, "cancel"),
+	}
+}
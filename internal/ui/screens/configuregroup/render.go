@@ -0,0 +1,64 @@
+package configuregroup
+
+import (
+	"strings"
+
+	"github.com/artemlive/gh-crossplane/internal/keymap"
+	"github.com/artemlive/gh-crossplane/internal/render"
+	ui "github.com/artemlive/gh-crossplane/internal/ui/shared"
+	"github.com/artemlive/gh-crossplane/internal/ui/style"
+	"github.com/charmbracelet/bubbles/v2/key"
+	"github.com/charmbracelet/bubbles/v2/viewport"
+	tea "github.com/charmbracelet/bubbletea/v2"
+)
+
+// openRenderPreview shows the managed resources the group produces,
+// including the unsaved edits
+func (m *ConfigureGroupModel) openRenderPreview() {
+	var sb strings.Builder
+	if err := render.Encode(&sb, render.Group(m.group.Manifest)); err != nil {
+		m.message = ui.ErrorMessage("Error rendering the managed resources: " + err.Error())
+		return
+	}
+
+	vp := viewport.New()
+	m.sizeViewport(&vp)
+	vp.KeyMap = keymap.Viewport()
+	vp.SetContent(sb.String())
+	m.preview = &vp
+}
+
+// sizeViewport fits a preview to the screen, leaving room for the title and the help line
+func (m *ConfigureGroupModel) sizeViewport(vp *viewport.Model) {
+	h, v := style.AppStyle.GetFrameSize()
+	vp.SetWidth(max(m.width-h, 20))
+	vp.SetHeight(max(m.height-v-2, 5))
+}
+
+// handleRenderPreview scrolls the preview, esc and q close it
+func (m *ConfigureGroupModel) handleRenderPreview(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	keys := keymap.Keys
+	switch {
+	case key.Matches(msg, keys.Back, keys.Quit, keys.Render):
+		m.preview = nil
+		return m, nil
+	}
+	vp, cmd := m.preview.Update(msg)
+	m.preview = &vp
+	return m, cmd
+}
+
+func (m ConfigureGroupModel) renderPreviewView() string {
+	title := style.LabelStyle.Render("Managed resources of group '" + m.group.Title() + "'")
+	help := style.InactiveTextStyle.Render(keymap.Hints(previewKeys()...))
+	return style.AppStyle.Render(ui.JoinVertical([]string{title, m.preview.View(), help}))
+}
+
+// previewKeys are the keys of the preview of the managed resources
+func previewKeys() []key.Binding {
+	keys := keymap.Keys
+	return []key.Binding{
+		keymap.Describe(keys.Up, "scroll up"), keymap.Describe(keys.Down, "scroll down"),
+		keymap.Describe(keys.Back, "close"),
+	}
+}
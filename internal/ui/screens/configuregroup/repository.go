@@ -0,0 +1,217 @@
+package configuregroup
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/artemlive/gh-crossplane/internal/domain"
+	"github.com/artemlive/gh-crossplane/internal/keymap"
+	"github.com/artemlive/gh-crossplane/internal/manifest"
+	"github.com/artemlive/gh-crossplane/internal/policy"
+	"github.com/artemlive/gh-crossplane/internal/ui/field"
+	ui "github.com/artemlive/gh-crossplane/internal/ui/shared"
+	"github.com/artemlive/gh-crossplane/internal/ui/style"
+	"github.com/charmbracelet/bubbles/v2/key"
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// repoEdit is set when the model edits a single repository of the group.
+// The fields edit a copy, which replaces the repository of the group when
+// the edits are applied, so cancelling leaves the group as it was.
+type repoEdit struct {
+	index int
+	copy  *domain.Repository
+	// standalone editors are opened from the repository picker and save the group
+	// file on apply, the others only change the group of the editor they were opened from
+	standalone bool
+	// discard is set when esc was pressed with unapplied edits
+	discard bool
+}
+
+// NewRepositoryEditor opens the repository of the group on its own, the group
+// file is saved when the edits are applied
+func NewRepositoryEditor(group *manifest.GroupFile, repoName string, services ui.Services, width, height int) (*ConfigureGroupModel, error) {
+	// the edits must not reach the loaded copy before they are saved
+	gf := *group
+	gf.Manifest.Spec.Repositories = slices.Clone(group.Manifest.Spec.Repositories)
+
+	idx := slices.IndexFunc(gf.Manifest.Spec.Repositories, func(r domain.Repository) bool { return r.Name == repoName })
+	if idx < 0 {
+		return nil, fmt.Errorf("repository '%s' not found in group '%s'", repoName, group.Title())
+	}
+	return newRepositoryModel(&gf, idx, services, width, height, true), nil
+}
+
+// openRepository opens the editor of the repository at the index over the group editor
+func (m *ConfigureGroupModel) openRepository(index int) tea.Cmd {
+	editor := newRepositoryModel(m.group, index, m.services, m.width, m.height, false)
+	m.modal = editor
+	// the ticks of the group editor reach it, only the first field needs the focus
+	if comps := editor.fieldComponents[0]; len(comps) > 0 {
+		return comps[0].Focus()
+	}
+	return nil
+}
+
+func newRepositoryModel(group *manifest.GroupFile, index int, services ui.Services, width, height int, standalone bool) *ConfigureGroupModel {
+	repo := group.Manifest.Spec.Repositories[index].Clone()
+	m := ConfigureGroupModel{
+		tabs:     services.Layout.For(group.Manifest).RepositoryTabs,
+		group:    group,
+		width:    width,
+		height:   height,
+		services: services,
+		repoEdit: &repoEdit{index: index, copy: &repo, standalone: standalone},
+	}
+	m.tabHandlers = make([]TabHandler, len(m.tabs))
+	for i, tab := range m.tabs {
+		components := field.GenerateComponents(&repo, tab.Fields, tab.Labels)
+		m.setSources(components)
+		m.fieldComponents = append(m.fieldComponents, components)
+		m.tabHandlers[i] = &GenericTabHandler{}
+	}
+	return &m
+}
+
+// repoModified reports whether the copy differs from the repository of the group,
+// the encoded forms are compared, so nil and empty values are treated the same way
+func (m *ConfigureGroupModel) repoModified() bool {
+	mine, err := yaml.Marshal(m.repoEdit.copy)
+	if err != nil {
+		return true
+	}
+	saved, err := yaml.Marshal(m.group.Manifest.Spec.Repositories[m.repoEdit.index])
+	return err != nil || !bytes.Equal(mine, saved)
+}
+
+// applyRepository replaces the repository of the group at the index
+func (m *ConfigureGroupModel) applyRepository(index int, repo domain.Repository) {
+	// the slice is shared with the loaded copy
+	repos := slices.Clone(m.group.Manifest.Spec.Repositories)
+	repos[index] = repo
+	m.group.Manifest.Spec.Repositories = repos
+}
+
+// handleRepositoryKeys handles the keys of the repository editor in the navigation mode,
+// it reports false for the keys left to the tab handlers
+func (m *ConfigureGroupModel) handleRepositoryKeys(msg tea.KeyMsg) (tea.Cmd, bool) {
+	e := m.repoEdit
+	keys := keymap.Keys
+	if !key.Matches(msg, keys.Back) {
+		e.discard = false
+	}
+	switch {
+	case key.Matches(msg, keys.Save):
+		repo := e.copy.Clone()
+		if !e.standalone {
+			// the group editor applies it
+			index := e.index
+			return func() tea.Msg { return ui.SwitchToGroupMsg{Index: index, Repo: &repo} }, true
+		}
+		m.applyRepository(e.index, repo)
+		m.saveRepository()
+		return nil, true
+	case key.Matches(msg, keys.Back):
+		if m.repoModified() && !e.discard {
+			e.discard = true
+			m.message = ui.WarningMessage(fmt.Sprintf("There are unapplied edits, press %s to apply or %s again to discard them.", keymap.Name(keys.Save), keymap.Name(keys.Back)))
+			return nil, true
+		}
+		if e.standalone {
+			return func() tea.Msg { return ui.SwitchToRepoPickerMsg{} }, true
+		}
+		return func() tea.Msg { return ui.SwitchToGroupMsg{} }, true
+	case key.Matches(msg, keys.OpenGroup):
+		if !e.standalone {
+			return nil, false
+		}
+		if m.repoModified() || m.group.Modified() {
+			m.message = ui.WarningMessage(fmt.Sprintf("Apply the edits with %s before opening the group.", keymap.Name(keys.Save)))
+			return nil, true
+		}
+		group, repo := m.group.Title(), m.repoEdit.copy.Name
+		return func() tea.Msg { return ui.SwitchToConfigureGroupMsg{GroupName: group, RepoName: repo} }, true
+	case key.Matches(msg, keys.Commit, keys.Propose, keys.Preset, keys.Rename):
+		// the group actions are left to the group editor
+		m.message = ui.WarningMessage(fmt.Sprintf("Open the group with %s to commit, propose, apply presets or rename it.", keymap.Name(keys.OpenGroup)))
+		return nil, true
+	}
+	return nil, false
+}
+
+// saveRepository writes the group of the standalone editor. Without the merge prompt
+// of the group editor a conflicting change on disk only fails the save.
+func (m *ConfigureGroupModel) saveRepository() {
+	err := m.services.Loader.SaveGroupFile(m.group)
+	if err != nil {
+		m.message = ui.ErrorMessage(fmt.Sprintf("Error saving group '%s': %s", m.group.Title(), err.Error()))
+		return
+	}
+	m.repoEdit.discard = false
+	m.message = ui.InfoMessage(fmt.Sprintf("Repository '%s' saved to group '%s'.", m.repoEdit.copy.Name, m.group.Title()))
+	if warnings := m.renameWarnings(); len(warnings) > 0 {
+		m.message = ui.WarningMessage(fmt.Sprintf("Repository '%s' saved with warnings: %s", m.repoEdit.copy.Name, strings.Join(warnings, "; ")))
+	}
+}
+
+// handleRepositoryApplied closes the repository editor, replacing the repository
+// of the group unless the edits were cancelled
+func (m *ConfigureGroupModel) handleRepositoryApplied(msg ui.SwitchToGroupMsg) {
+	m.modal = nil
+	if msg.Repo == nil || msg.Index >= len(m.group.Manifest.Spec.Repositories) {
+		return
+	}
+	m.applyRepository(msg.Index, *msg.Repo)
+	m.message = ui.InfoMessage(fmt.Sprintf("Repository '%s' updated, press %s to save.", msg.Repo.Name, keymap.Name(keymap.Keys.Save)))
+	if warnings := m.renameWarnings(); len(warnings) > 0 {
+		m.message = ui.WarningMessage(strings.Join(warnings, "; "))
+	}
+}
+
+// repositoryHeader names the repository and the group it belongs to
+func (m ConfigureGroupModel) repositoryHeader() string {
+	return ui.JoinVertical([]string{
+		style.LabelStyle.Render(fmt.Sprintf("Repository '%s'", m.repoEdit.copy.Name)),
+		style.InactiveTextStyle.Render(fmt.Sprintf("Group '%s', %s", m.group.Title(), m.group.Path)),
+	})
+}
+
+// effectiveLines lists the settings the repository ends up with,
+// the group settings with the repository overrides applied
+func (m ConfigureGroupModel) effectiveLines() []string {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(domain.Effective(m.group.Manifest.Spec, *m.repoEdit.copy)); err != nil {
+		return []string{err.Error()}
+	}
+	// the effective spec has no repositories
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	return slices.DeleteFunc(lines, func(l string) bool { return strings.HasPrefix(l, "repositories:") })
+}
+
+// repoViolations returns the violations of the edited repository
+func (m *ConfigureGroupModel) repoViolations() []policy.Violation {
+	g := m.group.Manifest
+	g.Spec.Repositories = slices.Clone(g.Spec.Repositories)
+	g.Spec.Repositories[m.repoEdit.index] = *m.repoEdit.copy
+	return slices.DeleteFunc(m.services.Policies.Evaluate(g), func(v policy.Violation) bool {
+		return v.Repo != m.repoEdit.copy.Name
+	})
+}
+
+func (m ConfigureGroupModel) repositoryStatusText() string {
+	return "[REPO] " + keymap.Hints(m.repositoryKeys()...)
+}
+
+// repositoryKeys are the keys of the repository editor
+func (m ConfigureGroupModel) repositoryKeys() []key.Binding {
+	keys := keymap.Keys
+	if m.repoEdit.standalone {
+		return []key.Binding{keys.Edit, keys.Save, keys.OpenGroup, keys.Render, keymap.Describe(keys.Back, "cancel"), keymap.Describe(keys.Help, "all keys")}
+	}
+	return []key.Binding{keys.Edit, keymap.Describe(keys.Save, "apply"), keys.Render, keymap.Describe(keys.Back, "cancel"), keymap.Describe(keys.Help, "all keys")}
+}
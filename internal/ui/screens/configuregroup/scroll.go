@@ -0,0 +1,169 @@
+package configuregroup
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/artemlive/gh-crossplane/internal/ui/field"
+	ui "github.com/artemlive/gh-crossplane/internal/ui/shared"
+	"github.com/artemlive/gh-crossplane/internal/ui/style"
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
+)
+
+// minBodyHeight is the number of lines the fields keep on a very short terminal
+const minBodyHeight = 3
+
+// body is the rendered fields of the active tab, one string per line
+type body struct {
+	lines []string
+	// side are the lines of the previews shown beside the fields, nil if they are below
+	side []string
+
+	// focusTop and focusBottom are the first and the last lines of the focused field
+	focusTop, focusBottom int
+	// focusLine is the line of the focused item or of the cursor in the field, -1 if none
+	focusLine int
+	// cursor is positioned in the lines of the body, nil if none
+	cursor *tea.Cursor
+}
+
+// renderBody renders the fields of the active tab. The previews go beside the
+// fields if the terminal is wide enough for both, below them otherwise.
+func (m *ConfigureGroupModel) renderBody() body {
+	res := m.tabHandlers[m.activeTab].Render(m)
+	b := body{focusLine: -1}
+
+	for _, rc := range res.Components {
+		start := len(b.lines)
+		b.lines = append(b.lines, rc.Lines...)
+		if !rc.Component.IsFocused() {
+			continue
+		}
+		b.focusTop, b.focusBottom = start, len(b.lines)-1
+		if fl, ok := rc.Component.(field.FocusLiner); ok {
+			b.focusLine = start + fl.FocusLine()
+		}
+		if c, ok := rc.Component.(field.Cursorer); ok {
+			if cursor := c.Cursor(); cursor != nil {
+				b.cursor = tea.NewCursor(rc.Component.CursorOffset()+cursor.X, start+cursor.Y)
+				b.focusLine = start + cursor.Y
+			}
+		}
+	}
+	for _, extra := range res.ExtraLines {
+		b.lines = append(b.lines, strings.Split(extra, "\n")...)
+	}
+
+	var side []string
+	for _, preview := range res.SideLines {
+		side = append(side, strings.Split(preview, "\n")...)
+	}
+	if m.width > 0 && maxWidth(b.lines)+1+maxWidth(side) <= m.width {
+		b.side = side
+	} else {
+		b.lines = append(b.lines, side...)
+	}
+	return b
+}
+
+// bodyHeight returns the number of lines left for the fields between the tabs
+// and the status bar, 0 if the size of the terminal isn't known
+func (m *ConfigureGroupModel) bodyHeight() int {
+	if m.height <= 0 {
+		return 0
+	}
+	return max(m.height-lipgloss.Height(m.renderHeader())-lipgloss.Height(m.renderFooter()), minBodyHeight)
+}
+
+// visible returns the number of lines of the fields shown in the height, one
+// line is taken by the scroll position when they don't fit
+func (b body) visible(height int) int {
+	if height <= 0 || len(b.lines) <= height {
+		return len(b.lines)
+	}
+	return height - 1
+}
+
+// scrollTo returns the first line shown, moved as little as possible from the
+// offset to show the focused field. The top of a field taller than the screen
+// is shown, unless its focused item or its cursor is further down.
+func (b body) scrollTo(offset, visible int) int {
+	if visible >= len(b.lines) {
+		return 0
+	}
+	offset = reveal(offset, b.focusTop, min(b.focusBottom, b.focusTop+visible-1), visible)
+	if b.focusLine >= 0 {
+		offset = reveal(offset, b.focusLine, b.focusLine, visible)
+	}
+	return max(0, min(offset, len(b.lines)-visible))
+}
+
+// reveal moves the offset to show the lines from top to bottom
+func reveal(offset, top, bottom, visible int) int {
+	if bottom >= offset+visible {
+		offset = bottom - visible + 1
+	}
+	if top < offset {
+		offset = top
+	}
+	return offset
+}
+
+// keepFocusVisible scrolls the fields of the active tab to the focused one
+func (m *ConfigureGroupModel) keepFocusVisible() {
+	b := m.renderBody()
+	m.scroll = b.scrollTo(m.scroll, b.visible(m.bodyHeight()))
+}
+
+// renderFields renders the part of the fields fitting the screen and the
+// previews beside them, the cursor is moved to the screen starting at the line y
+func (m *ConfigureGroupModel) renderFields(y int) (string, *tea.Cursor) {
+	b := m.renderBody()
+	height := m.bodyHeight()
+	visible := b.visible(height)
+	offset := b.scrollTo(m.scroll, visible)
+
+	lines := slices.Clone(b.lines[offset : offset+visible])
+	if visible < len(b.lines) {
+		position := fmt.Sprintf("lines %d-%d of %d", offset+1, offset+visible, len(b.lines))
+		lines = append(lines, style.InactiveTextStyle.Render(position))
+	}
+
+	var cursor *tea.Cursor
+	if b.cursor != nil && b.cursor.Y >= offset && b.cursor.Y < offset+visible {
+		cursor = b.cursor
+		cursor.Y += y - offset
+	}
+
+	view := ui.JoinVertical(lines)
+	if len(b.side) > 0 {
+		side := b.side
+		if height > 0 && len(side) > height {
+			side = side[:height]
+		}
+		// the fields keep their width while scrolling, the previews don't move
+		left := max(maxWidth(b.lines), maxWidth(lines)) + 1
+		view = lipgloss.JoinHorizontal(lipgloss.Top, lipgloss.NewStyle().Width(left).Render(view), ui.JoinVertical(side))
+	}
+	return view, cursor
+}
+
+// wrap breaks the lines wider than the terminal, so the height of the header
+// and the footer is known
+func (m *ConfigureGroupModel) wrap(s string) string {
+	if m.width <= 0 || maxWidth(strings.Split(s, "\n")) <= m.width {
+		return s
+	}
+	return lipgloss.NewStyle().Width(m.width).Render(s)
+}
+
+// maxWidth returns the width of the widest line
+func maxWidth(lines []string) int {
+	w := 0
+	for _, l := range lines {
+		w = max(w, lipgloss.Width(l))
+	}
+	return w
+}
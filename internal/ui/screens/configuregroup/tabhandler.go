@@ -7,10 +7,11 @@ import (
 
 	"github.com/artemlive/gh-crossplane/debug"
 	"github.com/artemlive/gh-crossplane/internal/domain"
+	"github.com/artemlive/gh-crossplane/internal/keymap"
 	"github.com/artemlive/gh-crossplane/internal/ui/field"
-	"github.com/artemlive/gh-crossplane/internal/ui/screens/configurerepo"
 	ui "github.com/artemlive/gh-crossplane/internal/ui/shared"
 	"github.com/artemlive/gh-crossplane/internal/ui/style"
+	"github.com/charmbracelet/bubbles/v2/key"
 	tea "github.com/charmbracelet/bubbletea/v2"
 )
 
@@ -24,15 +25,26 @@ type GenericTabHandler struct{}
 
 func (h GenericTabHandler) Render(m *ConfigureGroupModel) field.RenderResult {
 	var result field.RenderResult
+	violations := m.groupViolations()
 
 	for _, comp := range m.fieldComponents[m.activeTab] {
 		lines := strings.Split(comp.View(), "\n")
+		// the policy violations go right below the field causing them
+		lines = append(lines, violationLines(fieldViolations(violations, comp))...)
 		result.Components = append(result.Components, field.RenderedComponent{
 			Component: comp,
 			Lines:     lines,
 		})
 	}
 
+	if other := violationLines(m.unplacedViolations(violations)); len(other) > 0 {
+		result.ExtraLines = append(result.ExtraLines, "", style.LabelStyle.Render("Policy violations:"))
+		result.ExtraLines = append(result.ExtraLines, other...)
+	}
+	if m.repoEdit != nil {
+		effective := ui.JoinVertical(append([]string{style.LabelStyle.Render("Effective settings:")}, m.effectiveLines()...))
+		result.SideLines = append(result.SideLines, style.RepoPreviewStyle.Render(effective))
+	}
 	return result
 }
 func (h GenericTabHandler) Update(m *ConfigureGroupModel, msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -65,18 +77,19 @@ func (h GenericTabHandler) Update(m *ConfigureGroupModel, msg tea.Msg) (tea.Mode
 		return m.handleNextField()
 
 	case tea.KeyMsg:
+		keys := keymap.Keys
 		switch m.mode {
 		case ui.ModeNavigation:
-			switch msg.String() {
-			case "tab", "down", "j":
+			switch {
+			case key.Matches(msg, keys.NextField, keys.Down):
 				return m.handleNextField()
-			case "shift+tab", "up", "k":
+			case key.Matches(msg, keys.PrevField, keys.Up):
 				return m.handlePrevField()
-			case "left", "h":
+			case key.Matches(msg, keys.Left):
 				return m.switchTab(-1)
-			case "right", "l":
+			case key.Matches(msg, keys.Right):
 				return m.switchTab(1)
-			case "enter", "i":
+			case key.Matches(msg, keys.Edit):
 				var cmd tea.Cmd
 				if comps := m.fieldComponents[m.activeTab]; len(comps) > 0 {
 					// blur the old focused field
@@ -87,21 +100,22 @@ func (h GenericTabHandler) Update(m *ConfigureGroupModel, msg tea.Msg) (tea.Mode
 					m.mode = ui.ModeEditing
 				}
 				return m, cmd
-			case "ctrl+s":
-				if err := m.loader.SaveGroupFile(m.group); err != nil {
-					m.message = ui.ErrorMessage(fmt.Sprintf("Error saving group '%s': %s", m.group.Title(), err.Error()))
-				} else {
-					m.message = ui.InfoMessage(fmt.Sprintf("Group '%s' saved successfully.", m.group.Title()))
-				}
+			case key.Matches(msg, keys.Save):
+				_ = m.saveGroup()
+				return m, nil
+			case key.Matches(msg, keys.Commit):
+				m.commitChanges()
 				return m, nil
-			case "esc":
+			case key.Matches(msg, keys.Propose):
+				return m, m.proposeChanges()
+			case key.Matches(msg, keys.Back):
 				return m, func() tea.Msg { return ui.SwitchToMenuMsg{} }
-			case "q":
+			case key.Matches(msg, keys.Quit):
 				return m, tea.Quit
 			}
 
 		case ui.ModeEditing:
-			if msg.String() == "esc" {
+			if key.Matches(msg, keys.Back) {
 				return m.Update(field.FieldDoneMsg{})
 			}
 		}
@@ -120,9 +134,14 @@ func (h GenericTabHandler) Update(m *ConfigureGroupModel, msg tea.Msg) (tea.Mode
 func (h GenericTabHandler) StatusBarText(m *ConfigureGroupModel) string {
 	switch m.mode {
 	case ui.ModeNavigation:
-		return style.ConfigureGroupStatusStyleNavigation.Render("[NAV Mode] Up/Down Left/Right to navigate, Enter to edit, Ctrl+s to save, q to quit")
+		if m.repoEdit != nil {
+			return style.ConfigureGroupStatusStyleNavigation.Render(m.repositoryStatusText())
+		}
+		keys := keymap.Keys
+		return style.ConfigureGroupStatusStyleNavigation.Render("[NAV Mode] " + keymap.Hints(keys.Edit, keys.Save, keys.Commit, keys.Propose, keys.Quit, keymap.Describe(keys.Help, "all keys")))
 	case ui.ModeEditing:
-		return style.ConfigureGroupStatusStyleEditing.Render("[EDT Mode] Press Esc or Enter to finish")
+		keys := keymap.Keys
+		return style.ConfigureGroupStatusStyleEditing.Render(fmt.Sprintf("[EDT Mode] Press %s or %s to finish", keymap.Name(keys.Back), keymap.Name(keys.Done)))
 	}
 	return ""
 }
@@ -142,16 +161,23 @@ func (h *RepositoryTabHandler) Render(m *ConfigureGroupModel) field.RenderResult
 		if comp.IsFocused() {
 			if pv, ok := comp.(field.PreviewableComponent); ok {
 				preview := style.RepoPreviewStyle.Render(strings.Join(pv.PreviewLines(), "\n"))
-				result.ExtraLines = append(result.ExtraLines, preview)
+				result.SideLines = append(result.SideLines, preview)
 			}
 		}
+		if rc, ok := comp.(*field.RepositoriesComponent); ok {
+			result.ExtraLines = append(result.ExtraLines, m.repoViolationLines(rc.Selected())...)
+		}
 	}
 
-	//TODO: modal drawing
 	return result
 }
 
 func (h *RepositoryTabHandler) Update(m *ConfigureGroupModel, msg tea.Msg) (tea.Model, tea.Cmd) {
+	// the repository editor closes itself with this message
+	if msg, ok := msg.(ui.SwitchToGroupMsg); ok {
+		m.handleRepositoryApplied(msg)
+		return m, nil
+	}
 	if m.isModalOpen() {
 		newModel, cmd := m.modal.Update(msg)
 		if vm, ok := newModel.(ui.ViewableModel); ok {
@@ -162,10 +188,6 @@ func (h *RepositoryTabHandler) Update(m *ConfigureGroupModel, msg tea.Msg) (tea.
 
 	switch msg := msg.(type) {
 
-	case ui.SwitchToGroupMsg:
-		m.modal = nil
-		return m, nil
-
 	case field.FieldDoneMsg:
 		if comps := m.fieldComponents[m.activeTab]; len(comps) > 0 {
 			comps[m.focusedIndex].Blur()
@@ -181,24 +203,29 @@ func (h *RepositoryTabHandler) Update(m *ConfigureGroupModel, msg tea.Msg) (tea.
 	case field.FieldOpenMsg:
 		switch v := msg.Value.(type) {
 		case *domain.Repository:
-			m.modal = configurerepo.New(v)
+			for i := range m.group.Manifest.Spec.Repositories {
+				if &m.group.Manifest.Spec.Repositories[i] == v {
+					return m, m.openRepository(i)
+				}
+			}
 		default:
 			m.message = ui.ErrorMessage("Invalid repository value type in FieldOpenMsg")
 		}
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "ctrl+s":
-			err := m.loader.SaveGroupFile(m.group)
-			if err != nil {
-				m.message = ui.ErrorMessage("Error saving group: " + err.Error())
-			} else {
-				m.message = ui.InfoMessage(fmt.Sprintf("Group '%s' saved successfully.", m.group.Title()))
-			}
+		keys := keymap.Keys
+		switch {
+		case key.Matches(msg, keys.Save):
+			_ = m.saveGroup()
+			return m, nil
+		case key.Matches(msg, keys.Commit):
+			m.commitChanges()
 			return m, nil
-		case "esc":
+		case key.Matches(msg, keys.Propose):
+			return m, m.proposeChanges()
+		case key.Matches(msg, keys.Back):
 			return m, func() tea.Msg { return ui.SwitchToMenuMsg{} }
 
-		case "q":
+		case key.Matches(msg, keys.Quit):
 			return m, tea.Quit
 		}
 	}
@@ -215,14 +242,15 @@ func (h *RepositoryTabHandler) Update(m *ConfigureGroupModel, msg tea.Msg) (tea.
 
 	// Only handle navigation *after* component had a chance
 	if keyMsg, ok := msg.(tea.KeyMsg); ok {
-		switch keyMsg.String() {
-		case "up", "k":
+		keys := keymap.Keys
+		switch {
+		case key.Matches(keyMsg, keys.Up):
 			return m.handlePrevField()
-		case "down", "j":
+		case key.Matches(keyMsg, keys.Down):
 			return m.handleNextField()
-		case "left", "h":
+		case key.Matches(keyMsg, keys.Left):
 			return m.switchTab(-1)
-		case "right", "l":
+		case key.Matches(keyMsg, keys.Right):
 			return m.switchTab(1)
 		}
 	}
@@ -230,5 +258,6 @@ func (h *RepositoryTabHandler) Update(m *ConfigureGroupModel, msg tea.Msg) (tea.
 	return m, nil
 }
 func (h RepositoryTabHandler) StatusBarText(m *ConfigureGroupModel) string {
-	return "[REPO Mode] Up/Down to navigate, Ctrl+s to save, q to quit"
+	keys := keymap.Keys
+	return "[REPO Mode] " + keymap.Hints(keymap.Describe(keys.Select, "edit the repository"), keys.Save, keys.Commit, keys.Propose, keys.Quit, keymap.Describe(keys.Help, "all keys"))
 }
@@ -0,0 +1,25 @@
+package configuregroup
+
+import (
+	"fmt"
+
+	"github.com/artemlive/gh-crossplane/internal/keymap"
+	ui "github.com/artemlive/gh-crossplane/internal/ui/shared"
+	tea "github.com/charmbracelet/bubbletea/v2"
+)
+
+// openXRDForm switches to the form built from the XRD, which edits the fields
+// the domain types don't know. The form reads the group file, so the edits
+// have to be saved first.
+func (m *ConfigureGroupModel) openXRDForm() tea.Cmd {
+	switch {
+	case m.services.XRD == nil:
+		m.message = ui.WarningMessage("There is no XRD, start the editor with -xrd to use its form")
+		return nil
+	case m.group.Modified():
+		m.message = ui.WarningMessage(fmt.Sprintf("Save the group with %s before opening the XRD form", keymap.Name(keymap.Keys.Save)))
+		return nil
+	}
+	name := m.group.Manifest.Metadata.Name
+	return func() tea.Msg { return ui.SwitchToXRDFormMsg{GroupName: name} }
+}
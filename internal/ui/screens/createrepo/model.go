@@ -1,12 +1,16 @@
 package createrepo
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/artemlive/gh-crossplane/debug"
+	"github.com/artemlive/gh-crossplane/internal/keymap"
+	"github.com/artemlive/gh-crossplane/internal/reponame"
 	"github.com/artemlive/gh-crossplane/internal/ui/field"
 	ui "github.com/artemlive/gh-crossplane/internal/ui/shared"
 	"github.com/artemlive/gh-crossplane/internal/ui/style"
+	"github.com/charmbracelet/bubbles/v2/key"
 	tea "github.com/charmbracelet/bubbletea/v2"
 	"github.com/charmbracelet/lipgloss/v2"
 )
@@ -16,8 +20,21 @@ type CreateRepoModel struct {
 	repoName    string
 	description string
 
-	message string
+	services ui.Services
+	owner    string // organization the name is checked in, empty skips the GitHub check
+	checking bool   // the name is being looked up on GitHub
+	// confirmed is a name the user chose to keep despite the warning
+	confirmed string
+
+	message ui.Message
 	input   *field.TextInputComponent
+	// presets is the preset picker, nil if there are no presets
+	presets *field.ListComponent
+}
+
+type availabilityMsg struct {
+	availability reponame.Availability
+	err          error
 }
 
 type TeamPermission struct {
@@ -28,18 +45,25 @@ type TeamPermission struct {
 const (
 	StepRepoName = iota
 	StepDescription
+	StepPreset
 	StepTeamName
 	StepPermission
 	StepAskAddMore
 	StepDone
 )
 
-func NewCreateRepoModel() CreateRepoModel {
+func NewCreateRepoModel(services ui.Services, owner string) CreateRepoModel {
 	ti := field.NewTextInputComponent("Repository Name", nil)
-	return CreateRepoModel{
-		step:  StepRepoName,
-		input: ti,
+	m := CreateRepoModel{
+		step:     StepRepoName,
+		input:    ti,
+		services: services,
+		owner:    owner,
 	}
+	if len(services.Presets) > 0 {
+		m.presets = field.NewPresetListComponent(services.Presets)
+	}
+	return m
 }
 
 func (m CreateRepoModel) Init() tea.Cmd {
@@ -48,38 +72,43 @@ func (m CreateRepoModel) Init() tea.Cmd {
 
 func (m CreateRepoModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case availabilityMsg:
+		return m.handleAvailability(msg)
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "enter":
+		if m.checking {
+			return m, nil
+		}
+		if m.step == StepPreset {
+			return m.updatePreset(msg)
+		}
+		keys := keymap.Keys
+		switch {
+		case key.Matches(msg, keys.Select):
 			val := m.input.Value()
 			debug.Log.Printf("Input %v, val: %s", m.input, val)
 			if val == "" {
-				m.message = "please enter a value"
+				m.message = ui.WarningMessage("please enter a value")
 				return m, nil // Do nothing if input is empty
 			}
 
 			switch m.step {
 			case StepRepoName:
-				m.repoName = val
-				m.step = StepDescription
-				m.input.SetValue("") // Clear input for next step
+				return m.checkName(val)
 			case StepDescription:
 				m.description = val
-				m.step = StepDone
-				return m, func() tea.Msg {
-					return ui.SwitchToSelectGroupMsg{
-						RepoName:    m.repoName,
-						Description: m.description,
-					}
+				if m.presets != nil {
+					m.step = StepPreset
+					return m, nil
 				}
+				return m.done("")
 			}
-		case "esc":
+		case key.Matches(msg, keys.Back):
 			//TODO: switch between steps
 			return m, func() tea.Msg {
 				return ui.SwitchToMenuMsg{}
 			}
 		default:
-			m.message = ""
+			m.message = ui.Message{}
 		}
 	}
 	newInput, cmd := m.input.Update(msg, ui.ModeEditing)
@@ -88,6 +117,85 @@ func (m CreateRepoModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+func (m CreateRepoModel) updatePreset(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	keys := keymap.Keys
+	switch {
+	case key.Matches(msg, keys.Select):
+		preset := ""
+		if i := m.presets.Selected(); i > 0 {
+			preset = m.services.Presets[i-1].Name
+		}
+		return m.done(preset)
+	case key.Matches(msg, keys.Back):
+		m.step = StepDescription
+		return m, nil
+	}
+	_, cmd := m.presets.Update(msg, ui.ModeEditing)
+	return m, cmd
+}
+
+// done passes the new repository on to the group selection
+func (m CreateRepoModel) done(preset string) (tea.Model, tea.Cmd) {
+	m.step = StepDone
+	return m, func() tea.Msg {
+		return ui.SwitchToSelectGroupMsg{
+			RepoName:    m.repoName,
+			Description: m.description,
+			Preset:      preset,
+		}
+	}
+}
+
+// checkName makes sure the repository doesn't exist yet, first in the groups,
+// then on GitHub. A name already used on GitHub can be kept by pressing enter again.
+func (m CreateRepoModel) checkName(name string) (tea.Model, tea.Cmd) {
+	if a := reponame.Managed(m.services.Loader.Groups(), name); !a.Available() {
+		m.message = ui.ErrorMessage(a.Warning())
+		return m, nil
+	}
+	if name == m.confirmed || m.services.GitHub == nil || m.owner == "" {
+		return m.acceptName(name), nil
+	}
+
+	m.checking = true
+	m.message = ui.InfoMessage(fmt.Sprintf("Checking whether %s/%s exists...", m.owner, name))
+	gh, owner, groups := m.services.GitHub, m.owner, m.services.Loader.Groups()
+	return m, func() tea.Msg {
+		a, err := reponame.Check(gh, owner, groups, name)
+		return availabilityMsg{availability: a, err: err}
+	}
+}
+
+func (m CreateRepoModel) handleAvailability(msg availabilityMsg) (tea.Model, tea.Cmd) {
+	m.checking = false
+	name := msg.availability.Name
+	if name != m.input.Value() {
+		// the name was changed while checking
+		m.message = ui.Message{}
+		return m, nil
+	}
+
+	switch {
+	case msg.err != nil:
+		m.confirmed = name
+		m.message = ui.WarningMessage(fmt.Sprintf("Couldn't check the name: %s. Press enter to use it anyway.", msg.err))
+	case msg.availability.OnGitHub:
+		m.confirmed = name
+		m.message = ui.WarningMessage(msg.availability.Warning() + "\nUse 'Import repositories from GitHub' in the main menu, or press enter to add it anyway.")
+	default:
+		return m.acceptName(name), nil
+	}
+	return m, nil
+}
+
+func (m CreateRepoModel) acceptName(name string) CreateRepoModel {
+	m.repoName = name
+	m.step = StepDescription
+	m.message = ui.Message{}
+	m.input.SetValue("") // Clear input for next step
+	return m
+}
+
 func (m CreateRepoModel) View() (string, *tea.Cursor) {
 	var layers []*lipgloss.Layer
 	var globalCursor *tea.Cursor
@@ -105,6 +213,11 @@ func (m CreateRepoModel) View() (string, *tea.Cursor) {
 		prompt = "Done!"
 	}
 
+	if m.step == StepPreset {
+		help := style.InactiveTextStyle.Render(keymap.Hints(m.keys()...))
+		return ui.JoinVertical([]string{m.presets.View(), "", help}), nil
+	}
+
 	m.input.Focus()
 	promptLines := strings.Split(prompt, "\n")
 	layers = append(layers, lipgloss.NewLayer(prompt).Y(layoutY))
@@ -122,8 +235,8 @@ func (m CreateRepoModel) View() (string, *tea.Cursor) {
 	layoutY += len(inputLines)
 
 	// info message (if any)
-	if m.message != "" {
-		msg := style.InfoMessageStyle.Render(m.message)
+	if m.message.Msg != "" {
+		msg := ui.FormatMessage(m.message)
 		msgLines := strings.Split(msg, "\n")
 		layers = append(layers, lipgloss.NewLayer("\n\n"+msg).Y(layoutY))
 		layoutY += 2 + len(msgLines) //nolint
@@ -133,3 +246,16 @@ func (m CreateRepoModel) View() (string, *tea.Cursor) {
 	canvas := lipgloss.NewCanvas(layers...)
 	return canvas.Render(), globalCursor
 }
+
+// keys are the keys of the preset picker, or of the name and the description inputs
+func (m CreateRepoModel) keys() []key.Binding {
+	keys := keymap.Keys
+	if m.step == StepPreset {
+		return []key.Binding{keys.Up, keys.Down, keymap.Describe(keys.Select, "confirm"), keys.Back}
+	}
+	return []key.Binding{keymap.Describe(keys.Select, "next"), keymap.Describe(keys.Back, "main menu")}
+}
+
+func (m CreateRepoModel) KeyHelp() ([][]key.Binding, bool) {
+	return [][]key.Binding{m.keys()}, m.step != StepPreset
+}
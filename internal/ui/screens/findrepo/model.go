@@ -0,0 +1,153 @@
+package findrepo
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/artemlive/gh-crossplane/internal/domain"
+	"github.com/artemlive/gh-crossplane/internal/keymap"
+	"github.com/artemlive/gh-crossplane/internal/manifest"
+	ui "github.com/artemlive/gh-crossplane/internal/ui/shared"
+	"github.com/artemlive/gh-crossplane/internal/ui/style"
+	"github.com/charmbracelet/bubbles/v2/key"
+	"github.com/charmbracelet/bubbles/v2/list"
+	tea "github.com/charmbracelet/bubbletea/v2"
+)
+
+// Hit is a repository of a group, as a list item
+type Hit struct {
+	Repo  domain.Repository
+	Group string
+	Path  string
+}
+
+func (h Hit) Title() string {
+	return h.Repo.Name
+}
+
+func (h Hit) Description() string {
+	desc := fmt.Sprintf("group %s, %s", h.Group, filepath.Base(h.Path))
+	if h.Repo.Description != "" {
+		desc += " · " + h.Repo.Description
+	}
+	return desc
+}
+
+// FilterValue starts with the name, so the matched characters of the name are highlighted
+func (h Hit) FilterValue() string {
+	return strings.Join(append([]string{h.Repo.Name, h.Repo.Description}, h.Repo.Topics...), " ")
+}
+
+// Hits lists the repositories of all the groups
+func Hits(groups []manifest.GroupFile) []Hit {
+	var out []Hit
+	for _, g := range groups {
+		for _, r := range g.Manifest.Spec.Repositories {
+			out = append(out, Hit{Repo: r, Group: g.Manifest.Metadata.Name, Path: g.Path})
+		}
+	}
+	return out
+}
+
+// Mode is what the picked repository is opened in
+type Mode int
+
+const (
+	ModeSearch    Mode = iota // the group editor with the repository focused
+	ModeConfigure             // the editor of the repository
+)
+
+// FindRepoModel fuzzy searches the repositories of all the groups
+// by name, description and topics
+type FindRepoModel struct {
+	list list.Model
+	keys *keyMap
+	mode Mode
+}
+
+type keyMap struct {
+	open         key.Binding
+	returnToMenu key.Binding
+}
+
+func NewFindRepoModel(groups []manifest.GroupFile, mode Mode, width, height int) FindRepoModel {
+	openHelp, title := "open in group", "Search Repositories"
+	if mode == ModeConfigure {
+		openHelp, title = "configure", "Select Repository To Configure"
+	}
+	keys := &keyMap{
+		open:         keymap.Describe(keymap.Keys.Select, openHelp),
+		returnToMenu: keymap.Describe(keymap.Keys.Back, "return to main menu"),
+	}
+
+	h, v := style.AppStyle.GetFrameSize()
+	l := list.New(items(groups), style.ListDelegate(), width-h, height-v)
+	l.Title = title
+	l.SetStatusBarItemName("repository", "repositories")
+	l.Styles = style.ListStyles()
+	l.Help.Styles = style.HelpStyles()
+	l.KeyMap = keymap.List()
+	l.AdditionalFullHelpKeys = func() []key.Binding {
+		return []key.Binding{keys.open, keys.returnToMenu}
+	}
+	l.AdditionalShortHelpKeys = func() []key.Binding {
+		return []key.Binding{keymap.Describe(keymap.Keys.Help, "more")}
+	}
+	// start typing right away
+	l.SetFilterState(list.Filtering)
+	return FindRepoModel{list: l, keys: keys, mode: mode}
+}
+
+func items(groups []manifest.GroupFile) []list.Item {
+	var out []list.Item
+	for _, h := range Hits(groups) {
+		out = append(out, h)
+	}
+	return out
+}
+
+func (m FindRepoModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m FindRepoModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		h, v := style.AppStyle.GetFrameSize()
+		m.list.SetSize(msg.Width-h, msg.Height-v)
+	case ui.GroupsReloadedMsg:
+		return m, m.list.SetItems(items(msg.Groups))
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.open):
+			hit, ok := m.list.SelectedItem().(Hit)
+			if !ok {
+				return m, nil
+			}
+			if m.mode == ModeConfigure {
+				return m, func() tea.Msg {
+					return ui.SwitchToConfigureRepoMsg{GroupName: hit.Group, RepoName: hit.Repo.Name}
+				}
+			}
+			return m, func() tea.Msg {
+				return ui.SwitchToConfigureGroupMsg{GroupName: hit.Group, RepoName: hit.Repo.Name}
+			}
+		case key.Matches(msg, m.keys.returnToMenu) && m.list.FilterState() != list.Filtering:
+			return m, func() tea.Msg { return ui.SwitchToMenuMsg{} }
+		}
+	}
+
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(msg)
+	return m, cmd
+}
+
+// KeyHelp lists the keys of the list, the filter is typed while it's edited
+func (m FindRepoModel) KeyHelp() ([][]key.Binding, bool) {
+	return m.list.FullHelp(), m.list.FilterState() == list.Filtering
+}
+
+func (m FindRepoModel) View() (string, *tea.Cursor) {
+	return style.AppStyle.Render(m.list.View()), nil
+}
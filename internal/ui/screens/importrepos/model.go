@@ -0,0 +1,359 @@
+package importrepos
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/artemlive/gh-crossplane/internal/github"
+	"github.com/artemlive/gh-crossplane/internal/importer"
+	"github.com/artemlive/gh-crossplane/internal/keymap"
+	"github.com/artemlive/gh-crossplane/internal/ui/field"
+	ui "github.com/artemlive/gh-crossplane/internal/ui/shared"
+	"github.com/artemlive/gh-crossplane/internal/ui/style"
+	"github.com/artemlive/gh-crossplane/internal/util"
+	"github.com/charmbracelet/bubbles/v2/key"
+	"github.com/charmbracelet/bubbles/v2/list"
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
+)
+
+const (
+	StepOrg = iota
+	StepLoading
+	StepSelect
+	StepGroup
+	StepImporting
+	StepDone
+)
+
+type reposLoadedMsg struct {
+	repos []github.Repository
+	err   error
+}
+
+type settingsLoadedMsg struct {
+	live []importer.LiveRepository
+	err  error
+}
+
+// repoItem is a repository in the selection list
+type repoItem struct {
+	repo      github.Repository
+	managedBy string // group the repo is already in
+	selected  bool
+}
+
+func (i repoItem) Title() string {
+	check := " "
+	if i.selected {
+		check = "x"
+	}
+	return fmt.Sprintf("[%s] %s", check, i.repo.Name)
+}
+
+func (i repoItem) Description() string {
+	desc := util.IfEmpty(i.repo.Description, "no description")
+	if i.managedBy != "" {
+		desc = fmt.Sprintf("already in group '%s'", i.managedBy)
+	}
+	return fmt.Sprintf("%s · %s", i.repo.Visibility, desc)
+}
+
+func (i repoItem) FilterValue() string {
+	return i.repo.Name
+}
+
+type listKeyMap struct {
+	toggle       key.Binding
+	next         key.Binding
+	returnToMenu key.Binding
+}
+
+func newListKeyMap() *listKeyMap {
+	keys := keymap.Keys
+	return &listKeyMap{
+		toggle:       keymap.Describe(keys.Toggle, "select repo"),
+		next:         keymap.Describe(keys.Select, "import selected"),
+		returnToMenu: keymap.Describe(keys.Back, "return to main menu"),
+	}
+}
+
+// ImportModel lets the user pick repositories of an organization
+// and imports their live settings into a new or an existing group
+type ImportModel struct {
+	services ui.Services
+	step     int
+	org      string
+	group    string
+
+	input    *field.TextInputComponent
+	list     list.Model
+	keys     *listKeyMap
+	warnings []string
+	message  ui.Message
+
+	width  int
+	height int
+}
+
+func NewImportModel(services ui.Services, defaultOrg string, width, height int) *ImportModel {
+	input := field.NewTextInputComponent("Organization", nil)
+	input.SetPlaceholder("my-org")
+	if defaultOrg != "" {
+		input.SetValue(defaultOrg)
+	}
+
+	keys := newListKeyMap()
+	h, v := style.AppStyle.GetFrameSize()
+	repoList := list.New(nil, style.ListDelegate(), width-h, height-v)
+	repoList.Styles = style.ListStyles()
+	repoList.Help.Styles = style.HelpStyles()
+	repoList.KeyMap = keymap.List()
+	repoList.AdditionalFullHelpKeys = func() []key.Binding {
+		return []key.Binding{keys.toggle, keys.next, keys.returnToMenu}
+	}
+	repoList.AdditionalShortHelpKeys = func() []key.Binding {
+		return []key.Binding{keys.toggle, keys.next, keymap.Describe(keymap.Keys.Help, "more")}
+	}
+
+	return &ImportModel{
+		services: services,
+		step:     StepOrg,
+		input:    input,
+		list:     repoList,
+		keys:     keys,
+		width:    width,
+		height:   height,
+	}
+}
+
+func (m *ImportModel) Init() tea.Cmd {
+	return m.input.Focus()
+}
+
+func (m *ImportModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		h, v := style.AppStyle.GetFrameSize()
+		m.list.SetSize(msg.Width-h, msg.Height-v)
+
+	case reposLoadedMsg:
+		if msg.err != nil {
+			m.step = StepOrg
+			m.message = ui.ErrorMessage(fmt.Sprintf("Error listing repositories of '%s': %s", m.org, msg.err))
+			return m, nil
+		}
+		m.step = StepSelect
+		m.list.Title = fmt.Sprintf("Select repositories of '%s' to import", m.org)
+		return m, m.list.SetItems(m.repoItems(msg.repos))
+
+	case settingsLoadedMsg:
+		return m.handleSettingsLoaded(msg)
+
+	case tea.KeyMsg:
+		if key.Matches(msg, m.keys.returnToMenu) && m.list.FilterState() != list.Filtering {
+			return m, func() tea.Msg { return ui.SwitchToMenuMsg{} }
+		}
+		switch m.step {
+		case StepOrg, StepGroup:
+			if key.Matches(msg, keymap.Keys.Select) {
+				return m.submitInput()
+			}
+		case StepSelect:
+			if m.list.FilterState() == list.Filtering {
+				break
+			}
+			switch {
+			case key.Matches(msg, m.keys.toggle):
+				return m, m.toggleSelected()
+			case key.Matches(msg, m.keys.next):
+				return m.confirmSelection()
+			}
+		case StepDone:
+			if key.Matches(msg, keymap.Keys.Select) {
+				group := m.group
+				return m, func() tea.Msg { return ui.SwitchToConfigureGroupMsg{GroupName: group} }
+			}
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	switch m.step {
+	case StepOrg, StepGroup:
+		_, cmd = m.input.Update(msg, ui.ModeEditing)
+	case StepSelect:
+		m.list, cmd = m.list.Update(msg)
+	}
+	return m, cmd
+}
+
+// submitInput handles enter on the organization and the group name inputs
+func (m *ImportModel) submitInput() (tea.Model, tea.Cmd) {
+	val := strings.TrimSpace(m.input.Value())
+	if val == "" {
+		m.message = ui.WarningMessage("Please enter a value")
+		return m, nil
+	}
+	m.message = ui.Message{}
+
+	if m.step == StepGroup {
+		if err := importer.Validate(m.services.Loader.Groups(), val, m.selectedNames()); err != nil {
+			m.message = ui.ErrorMessage(err.Error())
+			return m, nil
+		}
+		m.group = val
+		m.step = StepImporting
+		return m, m.fetchSettings()
+	}
+
+	if m.services.GitHub == nil {
+		m.message = ui.ErrorMessage("GitHub client is not available, run 'gh auth login' first")
+		return m, nil
+	}
+	m.org = val
+	m.step = StepLoading
+	gh, org := m.services.GitHub, m.org
+	return m, func() tea.Msg {
+		repos, err := gh.ListOrgRepositories(org)
+		return reposLoadedMsg{repos: repos, err: err}
+	}
+}
+
+func (m *ImportModel) repoItems(repos []github.Repository) []list.Item {
+	managed := importer.ManagedRepositories(m.services.Loader.Groups())
+	items := make([]list.Item, len(repos))
+	for i, r := range repos {
+		items[i] = repoItem{repo: r, managedBy: managed[r.Name]}
+	}
+	return items
+}
+
+func (m *ImportModel) toggleSelected() tea.Cmd {
+	item, ok := m.list.SelectedItem().(repoItem)
+	if !ok {
+		return nil
+	}
+	if item.managedBy != "" && !item.selected {
+		// a repository can be managed by one group only
+		m.message = ui.WarningMessage(fmt.Sprintf("'%s' is already in group '%s'", item.repo.Name, item.managedBy))
+		return nil
+	}
+	m.message = ui.Message{}
+	item.selected = !item.selected
+	return m.list.SetItem(m.list.GlobalIndex(), item)
+}
+
+func (m *ImportModel) selectedNames() []string {
+	var names []string
+	for _, it := range m.list.Items() {
+		if item := it.(repoItem); item.selected {
+			names = append(names, item.repo.Name)
+		}
+	}
+	return names
+}
+
+// confirmSelection moves on to choosing the target group
+func (m *ImportModel) confirmSelection() (tea.Model, tea.Cmd) {
+	if len(m.selectedNames()) == 0 {
+		m.message = ui.WarningMessage("Select at least one repository with space")
+		return m, nil
+	}
+	m.message = ui.Message{}
+	m.step = StepGroup
+	m.input.SetLabel("Group")
+	m.input.SetValue("")
+	m.input.SetPlaceholder("new or existing group name")
+	return m, m.input.Focus()
+}
+
+func (m *ImportModel) fetchSettings() tea.Cmd {
+	gh, org, names := m.services.GitHub, m.org, m.selectedNames()
+	return func() tea.Msg {
+		live, err := importer.Fetch(gh, org, names)
+		return settingsLoadedMsg{live: live, err: err}
+	}
+}
+
+// handleSettingsLoaded writes the fetched settings into the group.
+// It runs on the update loop, since it touches the loaded groups.
+func (m *ImportModel) handleSettingsLoaded(msg settingsLoadedMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.step = StepGroup
+		m.message = ui.ErrorMessage("Error reading repository settings: " + msg.err.Error())
+		return m, nil
+	}
+
+	gf, warnings := importer.Plan(m.services.Loader, m.group, msg.live)
+	if err := m.services.Loader.SaveGroupFile(gf); err != nil {
+		m.step = StepGroup
+		m.message = ui.ErrorMessage(fmt.Sprintf("Error saving group '%s': %s", m.group, err))
+		return m, nil
+	}
+
+	m.step = StepDone
+	m.warnings = warnings
+	m.message = ui.InfoMessage(fmt.Sprintf("Imported %d repositories into group '%s' (%s).", len(msg.live), m.group, gf.Path))
+	return m, nil
+}
+
+// KeyHelp lists the keys of the step, the organization, the group
+// and the filter of the list are typed
+func (m *ImportModel) KeyHelp() ([][]key.Binding, bool) {
+	keys := keymap.Keys
+	switch m.step {
+	case StepSelect:
+		return m.list.FullHelp(), m.list.FilterState() == list.Filtering
+	case StepDone:
+		return [][]key.Binding{{keymap.Describe(keys.Select, "open the group"), m.keys.returnToMenu}}, false
+	case StepOrg, StepGroup:
+		return [][]key.Binding{{keymap.Describe(keys.Select, "next"), m.keys.returnToMenu}}, true
+	}
+	return [][]key.Binding{{m.keys.returnToMenu}}, false
+}
+
+func (m *ImportModel) View() (string, *tea.Cursor) {
+	switch m.step {
+	case StepSelect:
+		view := m.list.View()
+		if m.message.Msg != "" {
+			view += "\n" + ui.FormatMessage(m.message)
+		}
+		return style.AppStyle.Render(view), nil
+	case StepLoading:
+		return style.AppStyle.Render(fmt.Sprintf("Loading repositories of '%s'...", m.org)), nil
+	case StepImporting:
+		return style.AppStyle.Render("Reading repository settings from GitHub..."), nil
+	case StepDone:
+		lines := []string{ui.FormatMessage(m.message), ""}
+		for _, w := range m.warnings {
+			lines = append(lines, ui.FormatMessage(ui.WarningMessage(w)))
+		}
+		lines = append(lines, "", fmt.Sprintf("Press %s to open the group, %s to return to the menu.", keymap.Name(keymap.Keys.Select), keymap.Name(m.keys.returnToMenu)))
+		return style.AppStyle.Render(ui.JoinVertical(lines)), nil
+	}
+
+	// org and group inputs
+	var prompt string
+	if m.step == StepOrg {
+		prompt = "Import repositories from GitHub\n"
+	} else {
+		prompt = "Import into group (an existing group or a new one)\n"
+	}
+	promptLines := strings.Split(prompt, "\n")
+	layers := []*lipgloss.Layer{
+		lipgloss.NewLayer(prompt),
+		lipgloss.NewLayer(m.input.View()).Y(len(promptLines)),
+	}
+
+	var cursor *tea.Cursor
+	if cur := m.input.Cursor(); cur != nil {
+		cursor = tea.NewCursor(m.input.CursorOffset()+cur.X, len(promptLines)+cur.Y)
+	}
+	if m.message.Msg != "" {
+		layers = append(layers, lipgloss.NewLayer(ui.FormatMessage(m.message)).Y(len(promptLines)+2))
+	}
+	return lipgloss.NewCanvas(layers...).Render(), cursor
+}
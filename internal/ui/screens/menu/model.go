@@ -1,8 +1,10 @@
 package menu
 
 import (
+	"github.com/artemlive/gh-crossplane/internal/keymap"
 	ui "github.com/artemlive/gh-crossplane/internal/ui/shared"
 	"github.com/artemlive/gh-crossplane/internal/ui/style"
+	"github.com/charmbracelet/bubbles/v2/key"
 	tea "github.com/charmbracelet/bubbletea/v2"
 )
 
@@ -16,16 +18,28 @@ type MenuChoice int
 const (
 	ChoiceCreateRepo MenuChoice = iota
 	ChoiceConfigureRepo
+	ChoiceConfigureGroup
+	ChoiceSearch
+	ChoiceImport
+	ChoiceBulkEdit
 )
 
 var menuLabels = map[MenuChoice]string{
-	ChoiceCreateRepo:    "Create a new repo",
-	ChoiceConfigureRepo: "Configure an existing repo",
+	ChoiceCreateRepo:     "Create a new repo",
+	ChoiceConfigureRepo:  "Configure an existing repo",
+	ChoiceConfigureGroup: "Configure a group",
+	ChoiceSearch:         "Search repositories",
+	ChoiceImport:         "Import repositories from GitHub",
+	ChoiceBulkEdit:       "Bulk edit groups and repositories",
 }
 
 var menuOrder = []MenuChoice{
 	ChoiceCreateRepo,
 	ChoiceConfigureRepo,
+	ChoiceConfigureGroup,
+	ChoiceSearch,
+	ChoiceImport,
+	ChoiceBulkEdit,
 }
 
 func NewMenuModel() MenuModel {
@@ -41,24 +55,33 @@ func (m MenuModel) Init() tea.Cmd {
 func (m MenuModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "up", "k":
+		keys := keymap.Keys
+		switch {
+		case key.Matches(msg, keys.Up):
 			if m.cursor > 0 {
 				m.cursor--
 			}
-		case "down", "j":
+		case key.Matches(msg, keys.Down):
 			if m.cursor < len(m.choices)-1 {
 				m.cursor++
 			}
-		case "enter":
+		case key.Matches(msg, keys.Select):
 			switch menuOrder[m.cursor] {
 			case ChoiceCreateRepo:
 				return m, func() tea.Msg { return ui.SwitchToCreateRepoMsg{} }
 			case ChoiceConfigureRepo:
+				return m, func() tea.Msg { return ui.SwitchToRepoPickerMsg{} }
+			case ChoiceConfigureGroup:
 				return m, func() tea.Msg { return ui.SwitchToSelectGroupMsg{} }
+			case ChoiceSearch:
+				return m, func() tea.Msg { return ui.SwitchToSearchMsg{} }
+			case ChoiceImport:
+				return m, func() tea.Msg { return ui.SwitchToImportMsg{} }
+			case ChoiceBulkEdit:
+				return m, func() tea.Msg { return ui.SwitchToBulkEditMsg{} }
 			}
 			return m, nil
-		case "q":
+		case key.Matches(msg, keys.Quit):
 			return m, tea.Quit
 		}
 	}
@@ -76,6 +99,12 @@ func (m MenuModel) View() (string, *tea.Cursor) {
 			s += cursor + " " + menuLabels[choice] + "\n"
 		}
 	}
-	s += "\nUse up/down to move, enter to select, q to quit."
+	keys := keymap.Keys
+	s += "\n" + keymap.Hints(keys.Up, keys.Down, keys.Select, keys.Quit, keymap.Describe(keys.Help, "all keys"))
 	return s, nil
 }
+
+func (m MenuModel) KeyHelp() ([][]key.Binding, bool) {
+	keys := keymap.Keys
+	return [][]key.Binding{{keys.Up, keys.Down, keys.Select}, {keys.Quit, keys.ForceQuit}}, false
+}
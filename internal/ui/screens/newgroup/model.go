@@ -0,0 +1,190 @@
+package newgroup
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/artemlive/gh-crossplane/internal/domain"
+	"github.com/artemlive/gh-crossplane/internal/keymap"
+	"github.com/artemlive/gh-crossplane/internal/manifest"
+	"github.com/artemlive/gh-crossplane/internal/presets"
+	"github.com/artemlive/gh-crossplane/internal/ui/field"
+	ui "github.com/artemlive/gh-crossplane/internal/ui/shared"
+	"github.com/artemlive/gh-crossplane/internal/ui/style"
+	"github.com/charmbracelet/bubbles/v2/key"
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
+)
+
+const (
+	StepName = iota
+	StepPreset
+)
+
+// NewGroupModel asks for the name and the preset of a group, then creates its file
+type NewGroupModel struct {
+	step     int
+	name     string
+	services ui.Services
+	// repo is added to the new group, nil if none
+	repo *domain.Repository
+
+	input   *field.TextInputComponent
+	presets *field.ListComponent // nil if there are no presets
+	message ui.Message
+}
+
+func NewNewGroupModel(services ui.Services, repo *domain.Repository) NewGroupModel {
+	m := NewGroupModel{
+		step:     StepName,
+		services: services,
+		repo:     repo,
+		input:    field.NewTextInputComponent("Group Name", nil),
+	}
+	m.input.SetPlaceholder("group-name")
+	if len(services.Presets) > 0 {
+		m.presets = field.NewPresetListComponent(services.Presets)
+	}
+	return m
+}
+
+func (m NewGroupModel) Init() tea.Cmd {
+	return m.input.Focus()
+}
+
+func (m NewGroupModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		if m.step == StepName {
+			newInput, cmd := m.input.Update(msg, ui.ModeEditing)
+			m.input = newInput.(*field.TextInputComponent)
+			return m, cmd
+		}
+		return m, nil
+	}
+
+	keys := keymap.Keys
+	switch m.step {
+	case StepName:
+		switch {
+		case key.Matches(keyMsg, keys.Select):
+			return m.checkName(strings.TrimSpace(m.input.Value()))
+		case key.Matches(keyMsg, keys.Back):
+			return m, func() tea.Msg { return ui.SwitchToMenuMsg{} }
+		}
+		m.message = ui.Message{}
+		newInput, cmd := m.input.Update(msg, ui.ModeEditing)
+		m.input = newInput.(*field.TextInputComponent)
+		return m, cmd
+	case StepPreset:
+		switch {
+		case key.Matches(keyMsg, keys.Select):
+			var preset presets.Preset
+			if i := m.presets.Selected(); i > 0 {
+				preset = m.services.Presets[i-1]
+			}
+			return m.create(preset)
+		case key.Matches(keyMsg, keys.Back):
+			m.step = StepName
+			return m, m.input.Focus()
+		}
+		_, cmd := m.presets.Update(msg, ui.ModeEditing)
+		return m, cmd
+	}
+	return m, nil
+}
+
+// checkName makes sure the name is valid and not taken by a group or a file
+func (m NewGroupModel) checkName(name string) (tea.Model, tea.Cmd) {
+	loader := m.services.Loader
+	switch {
+	case name == "":
+		m.message = ui.WarningMessage("please enter a value")
+		return m, nil
+	case !manifest.NamePattern.MatchString(name):
+		m.message = ui.ErrorMessage("The name must consist of lowercase letters, digits and '-', and start and end with a letter or digit")
+		return m, nil
+	case loader.GetGroup(name) != nil:
+		m.message = ui.ErrorMessage(fmt.Sprintf("Group '%s' already exists", name))
+		return m, nil
+	}
+	if _, err := os.Stat(loader.NewGroupPath(name)); err == nil {
+		m.message = ui.ErrorMessage(fmt.Sprintf("File '%s' already exists", loader.NewGroupPath(name)))
+		return m, nil
+	}
+
+	m.name = name
+	m.message = ui.Message{}
+	if m.presets == nil {
+		return m.create(presets.Preset{})
+	}
+	m.input.Blur()
+	m.step = StepPreset
+	return m, nil
+}
+
+// create writes the group file and opens it
+func (m NewGroupModel) create(preset presets.Preset) (tea.Model, tea.Cmd) {
+	loader := m.services.Loader
+	gf := &manifest.GroupFile{
+		Path:     loader.NewGroupPath(m.name),
+		Manifest: preset.NewGroup(m.name, loader.APIVersion()),
+	}
+	if m.repo != nil {
+		gf.Manifest.Spec.Repositories = append(gf.Manifest.Spec.Repositories, *m.repo)
+	}
+	if err := loader.SaveGroupFile(gf); err != nil {
+		m.message = ui.ErrorMessage(fmt.Sprintf("Error creating group '%s': %s", m.name, err.Error()))
+		return m, nil
+	}
+	name := m.name
+	return m, func() tea.Msg { return ui.SwitchToConfigureGroupMsg{GroupName: name} }
+}
+
+func (m NewGroupModel) View() (string, *tea.Cursor) {
+	var layers []*lipgloss.Layer
+	var cursor *tea.Cursor
+	layoutY := 0
+
+	title := "New group"
+	if m.repo != nil {
+		title = fmt.Sprintf("New group for repo '%s'", m.repo.Name)
+	}
+	layers = append(layers, lipgloss.NewLayer(style.LabelStyle.Render(title)).Y(layoutY))
+	layoutY += 2
+
+	var body string
+	switch m.step {
+	case StepName:
+		body = m.input.View()
+		if cur := m.input.Cursor(); cur != nil {
+			cursor = tea.NewCursor(m.input.CursorOffset()+cur.X, layoutY+cur.Y)
+		}
+	case StepPreset:
+		body = m.presets.View()
+	}
+	layers = append(layers, lipgloss.NewLayer(body).Y(layoutY))
+	layoutY += lipgloss.Height(body) + 1
+
+	layers = append(layers, lipgloss.NewLayer(style.InactiveTextStyle.Render(keymap.Hints(m.keys()...))).Y(layoutY))
+	layoutY++
+
+	if m.message.Msg != "" {
+		layers = append(layers, lipgloss.NewLayer("\n"+ui.FormatMessage(m.message)).Y(layoutY))
+	}
+	return lipgloss.NewCanvas(layers...).Render(), cursor
+}
+
+// keys are the keys of the name input, or of the preset picker
+func (m NewGroupModel) keys() []key.Binding {
+	keys := keymap.Keys
+	if m.step == StepPreset {
+		return []key.Binding{keys.Up, keys.Down, keymap.Describe(keys.Select, "create"), keys.Back}
+	}
+	return []key.Binding{keymap.Describe(keys.Select, "confirm"), keymap.Describe(keys.Back, "return to main menu")}
+}
+
+func (m NewGroupModel) KeyHelp() ([][]key.Binding, bool) {
+	return [][]key.Binding{m.keys()}, m.step == StepName
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/artemlive/gh-crossplane/internal/domain"
+	"github.com/artemlive/gh-crossplane/internal/keymap"
 	"github.com/artemlive/gh-crossplane/internal/manifest"
 	ui "github.com/artemlive/gh-crossplane/internal/ui/shared"
 	"github.com/artemlive/gh-crossplane/internal/ui/style"
@@ -18,6 +19,8 @@ type SelectGroupModel struct {
 	selectedGroup string
 	list          list.Model
 	keys          *listKeyMap
+	// repo is the new repository the group is selected for, the name is empty if none
+	repo domain.Repository
 }
 
 type listKeyMap struct {
@@ -27,18 +30,11 @@ type listKeyMap struct {
 }
 
 func newListKeyMap() *listKeyMap {
+	keys := keymap.Keys
 	return &listKeyMap{
-		addGroup: key.NewBinding(
-			key.WithKeys("a"),
-			key.WithHelp("a", "add new group"),
-		),
-		selectGroup: key.NewBinding(
-			key.WithKeys("enter"),
-			key.WithHelp("enter", "select group"),
-		),
-		returnToMenu: key.NewBinding(
-			key.WithKeys("esc"),
-			key.WithHelp("esc", "return to main menu")),
+		addGroup:     keymap.Describe(keys.Add, "add new group"),
+		selectGroup:  keymap.Describe(keys.Select, "select group"),
+		returnToMenu: keymap.Describe(keys.Back, "return to main menu"),
 	}
 }
 
@@ -58,23 +54,31 @@ func NewSelectGroupModel(groups []manifest.GroupFile, repo domain.Repository, wi
 	// to adjust the size to fit the terminal
 	// same as we do in the window size message handler
 	h, v := style.AppStyle.GetFrameSize()
-	groupsList := list.New(listItems, list.NewDefaultDelegate(), width-h, height-v)
-	groupsList.KeyMap.Quit.SetKeys("q", "ctrl+c")
-	groupsList.Title = "Select Group Or Add New By Pressing 'a'"
+	groupsList := list.New(listItems, style.ListDelegate(), width-h, height-v)
+	groupsList.Styles = style.ListStyles()
+	groupsList.Help.Styles = style.HelpStyles()
+	groupsList.KeyMap = keymap.List()
+	add := keymap.Name(listKeys.addGroup)
+	groupsList.Title = fmt.Sprintf("Select Group Or Add New By Pressing '%s'", add)
 	if repo.Name != "" {
-		groupsList.Title = fmt.Sprintf("Select Group For Repo '%s' Or Add New By Pressing 'a'", repo.Name)
+		groupsList.Title = fmt.Sprintf("Select Group For Repo '%s' Or Add New By Pressing '%s'", repo.Name, add)
 	}
 	groupsList.AdditionalFullHelpKeys = func() []key.Binding {
 		return []key.Binding{
 			listKeys.addGroup,
 			listKeys.selectGroup,
+			listKeys.returnToMenu,
 		}
 	}
+	groupsList.AdditionalShortHelpKeys = func() []key.Binding {
+		return []key.Binding{keymap.Describe(keymap.Keys.Help, "more")}
+	}
 	return SelectGroupModel{
 		groupNames: groups,
 		cursor:     0,
 		list:       groupsList,
 		keys:       listKeys,
+		repo:       repo,
 	}
 }
 
@@ -85,6 +89,13 @@ func (m SelectGroupModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		h, v := style.AppStyle.GetFrameSize()
 		m.list.SetSize(msg.Width-h, msg.Height-v)
+	case ui.GroupsReloadedMsg:
+		m.groupNames = msg.Groups
+		listItems := make([]list.Item, len(msg.Groups))
+		for i, group := range msg.Groups {
+			listItems[i] = group
+		}
+		return m, m.list.SetItems(listItems)
 	case tea.KeyMsg:
 		if m.list.FilterState() == list.Filtering {
 			break
@@ -96,7 +107,11 @@ func (m SelectGroupModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			m.selectedGroup = m.list.SelectedItem().(manifest.GroupFile).Manifest.Metadata.Name
 			return m, func() tea.Msg {
-				return ui.SwitchToConfigureGroupMsg{GroupName: m.selectedGroup}
+				return ui.SwitchToConfigureGroupMsg{GroupName: m.selectedGroup, Repo: m.newRepo()}
+			}
+		case key.Matches(msg, m.keys.addGroup):
+			return m, func() tea.Msg {
+				return ui.SwitchToNewGroupMsg{Repo: m.newRepo()}
 			}
 		case key.Matches(msg, m.keys.returnToMenu):
 			return m, func() tea.Msg {
@@ -113,6 +128,20 @@ func (m SelectGroupModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 }
 
+// newRepo returns the repository to add to the selected group, nil if none
+func (m SelectGroupModel) newRepo() *domain.Repository {
+	if m.repo.Name == "" {
+		return nil
+	}
+	repo := m.repo
+	return &repo
+}
+
+// KeyHelp lists the keys of the list, the filter is typed while it's edited
+func (m SelectGroupModel) KeyHelp() ([][]key.Binding, bool) {
+	return m.list.FullHelp(), m.list.FilterState() == list.Filtering
+}
+
 func (m SelectGroupModel) View() (string, *tea.Cursor) {
 	return style.AppStyle.Render(m.list.View()), nil
 }
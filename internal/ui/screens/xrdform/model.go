@@ -0,0 +1,432 @@
+// Package xrdform edits a group with forms built from the schema of an XRD,
+// so the fields the domain types don't know about can be edited too.
+// It's opened from the group editor, which edits the repositories and
+// commits the changes, esc goes back there.
+package xrdform
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/artemlive/gh-crossplane/internal/dynamic"
+	"github.com/artemlive/gh-crossplane/internal/keymap"
+	"github.com/artemlive/gh-crossplane/internal/manifest"
+	"github.com/artemlive/gh-crossplane/internal/schema"
+	"github.com/artemlive/gh-crossplane/internal/ui/field"
+	ui "github.com/artemlive/gh-crossplane/internal/ui/shared"
+	"github.com/artemlive/gh-crossplane/internal/ui/style"
+	"github.com/charmbracelet/bubbles/v2/key"
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// entry is an editable field and the component editing it
+type entry struct {
+	field     dynamic.Field
+	component field.FieldComponent
+	text      *string // the edited value, nil for checkboxes
+	original  string  // the value in the document when it was loaded or saved
+}
+
+// section is a tab with the fields of one object of the spec
+type section struct {
+	name     string
+	entries  []*entry
+	readOnly []dynamic.Field // lists of objects and maps
+}
+
+type Model struct {
+	group  *manifest.GroupFile
+	doc    *yaml.Node
+	loader *manifest.ManifestLoader
+
+	sections     []section
+	activeTab    int
+	focusedIndex int
+	mode         ui.FocusMode
+	message      ui.Message
+	width        int
+	height       int
+	// discard is set when esc was pressed with unsaved edits
+	discard bool
+}
+
+var _ ui.ViewableModel = (*Model)(nil)
+
+// New builds the forms of the group from the spec schema of the XRD version
+// matching the apiVersion of the group
+func New(group *manifest.GroupFile, xrd *schema.XRD, loader *manifest.ManifestLoader, width, height int) (*Model, error) {
+	_, version, _ := strings.Cut(group.Manifest.APIVersion, "/")
+	spec, err := xrd.SpecSchema(version)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := group.Node()
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Model{
+		group:  group,
+		doc:    doc,
+		loader: loader,
+		width:  width,
+		height: height,
+	}
+	for _, f := range dynamic.Fields(spec, "spec") {
+		s := m.section(f.Section())
+		if !f.Editable() {
+			s.readOnly = append(s.readOnly, f)
+			continue
+		}
+		text := dynamic.Text(doc, f)
+		s.entries = append(s.entries, newEntry(f, text, text))
+	}
+	return m, nil
+}
+
+// section returns the tab of the object with the given path, adding it if missing
+func (m *Model) section(path string) *section {
+	for i := range m.sections {
+		if m.sections[i].name == path {
+			return &m.sections[i]
+		}
+	}
+	m.sections = append(m.sections, section{name: path})
+	return &m.sections[len(m.sections)-1]
+}
+
+// newEntry builds the component editing the field, starting from the value
+// which differs from the original one if there are unsaved edits
+func newEntry(f dynamic.Field, original, value string) *entry {
+	e := &entry{field: f, original: original}
+	label := f.Label()
+	if def := dynamic.DefaultText(f); def != "" && f.Schema.Type != "string" {
+		label += fmt.Sprintf(" (default %s)", def)
+	}
+
+	switch {
+	case f.Schema.Type == "boolean":
+		var checked *bool
+		if b, err := strconv.ParseBool(value); err == nil {
+			checked = &b
+		}
+		e.component = field.NewCheckboxComponent(label, checked)
+	case len(f.Schema.Enum) > 0:
+		e.text = &value
+		e.component = field.NewSelectComponent(label, e.text, f.Schema.Enum)
+	default:
+		e.text = &value
+		input := field.NewTextInputComponent(label, e.text)
+		if def := dynamic.DefaultText(f); def != "" {
+			input.SetPlaceholder(def)
+		}
+		e.component = input
+	}
+	return e
+}
+
+// value returns the edited value in its text form, ok is false
+// for a checkbox that was never set
+func (e *entry) value() (string, bool) {
+	if cb, isCheckbox := e.component.(*field.CheckboxComponent); isCheckbox {
+		if cb.Value() == nil {
+			return "", false
+		}
+		return strconv.FormatBool(*cb.Value()), true
+	}
+	return *e.text, true
+}
+
+func (e *entry) modified() bool {
+	val, ok := e.value()
+	return ok && strings.TrimSpace(val) != e.original
+}
+
+func (m *Model) entries() []*entry {
+	if len(m.sections) == 0 {
+		return nil
+	}
+	return m.sections[m.activeTab].entries
+}
+
+func (m *Model) modified() bool {
+	for _, s := range m.sections {
+		for _, e := range s.entries {
+			if e.modified() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (m *Model) Init() tea.Cmd {
+	if entries := m.entries(); len(entries) > 0 {
+		return entries[0].component.Focus()
+	}
+	return nil
+}
+
+func (m *Model) focus(idx int) tea.Cmd {
+	entries := m.entries()
+	if len(entries) == 0 {
+		return nil
+	}
+	entries[m.focusedIndex].component.Blur()
+	m.focusedIndex = (idx + len(entries)) % len(entries)
+	return entries[m.focusedIndex].component.Focus()
+}
+
+func (m *Model) switchTab(delta int) tea.Cmd {
+	if len(m.sections) == 0 {
+		return nil
+	}
+	if entries := m.entries(); len(entries) > 0 {
+		entries[m.focusedIndex].component.Blur()
+	}
+	m.activeTab = (m.activeTab + delta + len(m.sections)) % len(m.sections)
+	m.focusedIndex = 0
+	if entries := m.entries(); len(entries) > 0 {
+		return entries[0].component.Focus()
+	}
+	return nil
+}
+
+// save writes the modified fields into the document and the document to the group file.
+// If the file changed on disk, the edits are merged into the new version instead.
+func (m *Model) save() tea.Cmd {
+	for _, s := range m.sections {
+		for _, e := range s.entries {
+			if !e.modified() {
+				continue
+			}
+			val, _ := e.value()
+			if err := dynamic.Set(m.doc, e.field, val); err != nil {
+				m.message = ui.ErrorMessage(fmt.Sprintf("Group '%s' was not saved: %s", m.group.Title(), err.Error()))
+				return nil
+			}
+		}
+	}
+
+	err := m.loader.SaveGroupNode(m.group, m.doc)
+	switch {
+	case errors.Is(err, manifest.ErrFileChanged):
+		if _, err := m.loader.Reload([]string{m.group.Path}); err != nil {
+			m.message = ui.ErrorMessage(fmt.Sprintf("Error reloading group '%s': %s", m.group.Title(), err.Error()))
+			return nil
+		}
+		cmd := m.handleGroupsReloaded(ui.GroupsReloadedMsg{Paths: []string{m.group.Path}})
+		m.message = ui.WarningMessage(fmt.Sprintf("Group '%s' was not saved: the file changed on disk. %s", m.group.Title(), m.message.Msg))
+		return cmd
+	case err != nil:
+		m.message = ui.ErrorMessage(fmt.Sprintf("Error saving group '%s': %s", m.group.Title(), err.Error()))
+		return nil
+	}
+
+	for _, s := range m.sections {
+		for _, e := range s.entries {
+			e.original = dynamic.Text(m.doc, e.field)
+		}
+	}
+	m.message = ui.InfoMessage(fmt.Sprintf("Group '%s' saved successfully.", m.group.Title()))
+	return nil
+}
+
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	entries := m.entries()
+
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		return m, nil
+	case ui.GroupsReloadedMsg:
+		return m, m.handleGroupsReloaded(msg)
+	case field.FieldDoneMsg:
+		m.mode = ui.ModeNavigation
+		return m, nil
+	case field.FieldDoneUpMsg:
+		return m, m.focus(m.focusedIndex - 1)
+	case field.FieldDoneDownMsg:
+		return m, m.focus(m.focusedIndex + 1)
+	case tea.KeyMsg:
+		keys := keymap.Keys
+		if !key.Matches(msg, keys.Back) {
+			m.discard = false
+		}
+		if m.mode == ui.ModeEditing {
+			if key.Matches(msg, keys.Back) {
+				m.mode = ui.ModeNavigation
+				return m, nil
+			}
+			break
+		}
+		switch {
+		case key.Matches(msg, keys.NextField, keys.Down):
+			return m, m.focus(m.focusedIndex + 1)
+		case key.Matches(msg, keys.PrevField, keys.Up):
+			return m, m.focus(m.focusedIndex - 1)
+		case key.Matches(msg, keys.Left):
+			return m, m.switchTab(-1)
+		case key.Matches(msg, keys.Right):
+			return m, m.switchTab(1)
+		case key.Matches(msg, keys.Edit):
+			if len(entries) > 0 {
+				m.mode = ui.ModeEditing
+			}
+			return m, nil
+		case key.Matches(msg, keys.Save):
+			return m, m.save()
+		case key.Matches(msg, keys.Back):
+			if m.modified() && !m.discard {
+				m.discard = true
+				m.message = ui.WarningMessage(fmt.Sprintf("There are unsaved edits, press %s again to discard them or %s to save.", keymap.Name(keys.Back), keymap.Name(keys.Save)))
+				return m, nil
+			}
+			name := m.group.Manifest.Metadata.Name
+			return m, func() tea.Msg { return ui.SwitchToConfigureGroupMsg{GroupName: name} }
+		case key.Matches(msg, keys.Quit):
+			return m, tea.Quit
+		}
+		return m, nil
+	}
+
+	if len(entries) == 0 {
+		return m, nil
+	}
+	e := entries[m.focusedIndex]
+	var cmd tea.Cmd
+	e.component, cmd = e.component.Update(msg, m.mode)
+	return m, cmd
+}
+
+// handleGroupsReloaded rebuilds the forms when the group file changed on disk.
+// The unsaved edits are merged into the new version: the fields only changed
+// on disk take the new value, on a conflict the local value wins.
+func (m *Model) handleGroupsReloaded(msg ui.GroupsReloadedMsg) tea.Cmd {
+	fresh := m.loader.GetGroupByPath(m.group.Path)
+	if fresh == nil || !slices.Contains(msg.Paths, m.group.Path) {
+		return nil
+	}
+	doc, err := fresh.Node()
+	if err != nil {
+		m.message = ui.ErrorMessage(fmt.Sprintf("Error reloading group '%s': %s", m.group.Title(), err.Error()))
+		return nil
+	}
+
+	var merged, conflicts []string
+	for i := range m.sections {
+		for j, e := range m.sections[i].entries {
+			theirs := dynamic.Text(doc, e.field)
+			mine, _ := e.value()
+			mine = strings.TrimSpace(mine)
+			if !e.modified() || mine == theirs {
+				m.sections[i].entries[j] = newEntry(e.field, theirs, theirs)
+				continue
+			}
+			if theirs != e.original {
+				conflicts = append(conflicts, e.field.Name())
+			}
+			merged = append(merged, e.field.Name())
+			m.sections[i].entries[j] = newEntry(e.field, theirs, mine)
+		}
+	}
+	*m.group, m.doc = *fresh, doc
+	m.mode = ui.ModeNavigation
+
+	keys := keymap.Keys
+	switch {
+	case len(conflicts) > 0:
+		m.message = ui.WarningMessage("Merged the changes from disk with conflicts, kept local values for: " + strings.Join(conflicts, ", "))
+	case len(merged) > 0:
+		m.message = ui.InfoMessage(fmt.Sprintf("Merged the changes from disk into the edits, press %s to save.", keymap.Name(keys.Save)))
+	default:
+		m.message = ui.InfoMessage(fmt.Sprintf("Group '%s' was reloaded from disk.", m.group.Title()))
+	}
+	if entries := m.entries(); len(entries) > 0 {
+		return entries[m.focusedIndex].component.Focus()
+	}
+	return nil
+}
+
+func (m *Model) View() (string, *tea.Cursor) {
+	var lines []string
+	var cursor *tea.Cursor
+
+	lines = append(lines, strings.Split(m.renderTabs(), "\n")...)
+	lines = append(lines, "")
+
+	if len(m.sections) == 0 {
+		lines = append(lines, style.InactiveTextStyle.Render("The XRD has no spec properties"))
+	} else {
+		s := m.sections[m.activeTab]
+		for i, e := range s.entries {
+			view := e.component.View()
+			if c, ok := e.component.(field.Cursorer); ok && e.component.IsFocused() && m.mode == ui.ModeEditing {
+				if cur := c.Cursor(); cur != nil {
+					cursor = tea.NewCursor(e.component.CursorOffset()+cur.X, len(lines)+cur.Y)
+				}
+			}
+			lines = append(lines, strings.Split(view, "\n")...)
+			if i == m.focusedIndex && e.field.Schema.Description != "" {
+				lines = append(lines, "    "+style.InactiveTextStyle.Render(e.field.Schema.Description))
+			}
+		}
+		for _, f := range s.readOnly {
+			where := "not editable with the XRD form"
+			if f.Name() == "spec.repositories" {
+				where = "edit them in the group editor, " + keymap.Name(keymap.Keys.Back)
+			}
+			lines = append(lines, style.DimStyle.Render(fmt.Sprintf("  %s: %s (%s)", f.Label(), dynamic.Summary(m.doc, f), where)))
+		}
+	}
+
+	lines = append(lines, "", m.statusBarText())
+	if m.message.Msg != "" {
+		lines = append(lines, "", ui.FormatMessage(m.message))
+	}
+	return strings.Join(lines, "\n"), cursor
+}
+
+func (m *Model) renderTabs() string {
+	var rendered []string
+	for i, s := range m.sections {
+		curStyle := style.InactiveTabStyle
+		if i == m.activeTab {
+			curStyle = style.ActiveTabStyle
+		}
+		rendered = append(rendered, curStyle.Render(s.name))
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, rendered...)
+}
+
+func (m *Model) statusBarText() string {
+	keys := keymap.Keys
+	if m.mode == ui.ModeEditing {
+		return style.ConfigureGroupStatusStyleEditing.Render(fmt.Sprintf("[EDT Mode] Press %s or %s to finish", keymap.Name(keys.Back), keymap.Name(keys.Done)))
+	}
+	return style.ConfigureGroupStatusStyleNavigation.Render(fmt.Sprintf("[XRD %s] %s", m.group.Title(), keymap.Hints(keys.Edit, keys.Save, keymap.Describe(keys.Back, "group editor"), keys.Quit, keymap.Describe(keys.Help, "all keys"))))
+}
+
+// KeyHelp lists the keys of the form, or of the field edited
+func (m *Model) KeyHelp() ([][]key.Binding, bool) {
+	keys := keymap.Keys
+	if m.mode == ui.ModeEditing {
+		finish := keymap.Describe(keys.Back, "finish editing")
+		entries := m.entries()
+		if m.focusedIndex < len(entries) {
+			if h, ok := entries[m.focusedIndex].component.(field.KeyHelper); ok && h.KeyHelp() != nil {
+				return [][]key.Binding{h.KeyHelp(), {finish}}, false
+			}
+		}
+		return [][]key.Binding{{keys.Done, keys.FieldUp, keys.FieldDown, finish}}, true
+	}
+	return [][]key.Binding{
+		{keys.Up, keys.Down, keys.NextField, keys.PrevField, keys.Left, keys.Right, keys.Edit},
+		{keys.Save, keymap.Describe(keys.Back, "group editor"), keys.Quit, keys.ForceQuit},
+	}, false
+}
@@ -3,9 +3,19 @@ package shared
 import (
 	"strings"
 
+	"github.com/artemlive/gh-crossplane/internal/checks"
+	"github.com/artemlive/gh-crossplane/internal/directory"
 	"github.com/artemlive/gh-crossplane/internal/domain"
+	"github.com/artemlive/gh-crossplane/internal/git"
+	"github.com/artemlive/gh-crossplane/internal/github"
+	"github.com/artemlive/gh-crossplane/internal/layout"
+	"github.com/artemlive/gh-crossplane/internal/manifest"
+	"github.com/artemlive/gh-crossplane/internal/policy"
+	"github.com/artemlive/gh-crossplane/internal/presets"
+	"github.com/artemlive/gh-crossplane/internal/schema"
+	"github.com/artemlive/gh-crossplane/internal/ui/style"
+	"github.com/charmbracelet/bubbles/v2/key"
 	tea "github.com/charmbracelet/bubbletea/v2"
-	"github.com/charmbracelet/lipgloss/v2"
 )
 
 type ViewableModel interface {
@@ -13,6 +23,32 @@ type ViewableModel interface {
 	tea.CursorModel
 }
 
+// KeyHelper is implemented by the screens, the help overlay lists their keys
+type KeyHelper interface {
+	// KeyHelp returns the groups of the keys active on the screen. Typing is set
+	// while the keys go to a text input, the printable help keys are typed there.
+	KeyHelp() (groups [][]key.Binding, typing bool)
+}
+
+// Services holds the dependencies the screens share
+type Services struct {
+	Loader *manifest.ManifestLoader
+	Repo   git.Repository // nil if the groups dir isn't inside a git repository
+	GitHub github.API     // nil if the GitHub client couldn't be created
+	// Directory completes the teams and members of the organization, nil if unknown
+	Directory *directory.Cache
+	// Checks suggests the status check contexts of the repositories, nil if unknown
+	Checks *checks.Cache
+	// Policies are the org-wide rules the groups are checked against, nil if there are none
+	Policies *policy.File
+	// Presets are the templates new groups and repositories start from
+	Presets []presets.Preset
+	// Layout arranges the fields of the editor, nil for the built-in layout
+	Layout *layout.File
+	// XRD builds the form of the fields the domain types don't know, nil if not given
+	XRD *schema.XRD
+}
+
 type FocusMode int
 
 const (
@@ -61,13 +97,41 @@ func InfoMessage(msg string) Message {
 
 type SwitchToMenuMsg struct{}
 type SwitchToCreateRepoMsg struct{}
+type SwitchToImportMsg struct{}
+type SwitchToBulkEditMsg struct{}
+type SwitchToSearchMsg struct{}
+
+// SwitchToRepoPickerMsg opens the list of all the repositories to configure one of them
+type SwitchToRepoPickerMsg struct{}
+
+// SwitchToConfigureRepoMsg opens the editor of a single repository of the group
+type SwitchToConfigureRepoMsg struct {
+	GroupName string
+	RepoName  string
+}
 type SwitchToSelectGroupMsg struct {
 	RepoName    string
 	Description string
+	Preset      string // preset the repository starts from, empty for none
 }
 
 type SwitchToConfigureGroupMsg struct {
 	GroupName string
+	// RepoName is the repository to focus, empty if none
+	RepoName string
+	// Repo is a new repository to add to the group, nil if none
+	Repo *domain.Repository
+}
+
+// SwitchToXRDFormMsg opens the group in the form built from the XRD
+type SwitchToXRDFormMsg struct {
+	GroupName string
+}
+
+// SwitchToNewGroupMsg opens the wizard creating a group file
+type SwitchToNewGroupMsg struct {
+	// Repo is a new repository to add to the group, nil if none
+	Repo *domain.Repository
 }
 
 type SwitchToGroupMsg struct {
@@ -75,22 +139,49 @@ type SwitchToGroupMsg struct {
 	Repo  *domain.Repository
 }
 
+// GroupFilesChangedMsg is sent when the watcher notices changed files in the groups dir
+type GroupFilesChangedMsg struct {
+	Paths []string
+}
+
+// GroupsReloadedMsg is delegated to the current screen after the changed files were reloaded
+type GroupsReloadedMsg struct {
+	Paths  []string // paths of the groups that changed
+	Groups []manifest.GroupFile
+}
+
 type ForceReRenderMsg struct{}
 type TickMsg struct{}
 
 func FormatMessage(msg Message) string {
 	switch msg.Type {
 	case MessageTypeError:
-		return lipgloss.NewStyle().Foreground(lipgloss.Color("1")).Render("✖ " + msg.Msg)
+		return style.ErrorStyle.Render("✖ " + msg.Msg)
 	case MessageTypeWarning:
-		return lipgloss.NewStyle().Foreground(lipgloss.Color("3")).Render("⚠ " + msg.Msg)
+		return style.WarningStyle.Render("⚠ " + msg.Msg)
 	case MessageTypeInfo:
-		return lipgloss.NewStyle().Foreground(lipgloss.Color("6")).Render("ℹ " + msg.Msg)
+		return style.InfoStyle.Render("ℹ " + msg.Msg)
 	default:
 		return msg.Msg
 	}
 }
 
+// FormatDiff colors the added lines green and the removed lines red
+func FormatDiff(lines []manifest.DiffLine) string {
+	out := make([]string, len(lines))
+	for i, l := range lines {
+		switch l.Op {
+		case '+':
+			out[i] = style.AddedStyle.Render(l.String())
+		case '-':
+			out[i] = style.RemovedStyle.Render(l.String())
+		default:
+			out[i] = l.String()
+		}
+	}
+	return strings.Join(out, "\n")
+}
+
 // JoinVertical joins lines with newlines, for vertical stacking
 func JoinVertical(lines []string) string {
 	return strings.Join(lines, "\n")
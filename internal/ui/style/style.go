@@ -1,63 +1,149 @@
 package style
 
 import (
+	"image/color"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/v2/help"
+	"github.com/charmbracelet/bubbles/v2/list"
 	"github.com/charmbracelet/bubbles/v2/textinput"
 	"github.com/charmbracelet/lipgloss/v2"
 )
 
-// TODO: consolidate this as a theme
+// Current is the theme the styles are built from, Apply changes it
+var Current Theme
+
 var (
-	AppStyle = lipgloss.NewStyle().Padding(1, 2)
+	AppStyle lipgloss.Style
 
-	MainMenuCurosrStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#25A065")) // green
+	MainMenuCurosrStyle lipgloss.Style
 
-	ConfigureGroupStatusStyleEditing    = lipgloss.NewStyle().Foreground(lipgloss.Color("#04B575")).Background(lipgloss.Color("235")).Padding(0, 1)
-	ConfigureGroupStatusStyleNavigation = lipgloss.NewStyle().Foreground(lipgloss.Color("#436f94")).Background(lipgloss.Color("235")).Padding(0, 1).Bold(true)
+	ConfigureGroupStatusStyleEditing    lipgloss.Style
+	ConfigureGroupStatusStyleNavigation lipgloss.Style
 
-	ErrorMessageStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("#FF0000")).Bold(true) // red
-	InfoMessageStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("#00FF00")).Bold(true) // green
-	WarningMessageStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#FFA500")).Bold(true) // orange
+	ErrorMessageStyle   lipgloss.Style
+	InfoMessageStyle    lipgloss.Style
+	WarningMessageStyle lipgloss.Style
 
-	InactiveTabStyle = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(0, 1).BorderForeground(lipgloss.Color("240"))
-	ActiveTabStyle   = InactiveTabStyle.BorderBottom(false).Bold(true)
+	// the messages shown below the screens and the lines of the diffs
+	ErrorStyle   lipgloss.Style
+	WarningStyle lipgloss.Style
+	InfoStyle    lipgloss.Style
+	AddedStyle   lipgloss.Style
+	RemovedStyle lipgloss.Style
 
-	RepoPreviewStyle = lipgloss.NewStyle().Padding(0, 0).Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("#4A32DE")).MarginLeft(1)
+	InactiveTabStyle lipgloss.Style
+	ActiveTabStyle   lipgloss.Style
 
-	ModalBoxStyle = lipgloss.NewStyle().
-			Padding(1, 2).
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("63")).
-			Background(lipgloss.Color("234")).
-			Align(lipgloss.Center)
-
-	LabelStyle        = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("7")) // white
-	InactiveTextStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#888"))         // gray
-	FocusedTextStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("7")).Bold(true) // bright green
-	FieldBlockStyle   = lipgloss.NewStyle().Padding(0, 1)
+	RepoPreviewStyle lipgloss.Style
+
+	ModalBoxStyle lipgloss.Style
+
+	// HelpBoxStyle frames the keys shown over a screen
+	HelpBoxStyle lipgloss.Style
+
+	LabelStyle        lipgloss.Style
+	InactiveTextStyle lipgloss.Style
+	FocusedTextStyle  lipgloss.Style
+	FieldBlockStyle   lipgloss.Style
 	FocusedPrefix     = "➤"
-	DimStyle          = lipgloss.NewStyle().Foreground(lipgloss.Color("#555"))
+	DimStyle          lipgloss.Style
 )
 
-var TextInputStyleEditingFocused = textinput.StyleState{
-	Prompt: lipgloss.NewStyle().Foreground(lipgloss.Color("205")),
-	Text:   lipgloss.NewStyle().Foreground(lipgloss.Color("229")),
+var (
+	TextInputStyleEditingFocused    textinput.StyleState
+	TextInputStyleEditingBlurred    textinput.StyleState
+	TextInputStyleNavigationFocused textinput.StyleState
+	TextInputStyleNavigationBlurred textinput.StyleState
+)
+
+func init() {
+	Apply(DarkTheme)
+}
+
+// Apply builds the styles from the theme. The screens render with the new
+// styles right away, the lists and the inputs created before keep their colors.
+func Apply(t Theme) {
+	Current = t
+	fg := func(c color.Color) lipgloss.Style { return lipgloss.NewStyle().Foreground(c) }
+
+	AppStyle = lipgloss.NewStyle().Padding(1, 2)
+
+	MainMenuCurosrStyle = fg(t.Accent)
+
+	ConfigureGroupStatusStyleEditing = fg(t.StatusEditing).Background(t.StatusBg).Padding(0, 1)
+	ConfigureGroupStatusStyleNavigation = fg(t.StatusNavigation).Background(t.StatusBg).Padding(0, 1).Bold(true)
+
+	ErrorMessageStyle = fg(t.Error).Bold(true)
+	InfoMessageStyle = fg(t.Success).Bold(true)
+	WarningMessageStyle = fg(t.Warning).Bold(true)
+
+	ErrorStyle = fg(t.Error)
+	WarningStyle = fg(t.Warning)
+	InfoStyle = fg(t.Info)
+	AddedStyle = fg(t.Success)
+	RemovedStyle = fg(t.Error)
+
+	InactiveTabStyle = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(0, 1).BorderForeground(t.Border)
+	ActiveTabStyle = InactiveTabStyle.BorderBottom(false).Bold(true)
+
+	RepoPreviewStyle = lipgloss.NewStyle().Padding(0, 0).Border(lipgloss.RoundedBorder()).BorderForeground(t.Preview).MarginLeft(1)
+
+	ModalBoxStyle = lipgloss.NewStyle().
+		Padding(1, 2).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(t.Highlight).
+		Background(t.ModalBg).
+		Align(lipgloss.Center)
+
+	HelpBoxStyle = lipgloss.NewStyle().
+		Padding(1, 2).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(t.Highlight)
+
+	LabelStyle = fg(t.Text).Bold(true)
+	InactiveTextStyle = fg(t.Muted)
+	FocusedTextStyle = fg(t.Focused).Bold(true)
+	FieldBlockStyle = lipgloss.NewStyle().Padding(0, 1)
+	DimStyle = fg(t.Faint)
+
+	TextInputStyleEditingFocused = textinput.StyleState{Prompt: fg(t.InputPrompt), Text: fg(t.InputText)}
+	TextInputStyleEditingBlurred = textinput.StyleState{Prompt: fg(t.InputBlurredPrompt), Text: fg(t.InputBlurredText)}
+	TextInputStyleNavigationFocused = textinput.StyleState{Prompt: fg(t.InputNavigation), Text: fg(t.InputNavigation)}
+	TextInputStyleNavigationBlurred = textinput.StyleState{Prompt: fg(t.InputNavBlurred), Text: fg(t.InputNavBlurred)}
+}
+
+// TextInputStyles returns the styles of a new text input
+func TextInputStyles() textinput.Styles {
+	s := textinput.DefaultStyles(Current.Dark)
+	s.Focused = TextInputStyleEditingFocused
+	s.Blurred = TextInputStyleEditingBlurred
+	return s
 }
 
-var TextInputStyleEditingBlurred = textinput.StyleState{
-	Prompt: lipgloss.NewStyle().Foreground(lipgloss.Color("240")),
-	Text:   lipgloss.NewStyle().Foreground(lipgloss.Color("245")),
+// ListStyles returns the styles of a new list, the title uses the accent color
+func ListStyles() list.Styles {
+	s := list.DefaultStyles(Current.Dark)
+	s.Title = s.Title.Background(Current.Accent).Foreground(Current.ModalBg)
+	return s
 }
 
-var TextInputStyleNavigationFocused = textinput.StyleState{
-	Prompt: lipgloss.NewStyle().Foreground(lipgloss.Color("81")),
-	Text:   lipgloss.NewStyle().Foreground(lipgloss.Color("81")),
+// ListDelegate returns the delegate rendering the items of a list, the selected
+// item uses the accent color
+func ListDelegate() list.DefaultDelegate {
+	d := list.NewDefaultDelegate()
+	d.Styles = list.NewDefaultItemStyles(Current.Dark)
+	d.Styles.SelectedTitle = d.Styles.SelectedTitle.Foreground(Current.Accent).BorderForeground(Current.Accent)
+	d.Styles.SelectedDesc = d.Styles.SelectedDesc.Foreground(Current.Focused).BorderForeground(Current.Accent)
+	return d
 }
 
-var TextInputStyleNavigationBlurred = textinput.StyleState{
-	Prompt: lipgloss.NewStyle().Foreground(lipgloss.Color("238")),
-	Text:   lipgloss.NewStyle().Foreground(lipgloss.Color("238")),
+// HelpStyles returns the styles of the keys in the help
+func HelpStyles() help.Styles {
+	s := help.DefaultStyles(Current.Dark)
+	s.FullKey = s.FullKey.Foreground(Current.Text)
+	s.FullDesc = s.FullDesc.Foreground(Current.Muted)
+	return s
 }
 
 func StyleModalBox(content string, termWidth, termHeight int) string {
@@ -0,0 +1,197 @@
+package style
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss/v2"
+)
+
+// Theme is a named palette the styles are built from
+type Theme struct {
+	Name string
+	// Dark is set for the palettes meant for a dark background, the bubbles
+	// (lists, help) use their dark styles then
+	Dark bool
+
+	Text    color.Color // labels
+	Focused color.Color // the focused field
+	Accent  color.Color // the menu cursor and the list titles
+	Muted   color.Color // the inactive fields
+	Faint   color.Color // the dimmed background and the fields that can't be edited
+
+	Border    color.Color // the tabs
+	Highlight color.Color // the borders of the modals and the help
+	Preview   color.Color // the border of the previews
+	ModalBg   color.Color
+
+	StatusBg         color.Color
+	StatusEditing    color.Color
+	StatusNavigation color.Color
+
+	Error   color.Color
+	Warning color.Color
+	Info    color.Color
+	Success color.Color
+
+	// the text inputs
+	InputPrompt        color.Color
+	InputText          color.Color
+	InputBlurredPrompt color.Color
+	InputBlurredText   color.Color
+	InputNavigation    color.Color
+	InputNavBlurred    color.Color
+}
+
+// ThemeAuto picks the dark or the light theme by the background of the terminal
+const ThemeAuto = "auto"
+
+// ThemeNames are the names of the themes accepted by Lookup
+var ThemeNames = []string{ThemeAuto, "dark", "light", "high-contrast"}
+
+// Lookup returns the theme by its name, an empty name is ThemeAuto. Dark tells
+// the background of the terminal, the auto and the high-contrast themes follow it.
+func Lookup(name string, dark bool) (Theme, error) {
+	switch name {
+	case "", ThemeAuto:
+		if dark {
+			return DarkTheme, nil
+		}
+		return LightTheme, nil
+	case "dark":
+		return DarkTheme, nil
+	case "light":
+		return LightTheme, nil
+	case "high-contrast":
+		if dark {
+			return HighContrastDarkTheme, nil
+		}
+		return HighContrastLightTheme, nil
+	}
+	return Theme{}, fmt.Errorf("unknown theme %q, expected one of %s", name, strings.Join(ThemeNames, ", "))
+}
+
+var DarkTheme = Theme{
+	Name:    "dark",
+	Dark:    true,
+	Text:    lipgloss.Color("7"),
+	Focused: lipgloss.Color("10"), // bright green
+	Accent:  lipgloss.Color("#25A065"),
+	Muted:   lipgloss.Color("#888"),
+	Faint:   lipgloss.Color("#555"),
+
+	Border:    lipgloss.Color("240"),
+	Highlight: lipgloss.Color("63"),
+	Preview:   lipgloss.Color("#4A32DE"),
+	ModalBg:   lipgloss.Color("234"),
+
+	StatusBg:         lipgloss.Color("235"),
+	StatusEditing:    lipgloss.Color("#04B575"),
+	StatusNavigation: lipgloss.Color("#436f94"),
+
+	Error:   lipgloss.Color("#FF5F5F"),
+	Warning: lipgloss.Color("#FFA500"),
+	Info:    lipgloss.Color("#5FD7FF"),
+	Success: lipgloss.Color("#04B575"),
+
+	InputPrompt:        lipgloss.Color("205"),
+	InputText:          lipgloss.Color("229"),
+	InputBlurredPrompt: lipgloss.Color("240"),
+	InputBlurredText:   lipgloss.Color("245"),
+	InputNavigation:    lipgloss.Color("81"),
+	InputNavBlurred:    lipgloss.Color("238"),
+}
+
+var LightTheme = Theme{
+	Name:    "light",
+	Text:    lipgloss.Color("#1F2328"),
+	Focused: lipgloss.Color("#116329"),
+	Accent:  lipgloss.Color("#1A7F37"),
+	Muted:   lipgloss.Color("#6E7781"),
+	Faint:   lipgloss.Color("#A0A8B0"),
+
+	Border:    lipgloss.Color("#8C959F"),
+	Highlight: lipgloss.Color("#8250DF"),
+	Preview:   lipgloss.Color("#4A32DE"),
+	ModalBg:   lipgloss.Color("#F6F8FA"),
+
+	StatusBg:         lipgloss.Color("#EAEEF2"),
+	StatusEditing:    lipgloss.Color("#1A7F37"),
+	StatusNavigation: lipgloss.Color("#0550AE"),
+
+	Error:   lipgloss.Color("#CF222E"),
+	Warning: lipgloss.Color("#9A6700"),
+	Info:    lipgloss.Color("#0969DA"),
+	Success: lipgloss.Color("#1A7F37"),
+
+	InputPrompt:        lipgloss.Color("#BF3989"),
+	InputText:          lipgloss.Color("#1F2328"),
+	InputBlurredPrompt: lipgloss.Color("#8C959F"),
+	InputBlurredText:   lipgloss.Color("#6E7781"),
+	InputNavigation:    lipgloss.Color("#0969DA"),
+	InputNavBlurred:    lipgloss.Color("#A0A8B0"),
+}
+
+// HighContrastDarkTheme uses the pure colors on a dark background
+var HighContrastDarkTheme = Theme{
+	Name:    "high-contrast",
+	Dark:    true,
+	Text:    lipgloss.Color("#FFFFFF"),
+	Focused: lipgloss.Color("#FFFF00"),
+	Accent:  lipgloss.Color("#00FF00"),
+	Muted:   lipgloss.Color("#D0D0D0"),
+	Faint:   lipgloss.Color("#A8A8A8"),
+
+	Border:    lipgloss.Color("#FFFFFF"),
+	Highlight: lipgloss.Color("#00FFFF"),
+	Preview:   lipgloss.Color("#00FFFF"),
+	ModalBg:   lipgloss.Color("#000000"),
+
+	StatusBg:         lipgloss.Color("#000000"),
+	StatusEditing:    lipgloss.Color("#00FF00"),
+	StatusNavigation: lipgloss.Color("#00FFFF"),
+
+	Error:   lipgloss.Color("#FF5555"),
+	Warning: lipgloss.Color("#FFFF00"),
+	Info:    lipgloss.Color("#00FFFF"),
+	Success: lipgloss.Color("#00FF00"),
+
+	InputPrompt:        lipgloss.Color("#FFFF00"),
+	InputText:          lipgloss.Color("#FFFFFF"),
+	InputBlurredPrompt: lipgloss.Color("#D0D0D0"),
+	InputBlurredText:   lipgloss.Color("#D0D0D0"),
+	InputNavigation:    lipgloss.Color("#00FFFF"),
+	InputNavBlurred:    lipgloss.Color("#A8A8A8"),
+}
+
+// HighContrastLightTheme uses the dark pure colors on a light background
+var HighContrastLightTheme = Theme{
+	Name:    "high-contrast",
+	Text:    lipgloss.Color("#000000"),
+	Focused: lipgloss.Color("#0000AF"),
+	Accent:  lipgloss.Color("#005F00"),
+	Muted:   lipgloss.Color("#303030"),
+	Faint:   lipgloss.Color("#585858"),
+
+	Border:    lipgloss.Color("#000000"),
+	Highlight: lipgloss.Color("#0000AF"),
+	Preview:   lipgloss.Color("#0000AF"),
+	ModalBg:   lipgloss.Color("#FFFFFF"),
+
+	StatusBg:         lipgloss.Color("#FFFFFF"),
+	StatusEditing:    lipgloss.Color("#005F00"),
+	StatusNavigation: lipgloss.Color("#0000AF"),
+
+	Error:   lipgloss.Color("#AF0000"),
+	Warning: lipgloss.Color("#875F00"),
+	Info:    lipgloss.Color("#0000AF"),
+	Success: lipgloss.Color("#005F00"),
+
+	InputPrompt:        lipgloss.Color("#AF005F"),
+	InputText:          lipgloss.Color("#000000"),
+	InputBlurredPrompt: lipgloss.Color("#303030"),
+	InputBlurredText:   lipgloss.Color("#303030"),
+	InputNavigation:    lipgloss.Color("#0000AF"),
+	InputNavBlurred:    lipgloss.Color("#585858"),
+}
@@ -3,6 +3,7 @@ package util
 import (
 	"reflect"
 	"strings"
+	"unicode"
 )
 
 func PtrToBool(v reflect.Value) bool {
@@ -40,3 +41,28 @@ func ParseTag(tag string) map[string]string {
 	}
 	return out
 }
+
+// Humanize turns a camelCase identifier into lower case words,
+// e.g. "requireSignedCommits" becomes "require signed commits"
+func Humanize(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte(' ')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// YAMLName returns the yaml key of the struct field
+func YAMLName(sf reflect.StructField) string {
+	name, _, _ := strings.Cut(sf.Tag.Get("yaml"), ",")
+	if name == "" {
+		return strings.ToLower(sf.Name[:1]) + sf.Name[1:]
+	}
+	return name
+}
@@ -0,0 +1,189 @@
+package versions
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/artemlive/gh-crossplane/internal/schema"
+	"gopkg.in/yaml.v3"
+)
+
+// v1beta1 turns the lists the provider uses for nested blocks, which never have
+// more than one item, into objects, e.g.
+//
+//	requiredStatusChecks:        requiredStatusChecks:
+//	  - strict: true        =>     strict: true
+func init() {
+	Register(Version{
+		Name:   "v1beta1",
+		Up:     func(doc *yaml.Node) error { return eachBlock(doc, false, unwrapBlock) },
+		Down:   func(doc *yaml.Node) error { return eachBlock(doc, true, wrapBlock) },
+		Schema: unwrapBlockSchemas,
+	})
+}
+
+// v1beta1Blocks are the single item lists of v1alpha1, relative to the spec of the group
+// or of a repository. A parent comes before its children.
+var v1beta1Blocks = []string{
+	"securityAndAnalysis",
+	"securityAndAnalysis.advancedSecurity",
+	"securityAndAnalysis.secretScanning",
+	"securityAndAnalysis.secretScanningPushProtection",
+	"protections[].requiredStatusChecks",
+	"protections[].requiredPullRequestReviews",
+}
+
+// eachBlock calls fn with the mapping holding every block of the group spec
+// and of the repository specs, the children first if reverse is set
+func eachBlock(doc *yaml.Node, reverse bool, fn func(parent *yaml.Node, key, path string) error) error {
+	spec := value(root(doc), "spec")
+	if spec == nil {
+		return nil
+	}
+	type specAt struct {
+		path string
+		node *yaml.Node
+	}
+	specs := []specAt{{"spec", spec}}
+	if repos := value(spec, "repositories"); repos != nil && repos.Kind == yaml.SequenceNode {
+		for i, repo := range repos.Content {
+			specs = append(specs, specAt{fmt.Sprintf("spec.repositories[%d]", i), repo})
+		}
+	}
+
+	blocks := slices.Clone(v1beta1Blocks)
+	if reverse {
+		slices.Reverse(blocks)
+	}
+	for _, s := range specs {
+		for _, block := range blocks {
+			if err := walk(s.node, s.path, strings.Split(block, "."), fn); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// walk follows the path from the node, a part ending with [] goes into every item of the list
+func walk(n *yaml.Node, at string, path []string, fn func(parent *yaml.Node, key, path string) error) error {
+	if n == nil || n.Kind != yaml.MappingNode {
+		return nil
+	}
+	key, each := strings.CutSuffix(path[0], "[]")
+	if len(path) == 1 {
+		if value(n, key) == nil {
+			return nil
+		}
+		return fn(n, key, at+"."+key)
+	}
+
+	child := value(n, key)
+	if !each {
+		return walk(child, at+"."+key, path[1:], fn)
+	}
+	if child == nil || child.Kind != yaml.SequenceNode {
+		return nil
+	}
+	for i, item := range child.Content {
+		if err := walk(item, fmt.Sprintf("%s.%s[%d]", at, key, i), path[1:], fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// unwrapBlock replaces the single item list with its item
+func unwrapBlock(parent *yaml.Node, key, path string) error {
+	list := value(parent, key)
+	if list.Kind != yaml.SequenceNode {
+		return nil // already converted
+	}
+	switch len(list.Content) {
+	case 0:
+		deleteKey(parent, key)
+	case 1:
+		item := list.Content[0]
+		item.HeadComment = joinComments(list.HeadComment, item.HeadComment)
+		item.LineComment = joinComments(list.LineComment, item.LineComment)
+		item.FootComment = joinComments(item.FootComment, list.FootComment)
+		setValue(parent, key, item)
+	default:
+		return fmt.Errorf("%s has %d items, only one is allowed", path, len(list.Content))
+	}
+	return nil
+}
+
+// wrapBlock replaces the object with a list holding it
+func wrapBlock(parent *yaml.Node, key, _ string) error {
+	item := value(parent, key)
+	if item.Kind != yaml.MappingNode {
+		return nil // already converted
+	}
+	setValue(parent, key, &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Content: []*yaml.Node{item}})
+	return nil
+}
+
+func unwrapBlockSchemas(spec *schema.Schema) {
+	specs := []*schema.Schema{spec}
+	if repos := spec.Properties["repositories"]; repos != nil && repos.Items != nil {
+		specs = append(specs, repos.Items)
+	}
+	for _, s := range specs {
+		for _, block := range v1beta1Blocks {
+			unwrapSchema(s, strings.Split(block, "."))
+		}
+	}
+}
+
+func unwrapSchema(s *schema.Schema, path []string) {
+	if s == nil {
+		return
+	}
+	key, each := strings.CutSuffix(path[0], "[]")
+	prop := s.Properties[key]
+	if prop == nil {
+		return
+	}
+	if len(path) == 1 {
+		if prop.Type == "array" && prop.Items != nil {
+			item := *prop.Items
+			item.Description = prop.Description
+			s.Properties[key] = &item
+		}
+		return
+	}
+	if each {
+		prop = prop.Items
+	}
+	unwrapSchema(prop, path[1:])
+}
+
+func setValue(mapping *yaml.Node, key string, n *yaml.Node) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			mapping.Content[i+1] = n
+			return
+		}
+	}
+}
+
+func deleteKey(mapping *yaml.Node, key string) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			mapping.Content = slices.Delete(mapping.Content, i, i+2)
+			return
+		}
+	}
+}
+
+func joinComments(a, b string) string {
+	switch {
+	case a == "":
+		return b
+	case b == "":
+		return a
+	}
+	return a + "\n" + b
+}
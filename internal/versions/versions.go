@@ -0,0 +1,136 @@
+// Package versions knows the apiVersions of the RepositoriesGroup claims
+// and converts the manifests between them.
+//
+// The domain types are the internal form every version is converted to on load
+// and from on save. They follow the shape of the provider resources, which is
+// the shape of v1alpha1. The conversions work on yaml nodes, so the comments
+// and the fields the domain types don't know about survive them.
+package versions
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/artemlive/gh-crossplane/internal/schema"
+	"gopkg.in/yaml.v3"
+)
+
+// Internal is the version the domain types correspond to
+const Internal = "v1alpha1"
+
+// Version is a registered apiVersion. Up converts a document of the previous
+// version to this one and Down back, Schema does the same for the spec schema.
+// They are nil for the internal version.
+type Version struct {
+	Name   string
+	Up     func(doc *yaml.Node) error
+	Down   func(doc *yaml.Node) error
+	Schema func(spec *schema.Schema)
+}
+
+// registered versions from the oldest to the latest
+var registry = []Version{{Name: Internal}}
+
+// Register adds the version after the registered ones
+func Register(v Version) {
+	registry = append(registry, v)
+}
+
+// Names returns the registered versions from the oldest to the latest
+func Names() []string {
+	var names []string
+	for _, v := range registry {
+		names = append(names, v.Name)
+	}
+	return names
+}
+
+// Latest returns the newest registered version
+func Latest() string {
+	return registry[len(registry)-1].Name
+}
+
+// Of returns the version part of the apiVersion, e.g. "v1alpha1"
+// for "github.platform.crossplane.io/v1alpha1"
+func Of(apiVersion string) string {
+	_, version, _ := strings.Cut(apiVersion, "/")
+	return version
+}
+
+// WithVersion replaces the version part of the apiVersion
+func WithVersion(apiVersion, version string) string {
+	group, _, _ := strings.Cut(apiVersion, "/")
+	return group + "/" + version
+}
+
+// Known reports whether the version is registered
+func Known(version string) bool {
+	return index(version) >= 0
+}
+
+func index(version string) int {
+	return slices.IndexFunc(registry, func(v Version) bool { return v.Name == version })
+}
+
+// Convert converts the document from one version to the other, one version at a time,
+// and updates its apiVersion.
+func Convert(doc *yaml.Node, from, to string) error {
+	i, j := index(from), index(to)
+	switch {
+	case i < 0:
+		return fmt.Errorf("unsupported version %s, known versions are %s", from, strings.Join(Names(), ", "))
+	case j < 0:
+		return fmt.Errorf("unsupported version %s, known versions are %s", to, strings.Join(Names(), ", "))
+	}
+
+	for ; i < j; i++ {
+		if err := registry[i+1].Up(doc); err != nil {
+			return fmt.Errorf("convert to %s: %w", registry[i+1].Name, err)
+		}
+	}
+	for ; i > j; i-- {
+		if err := registry[i].Down(doc); err != nil {
+			return fmt.Errorf("convert to %s: %w", registry[i-1].Name, err)
+		}
+	}
+
+	if apiVersion := value(root(doc), "apiVersion"); apiVersion != nil {
+		apiVersion.Value = WithVersion(apiVersion.Value, to)
+	}
+	return nil
+}
+
+// SpecSchema returns the schema of the spec in the version
+func SpecSchema(version string) (*schema.Schema, error) {
+	j := index(version)
+	if j < 0 {
+		return nil, fmt.Errorf("unsupported version %s, known versions are %s", version, strings.Join(Names(), ", "))
+	}
+	spec := schema.Spec()
+	for i := 1; i <= j; i++ {
+		registry[i].Schema(spec)
+	}
+	return spec, nil
+}
+
+// root returns the top level mapping of the document
+func root(doc *yaml.Node) *yaml.Node {
+	if doc.Kind == yaml.DocumentNode && len(doc.Content) > 0 {
+		return doc.Content[0]
+	}
+	return doc
+}
+
+// value returns the value of the key in the mapping, nil if missing
+func value(mapping *yaml.Node, key string) *yaml.Node {
+	if mapping == nil || mapping.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
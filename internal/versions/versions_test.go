@@ -0,0 +1,128 @@
+package versions
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+const v1alpha1Doc = `apiVersion: github.platform.crossplane.io/v1alpha1
+spec:
+  securityAndAnalysis:
+    - advancedSecurity:
+        - status: enabled
+  repositories:
+    - name: api
+      protections:
+        - name: main
+          requiredStatusChecks:
+            - strict: true
+          # the reviews of main
+          requiredPullRequestReviews:
+            - requiredApprovingReviewCount: 1
+`
+
+const v1beta1Doc = `apiVersion: github.platform.crossplane.io/v1beta1
+spec:
+  securityAndAnalysis:
+    advancedSecurity:
+      status: enabled
+  repositories:
+    - name: api
+      protections:
+        - name: main
+          requiredStatusChecks:
+            strict: true
+          # the reviews of main
+          requiredPullRequestReviews:
+            requiredApprovingReviewCount: 1
+`
+
+func TestConvert(t *testing.T) {
+	for _, tc := range []struct {
+		from, to string
+		in, want string
+	}{
+		{"v1alpha1", "v1beta1", v1alpha1Doc, v1beta1Doc},
+		{"v1beta1", "v1alpha1", v1beta1Doc, v1alpha1Doc},
+		{"v1beta1", "v1beta1", v1beta1Doc, v1beta1Doc},
+	} {
+		t.Run(tc.from+"-"+tc.to, func(t *testing.T) {
+			doc := parse(t, tc.in)
+			if err := Convert(doc, tc.from, tc.to); err != nil {
+				t.Fatal(err)
+			}
+			if got, want := encode(t, doc), encode(t, parse(t, tc.want)); got != want {
+				t.Errorf("converted to\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestConvertErrors(t *testing.T) {
+	doc := parse(t, v1alpha1Doc)
+	if err := Convert(doc, "v2", Internal); err == nil || !strings.Contains(err.Error(), "unsupported version v2") {
+		t.Errorf("Convert from v2 = %v, want unsupported version", err)
+	}
+
+	// v1beta1 allows only one item in a block
+	doc = parse(t, strings.Replace(v1alpha1Doc, "        - status: enabled\n", "        - status: enabled\n        - status: disabled\n", 1))
+	err := Convert(doc, "v1alpha1", "v1beta1")
+	if err == nil || !strings.Contains(err.Error(), "spec.securityAndAnalysis.advancedSecurity has 2 items") {
+		t.Errorf("Convert a block with two items = %v, want the path of the block", err)
+	}
+}
+
+func TestOf(t *testing.T) {
+	for apiVersion, want := range map[string]string{
+		"github.platform.crossplane.io/v1beta1": "v1beta1",
+		"":                                      "",
+	} {
+		if got := Of(apiVersion); got != want {
+			t.Errorf("Of(%q) = %q, want %q", apiVersion, got, want)
+		}
+	}
+	if got := WithVersion("github.platform.crossplane.io/v1alpha1", "v1beta1"); got != "github.platform.crossplane.io/v1beta1" {
+		t.Errorf("WithVersion = %s", got)
+	}
+}
+
+func TestSpecSchema(t *testing.T) {
+	alpha, err := SpecSchema("v1alpha1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	beta, err := SpecSchema("v1beta1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := alpha.Properties["securityAndAnalysis"].Type; got != "array" {
+		t.Errorf("v1alpha1 securityAndAnalysis type = %s, want array", got)
+	}
+	if got := beta.Properties["securityAndAnalysis"].Type; got != "object" {
+		t.Errorf("v1beta1 securityAndAnalysis type = %s, want object", got)
+	}
+	checks := beta.Properties["repositories"].Items.Properties["protections"].Items.Properties["requiredStatusChecks"]
+	if checks.Type != "object" {
+		t.Errorf("v1beta1 requiredStatusChecks type = %s, want object", checks.Type)
+	}
+}
+
+func parse(t *testing.T, content string) *yaml.Node {
+	t.Helper()
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(content), &doc); err != nil {
+		t.Fatal(err)
+	}
+	return &doc
+}
+
+func encode(t *testing.T, doc *yaml.Node) string {
+	t.Helper()
+	out, err := yaml.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out)
+}